@@ -1,185 +0,0 @@
--- MySQL dump 10.13  Distrib 8.0.17, for osx10.14 (x86_64)
---
--- Host: localhost    Database: bennjerry
--- ------------------------------------------------------
--- Server version	8.0.17
-
-/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
-/*!40101 SET @OLD_CHARACTER_SET_RESULTS=@@CHARACTER_SET_RESULTS */;
-/*!40101 SET @OLD_COLLATION_CONNECTION=@@COLLATION_CONNECTION */;
-/*!50503 SET NAMES utf8mb4 */;
-/*!40103 SET @OLD_TIME_ZONE=@@TIME_ZONE */;
-/*!40103 SET TIME_ZONE='+00:00' */;
-/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;
-/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;
-/*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
-/*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
-
---
--- Table structure for table `dietarycertification`
---
-
-DROP TABLE IF EXISTS `dietarycertification`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `dietarycertification` (
-  `id` int(11) NOT NULL AUTO_INCREMENT,
-  `name` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
-  PRIMARY KEY (`id`),
-  UNIQUE KEY `name` (`name`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
-/*!40101 SET character_set_client = @saved_cs_client */;
-
---
--- Dumping data for table `dietarycertification`
---
-
-LOCK TABLES `dietarycertification` WRITE;
-/*!40000 ALTER TABLE `dietarycertification` DISABLE KEYS */;
-/*!40000 ALTER TABLE `dietarycertification` ENABLE KEYS */;
-UNLOCK TABLES;
-
---
--- Table structure for table `ingredient`
---
-
-DROP TABLE IF EXISTS `ingredient`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `ingredient` (
-  `id` int(11) NOT NULL AUTO_INCREMENT,
-  `name` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
-  PRIMARY KEY (`id`),
-  UNIQUE KEY `name` (`name`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
-/*!40101 SET character_set_client = @saved_cs_client */;
-
---
--- Dumping data for table `ingredient`
---
-
-LOCK TABLES `ingredient` WRITE;
-/*!40000 ALTER TABLE `ingredient` DISABLE KEYS */;
-/*!40000 ALTER TABLE `ingredient` ENABLE KEYS */;
-UNLOCK TABLES;
-
---
--- Table structure for table `product`
---
-
-DROP TABLE IF EXISTS `product`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `product` (
-  `id` int(11) NOT NULL AUTO_INCREMENT,
-  `name` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
-  `description` text COLLATE utf8mb4_general_ci,
-  `story` text COLLATE utf8mb4_general_ci,
-  `image_closed` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
-  `image_opened` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
-  `allergy` text COLLATE utf8mb4_general_ci,
-  `dietary_certification_id` int(11) DEFAULT NULL,
-  `product_id` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
-  `is_inactive` tinyint(1) DEFAULT '0',
-  PRIMARY KEY (`id`),
-  UNIQUE KEY `product_id` (`product_id`),
-  KEY `dietary_certification_id` (`dietary_certification_id`),
-  CONSTRAINT `product_ibfk_1` FOREIGN KEY (`dietary_certification_id`) REFERENCES `dietarycertification` (`id`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
-/*!40101 SET character_set_client = @saved_cs_client */;
-
---
--- Dumping data for table `product`
---
-
-LOCK TABLES `product` WRITE;
-/*!40000 ALTER TABLE `product` DISABLE KEYS */;
-/*!40000 ALTER TABLE `product` ENABLE KEYS */;
-UNLOCK TABLES;
-
---
--- Table structure for table `product_ingredient`
---
-
-DROP TABLE IF EXISTS `product_ingredient`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `product_ingredient` (
-  `product_id` int(11) DEFAULT NULL,
-  `ingredient_id` int(11) DEFAULT NULL,
-  KEY `product_id` (`product_id`),
-  KEY `ingredient_id` (`ingredient_id`),
-  CONSTRAINT `product_ingredient_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`),
-  CONSTRAINT `product_ingredient_ibfk_2` FOREIGN KEY (`ingredient_id`) REFERENCES `ingredient` (`id`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
-/*!40101 SET character_set_client = @saved_cs_client */;
-
---
--- Dumping data for table `product_ingredient`
---
-
-LOCK TABLES `product_ingredient` WRITE;
-/*!40000 ALTER TABLE `product_ingredient` DISABLE KEYS */;
-/*!40000 ALTER TABLE `product_ingredient` ENABLE KEYS */;
-UNLOCK TABLES;
-
---
--- Table structure for table `product_sourcingvalue`
---
-
-DROP TABLE IF EXISTS `product_sourcingvalue`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `product_sourcingvalue` (
-  `product_id` int(11) DEFAULT NULL,
-  `sourcingvalue_id` int(11) DEFAULT NULL,
-  KEY `product_id` (`product_id`),
-  KEY `sourcingvalue_id` (`sourcingvalue_id`),
-  CONSTRAINT `product_sourcingvalue_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`),
-  CONSTRAINT `product_sourcingvalue_ibfk_2` FOREIGN KEY (`sourcingvalue_id`) REFERENCES `sourcingvalue` (`id`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
-/*!40101 SET character_set_client = @saved_cs_client */;
-
---
--- Dumping data for table `product_sourcingvalue`
---
-
-LOCK TABLES `product_sourcingvalue` WRITE;
-/*!40000 ALTER TABLE `product_sourcingvalue` DISABLE KEYS */;
-/*!40000 ALTER TABLE `product_sourcingvalue` ENABLE KEYS */;
-UNLOCK TABLES;
-
---
--- Table structure for table `sourcingvalue`
---
-
-DROP TABLE IF EXISTS `sourcingvalue`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `sourcingvalue` (
-  `id` int(11) NOT NULL AUTO_INCREMENT,
-  `name` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
-  PRIMARY KEY (`id`),
-  UNIQUE KEY `name` (`name`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
-/*!40101 SET character_set_client = @saved_cs_client */;
-
---
--- Dumping data for table `sourcingvalue`
---
-
-LOCK TABLES `sourcingvalue` WRITE;
-/*!40000 ALTER TABLE `sourcingvalue` DISABLE KEYS */;
-/*!40000 ALTER TABLE `sourcingvalue` ENABLE KEYS */;
-UNLOCK TABLES;
-/*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
-
-/*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
-/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
-/*!40014 SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS */;
-/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
-/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
-/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
-/*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
-
--- Dump completed on 2019-10-22 13:49:16
@@ -1,24 +1,176 @@
 package main
 
 import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
 	"github.com/gin-gonic/gin"
 
+	"admin"
 	"bennjerry"
+	"bennjerry/model"
 	"constants"
+	"health"
+	"imagestore"
+	"janitor"
 	"logger"
+	"metrics"
+	"migration"
 	"mysqlc"
+	"normalizer"
+	"scheduler"
+	"utils"
 )
 
 func main() {
 	// connecting to mysql
 	mysqlc.Init()
 	logger.Init()
+	// reopening log file and reloading log level on SIGHUP
+	logger.WatchSignals()
+	// refusing to start on a database with pending schema migrations
+	checkSchemaVersion()
+	// loading synonyms used to match names of sourcing values, ingredients and dietary certifications
+	loadSynonyms()
+	// setting up the storage of uploaded images
+	initImageStore()
 
-	// Creating group route for bennjerry
 	mainRouter := gin.Default()
+	// attaching a request id and request scoped logger to every request
+	mainRouter.Use(logger.RequestTracer)
+	// counting requests and measuring their latency by route and status
+	mainRouter.Use(metrics.Instrument)
+
+	// Exposing metrics to be scraped by prometheus
+	mainRouter.GET("/metrics", metrics.Handler)
+
+	// Serving uploaded images kept in a folder of the server, an object store serves them by itself
+	if localStorage, isLocal := imagestore.Store.(*imagestore.LocalStorage); isLocal {
+		mainRouter.Static(constants.ImageURLPath, localStorage.Directory)
+	}
+
+	// Exposing liveness and readiness (with dependency checks) for the orchestrator
+	mainRouter.GET("/healthz", health.Liveness)
+	mainRouter.GET("/readyz", health.Readiness)
+
+	// Creating group route for bennjerry
 	benNJerryGroup := mainRouter.Group("/bennjerry")
 	bennjerry.RoutesBenNJerry(benNJerryGroup)
 
-	// starting the server
-	mainRouter.Run(constants.ServerHost + ":" + constants.ServerPort)
+	// Creating group route for apis across ice cream products, e.g. filtering them
+	catalogGroup := mainRouter.Group("/catalog")
+	bennjerry.RoutesCatalog(catalogGroup)
+
+	// Creating group route for admin operations
+	adminGroup := mainRouter.Group("/admin")
+	admin.RoutesAdmin(adminGroup)
+
+	// deleting unused sourcing values, ingredients and dietary certifications in background
+	janitor.Start(utils.GetEnvSeconds(constants.JanitorIntervalEnvVarName, constants.JanitorInterval),
+		os.Getenv(constants.JanitorDryRunEnvVarName) == "1")
+
+	// emitting events of products going live or expiring as per their schedule, in background
+	scheduler.Init()
+	scheduler.Start(utils.GetEnvSeconds(constants.SchedulerIntervalEnvVarName, constants.SchedulerInterval))
+
+	// starting the server with timeouts, so that slow clients can't hold connections forever
+	server := &http.Server{
+		Addr:    constants.ServerHost + ":" + constants.ServerPort,
+		Handler: mainRouter,
+		ReadHeaderTimeout: utils.GetEnvSeconds(constants.ServerReadHeaderTimeoutEnvVarName,
+			constants.ServerReadHeaderTimeout),
+		ReadTimeout:  utils.GetEnvSeconds(constants.ServerReadTimeoutEnvVarName, constants.ServerReadTimeout),
+		WriteTimeout: utils.GetEnvSeconds(constants.ServerWriteTimeoutEnvVarName, constants.ServerWriteTimeout),
+		IdleTimeout:  utils.GetEnvSeconds(constants.ServerIdleTimeoutEnvVarName, constants.ServerIdleTimeout),
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.ZaloraStatsLogger.Error(constants.ServerLogBucketName, "main", constants.ServerStartErrorMessage,
+				err.Error())
+			panic(err.Error())
+		}
+	}()
+
+	// waiting for SIGTERM (sent by orchestrator) or SIGINT (ctrl+c) to shut down
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	shutDown(server)
+}
+
+func checkSchemaVersion() {
+	/*
+		To compare schema version of the database with the latest migration known to the server
+		Raising panic, if migrations can't be loaded or are pending
+	*/
+	logIdentifier := "main.checkSchemaVersion"
+	if err := migration.Init(); err != nil {
+		logger.ZaloraStatsLogger.Error(constants.MigrationLogBucketName, logIdentifier,
+			constants.MigrationLoadErrorMessage, err.Error())
+		panic(err.Error())
+	}
+	version, err := migration.Check(context.Background(), mysqlc.MySqlDB)
+	if err != nil {
+		logger.ZaloraStatsLogger.Error(constants.MigrationLogBucketName, logIdentifier,
+			constants.MigrationVersionErrorMessage, err.Error())
+		panic(err.Error())
+	}
+	if version > migration.LatestVersion {
+		logger.ZaloraStatsLogger.Warn(constants.MigrationLogBucketName, logIdentifier,
+			constants.MigrationAheadMessage+strconv.Itoa(version))
+	}
+}
+
+func loadSynonyms() {
+	/*
+		To load the synonym map of vocabulary names
+		Raising panic, if the synonyms file exists but can't be read
+	*/
+	if err := normalizer.Init(); err != nil {
+		logger.ZaloraStatsLogger.Error(constants.NormalizerLogBucketName, "main.loadSynonyms",
+			constants.NormalizerSynonymsErrorMessage, err.Error())
+		panic(err.Error())
+	}
+}
+
+func initImageStore() {
+	/*
+		To set up the storage images are uploaded to
+		Raising panic, if its folder can't be created
+	*/
+	if err := imagestore.Init(); err != nil {
+		logger.ZaloraStatsLogger.Error(constants.ImageStoreLogBucketName, "main.initImageStore",
+			constants.ImageStoreInitErrorMessage, err.Error())
+		panic(err.Error())
+	}
+}
+
+func shutDown(server *http.Server) {
+	/*
+		To gracefully stop the server within the shutdown timeout
+		Stops accepting connections, drains in-flight requests and open transactions,
+		then closes mysql connections and flushes the logger
+	*/
+	logIdentifier := "main.shutDown"
+	logger.ZaloraStatsLogger.Info(constants.ServerLogBucketName, logIdentifier, constants.ServerShutDownMessage)
+	ctx, cancel := context.WithTimeout(context.Background(),
+		utils.GetEnvSeconds(constants.ServerShutdownTimeoutEnvVarName, constants.ServerShutdownTimeout))
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.ZaloraStatsLogger.Error(constants.ServerLogBucketName, logIdentifier,
+			constants.ServerDrainErrorMessage, err.Error())
+	}
+	janitor.Stop()
+	scheduler.Stop()
+	if !model.WaitForTransactions(ctx) {
+		logger.ZaloraStatsLogger.Warn(constants.ServerLogBucketName, logIdentifier,
+			constants.ServerTransactionsDrainErrorMessage)
+	}
+	mysqlc.DBClosing()
+	logger.ZaloraStatsLogger.Info(constants.ServerLogBucketName, logIdentifier, constants.ServerStoppedMessage)
+	logger.ZaloraStatsLogger.Close()
 }
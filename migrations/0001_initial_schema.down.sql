@@ -0,0 +1,9 @@
+-- Dropping tables in reverse order of their foreign keys
+
+DROP TABLE IF EXISTS `product_revision`;
+DROP TABLE IF EXISTS `product_sourcingvalue`;
+DROP TABLE IF EXISTS `product_ingredient`;
+DROP TABLE IF EXISTS `product`;
+DROP TABLE IF EXISTS `sourcingvalue`;
+DROP TABLE IF EXISTS `ingredient`;
+DROP TABLE IF EXISTS `dietarycertification`;
@@ -0,0 +1,69 @@
+-- Schema of the catalog as it was loaded from the bennjerry.sql dump
+-- Tables are created only if missing, so that databases created from the dump can adopt migrations
+
+CREATE TABLE IF NOT EXISTS `dietarycertification` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `ingredient` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `sourcingvalue` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `product` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
+  `description` text COLLATE utf8mb4_general_ci,
+  `story` text COLLATE utf8mb4_general_ci,
+  `image_closed` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
+  `image_opened` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
+  `allergy` text COLLATE utf8mb4_general_ci,
+  `dietary_certification_id` int(11) DEFAULT NULL,
+  `product_id` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
+  `is_inactive` tinyint(1) DEFAULT '0',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `product_id` (`product_id`),
+  KEY `dietary_certification_id` (`dietary_certification_id`),
+  CONSTRAINT `product_ibfk_1` FOREIGN KEY (`dietary_certification_id`) REFERENCES `dietarycertification` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `product_ingredient` (
+  `product_id` int(11) DEFAULT NULL,
+  `ingredient_id` int(11) DEFAULT NULL,
+  KEY `product_id` (`product_id`),
+  KEY `ingredient_id` (`ingredient_id`),
+  CONSTRAINT `product_ingredient_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`),
+  CONSTRAINT `product_ingredient_ibfk_2` FOREIGN KEY (`ingredient_id`) REFERENCES `ingredient` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `product_sourcingvalue` (
+  `product_id` int(11) DEFAULT NULL,
+  `sourcingvalue_id` int(11) DEFAULT NULL,
+  KEY `product_id` (`product_id`),
+  KEY `sourcingvalue_id` (`sourcingvalue_id`),
+  CONSTRAINT `product_sourcingvalue_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`),
+  CONSTRAINT `product_sourcingvalue_ibfk_2` FOREIGN KEY (`sourcingvalue_id`) REFERENCES `sourcingvalue` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `product_revision` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `product_id` int(11) NOT NULL,
+  `revision` int(11) NOT NULL,
+  `snapshot` longtext COLLATE utf8mb4_general_ci NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `product_id_revision` (`product_id`,`revision`),
+  CONSTRAINT `product_revision_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
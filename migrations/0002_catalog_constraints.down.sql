@@ -0,0 +1,41 @@
+-- Restoring relation tables and foreign keys as they were in the initial schema
+
+ALTER TABLE `product_revision` DROP FOREIGN KEY `fk_product_revision_product`;
+ALTER TABLE `product_revision`
+  ADD CONSTRAINT `product_revision_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`);
+
+RENAME TABLE `product_sourcingvalue` TO `product_sourcingvalue_0002`;
+CREATE TABLE `product_sourcingvalue` (
+  `product_id` int(11) DEFAULT NULL,
+  `sourcingvalue_id` int(11) DEFAULT NULL,
+  KEY `product_id` (`product_id`),
+  KEY `sourcingvalue_id` (`sourcingvalue_id`),
+  CONSTRAINT `product_sourcingvalue_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`),
+  CONSTRAINT `product_sourcingvalue_ibfk_2` FOREIGN KEY (`sourcingvalue_id`) REFERENCES `sourcingvalue` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+INSERT INTO `product_sourcingvalue` (`product_id`, `sourcingvalue_id`)
+  SELECT `product_id`, `sourcingvalue_id` FROM `product_sourcingvalue_0002`;
+DROP TABLE `product_sourcingvalue_0002`;
+
+RENAME TABLE `product_ingredient` TO `product_ingredient_0002`;
+CREATE TABLE `product_ingredient` (
+  `product_id` int(11) DEFAULT NULL,
+  `ingredient_id` int(11) DEFAULT NULL,
+  KEY `product_id` (`product_id`),
+  KEY `ingredient_id` (`ingredient_id`),
+  CONSTRAINT `product_ingredient_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`),
+  CONSTRAINT `product_ingredient_ibfk_2` FOREIGN KEY (`ingredient_id`) REFERENCES `ingredient` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+INSERT INTO `product_ingredient` (`product_id`, `ingredient_id`)
+  SELECT `product_id`, `ingredient_id` FROM `product_ingredient_0002`;
+DROP TABLE `product_ingredient_0002`;
+
+ALTER TABLE `sourcingvalue` MODIFY `name` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL;
+ALTER TABLE `ingredient` MODIFY `name` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL;
+ALTER TABLE `dietarycertification` MODIFY `name` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL;
+
+ALTER TABLE `product` DROP FOREIGN KEY `fk_product_dietarycertification`;
+ALTER TABLE `product`
+  MODIFY `product_id` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
+  MODIFY `is_inactive` tinyint(1) DEFAULT '0',
+  ADD CONSTRAINT `product_ibfk_1` FOREIGN KEY (`dietary_certification_id`) REFERENCES `dietarycertification` (`id`);
@@ -0,0 +1,58 @@
+-- Composite primary keys and cascading foreign keys on relation tables, not null and unique columns on lookups
+-- Deleting a product deletes its relations and revisions, deleting a vocabulary entry in use is refused
+
+-- product_id is the public identifier of a product, so it can't be missing
+UPDATE `product` SET `product_id` = CONCAT('product-', `id`) WHERE `product_id` IS NULL;
+ALTER TABLE `product`
+  MODIFY `product_id` varchar(255) COLLATE utf8mb4_general_ci NOT NULL,
+  MODIFY `is_inactive` tinyint(1) NOT NULL DEFAULT '0',
+  DROP FOREIGN KEY `product_ibfk_1`;
+ALTER TABLE `product`
+  ADD CONSTRAINT `fk_product_dietarycertification` FOREIGN KEY (`dietary_certification_id`)
+    REFERENCES `dietarycertification` (`id`) ON DELETE SET NULL;
+
+-- Names of vocabulary entries are what products are matched on
+UPDATE `dietarycertification` SET `name` = CONCAT('dietarycertification-', `id`) WHERE `name` IS NULL;
+ALTER TABLE `dietarycertification` MODIFY `name` varchar(255) COLLATE utf8mb4_general_ci NOT NULL;
+UPDATE `ingredient` SET `name` = CONCAT('ingredient-', `id`) WHERE `name` IS NULL;
+ALTER TABLE `ingredient` MODIFY `name` varchar(255) COLLATE utf8mb4_general_ci NOT NULL;
+UPDATE `sourcingvalue` SET `name` = CONCAT('sourcingvalue-', `id`) WHERE `name` IS NULL;
+ALTER TABLE `sourcingvalue` MODIFY `name` varchar(255) COLLATE utf8mb4_general_ci NOT NULL;
+
+-- Relation tables are rebuilt, dropping incomplete and duplicate rows which can't satisfy the primary key
+RENAME TABLE `product_ingredient` TO `product_ingredient_0001`;
+CREATE TABLE `product_ingredient` (
+  `product_id` int(11) NOT NULL,
+  `ingredient_id` int(11) NOT NULL,
+  PRIMARY KEY (`product_id`,`ingredient_id`),
+  KEY `ingredient_id` (`ingredient_id`),
+  CONSTRAINT `fk_product_ingredient_product` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`)
+    ON DELETE CASCADE,
+  CONSTRAINT `fk_product_ingredient_ingredient` FOREIGN KEY (`ingredient_id`) REFERENCES `ingredient` (`id`)
+    ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+INSERT IGNORE INTO `product_ingredient` (`product_id`, `ingredient_id`)
+  SELECT `product_id`, `ingredient_id` FROM `product_ingredient_0001`
+  WHERE `product_id` IS NOT NULL AND `ingredient_id` IS NOT NULL;
+DROP TABLE `product_ingredient_0001`;
+
+RENAME TABLE `product_sourcingvalue` TO `product_sourcingvalue_0001`;
+CREATE TABLE `product_sourcingvalue` (
+  `product_id` int(11) NOT NULL,
+  `sourcingvalue_id` int(11) NOT NULL,
+  PRIMARY KEY (`product_id`,`sourcingvalue_id`),
+  KEY `sourcingvalue_id` (`sourcingvalue_id`),
+  CONSTRAINT `fk_product_sourcingvalue_product` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`)
+    ON DELETE CASCADE,
+  CONSTRAINT `fk_product_sourcingvalue_sourcingvalue` FOREIGN KEY (`sourcingvalue_id`)
+    REFERENCES `sourcingvalue` (`id`) ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+INSERT IGNORE INTO `product_sourcingvalue` (`product_id`, `sourcingvalue_id`)
+  SELECT `product_id`, `sourcingvalue_id` FROM `product_sourcingvalue_0001`
+  WHERE `product_id` IS NOT NULL AND `sourcingvalue_id` IS NOT NULL;
+DROP TABLE `product_sourcingvalue_0001`;
+
+ALTER TABLE `product_revision` DROP FOREIGN KEY `product_revision_ibfk_1`;
+ALTER TABLE `product_revision`
+  ADD CONSTRAINT `fk_product_revision_product` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`)
+    ON DELETE CASCADE;
@@ -0,0 +1,14 @@
+-- Only one certification per product can be kept, the one created first
+
+ALTER TABLE `product` ADD COLUMN `dietary_certification_id` int(11) DEFAULT NULL AFTER `allergy`,
+  ADD KEY `dietary_certification_id` (`dietary_certification_id`);
+UPDATE `product` INNER JOIN (
+    SELECT `product_id`, MIN(`dietarycertification_id`) AS `dietarycertification_id`
+    FROM `product_dietarycertification` GROUP BY `product_id`
+  ) AS `first_certification` ON `product`.`id` = `first_certification`.`product_id`
+  SET `product`.`dietary_certification_id` = `first_certification`.`dietarycertification_id`;
+ALTER TABLE `product`
+  ADD CONSTRAINT `fk_product_dietarycertification` FOREIGN KEY (`dietary_certification_id`)
+    REFERENCES `dietarycertification` (`id`) ON DELETE SET NULL;
+
+DROP TABLE `product_dietarycertification`;
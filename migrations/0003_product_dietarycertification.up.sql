@@ -0,0 +1,19 @@
+-- A product can carry several dietary certifications, so they move to a relation table like ingredients
+
+CREATE TABLE `product_dietarycertification` (
+  `product_id` int(11) NOT NULL,
+  `dietarycertification_id` int(11) NOT NULL,
+  PRIMARY KEY (`product_id`,`dietarycertification_id`),
+  KEY `dietarycertification_id` (`dietarycertification_id`),
+  CONSTRAINT `fk_product_dietarycertification_product` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`)
+    ON DELETE CASCADE,
+  CONSTRAINT `fk_product_dietarycertification_dietarycertification` FOREIGN KEY (`dietarycertification_id`)
+    REFERENCES `dietarycertification` (`id`) ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- Moving the single certification of every product
+INSERT INTO `product_dietarycertification` (`product_id`, `dietarycertification_id`)
+  SELECT `id`, `dietary_certification_id` FROM `product` WHERE `dietary_certification_id` IS NOT NULL;
+
+ALTER TABLE `product` DROP FOREIGN KEY `fk_product_dietarycertification`;
+ALTER TABLE `product` DROP COLUMN `dietary_certification_id`;
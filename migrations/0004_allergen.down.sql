@@ -0,0 +1,3 @@
+ALTER TABLE `product` DROP COLUMN `allergens_declared`;
+DROP TABLE `product_allergen`;
+DROP TABLE `allergen`;
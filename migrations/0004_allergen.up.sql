@@ -0,0 +1,41 @@
+-- Structured allergens, so products can be filtered by them, free text product.allergy is kept for display
+
+CREATE TABLE `allergen` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `code` varchar(64) COLLATE utf8mb4_general_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_general_ci NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `code` (`code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- The 14 allergens to be declared in the EU (Regulation (EU) No 1169/2011, Annex II)
+INSERT INTO `allergen` (`code`, `name`) VALUES
+  ('cereals_containing_gluten', 'Cereals containing gluten'),
+  ('crustaceans', 'Crustaceans'),
+  ('eggs', 'Eggs'),
+  ('fish', 'Fish'),
+  ('peanuts', 'Peanuts'),
+  ('soybeans', 'Soybeans'),
+  ('milk', 'Milk'),
+  ('nuts', 'Nuts'),
+  ('celery', 'Celery'),
+  ('mustard', 'Mustard'),
+  ('sesame_seeds', 'Sesame seeds'),
+  ('sulphites', 'Sulphur dioxide and sulphites'),
+  ('lupin', 'Lupin'),
+  ('molluscs', 'Molluscs');
+
+CREATE TABLE `product_allergen` (
+  `product_id` int(11) NOT NULL,
+  `allergen_id` int(11) NOT NULL,
+  `level` enum('contains','may_contain') COLLATE utf8mb4_general_ci NOT NULL,
+  PRIMARY KEY (`product_id`,`allergen_id`),
+  KEY `allergen_id` (`allergen_id`),
+  CONSTRAINT `fk_product_allergen_product` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`)
+    ON DELETE CASCADE,
+  CONSTRAINT `fk_product_allergen_allergen` FOREIGN KEY (`allergen_id`) REFERENCES `allergen` (`id`)
+    ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- Only products whose allergens have been declared (even as none) can be reported free of an allergen
+ALTER TABLE `product` ADD COLUMN `allergens_declared` tinyint(1) NOT NULL DEFAULT '0' AFTER `allergy`;
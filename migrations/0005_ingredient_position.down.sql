@@ -0,0 +1,4 @@
+ALTER TABLE `product_ingredient`
+  DROP INDEX `product_position`,
+  DROP COLUMN `percentage`,
+  DROP COLUMN `position`;
@@ -0,0 +1,18 @@
+-- Ingredients are listed in the order of the label, with their percentage if declared
+
+ALTER TABLE `product_ingredient`
+  ADD COLUMN `position` int(11) NOT NULL DEFAULT '0',
+  ADD COLUMN `percentage` decimal(5,2) DEFAULT NULL;
+
+-- Order of existing ingredients is unknown, numbering them by ingredient id
+UPDATE `product_ingredient` INNER JOIN (
+    SELECT `current`.`product_id`, `current`.`ingredient_id`, COUNT(*) AS `position`
+    FROM `product_ingredient` AS `current` INNER JOIN `product_ingredient` AS `previous`
+      ON `previous`.`product_id` = `current`.`product_id` AND `previous`.`ingredient_id` <= `current`.`ingredient_id`
+    GROUP BY `current`.`product_id`, `current`.`ingredient_id`
+  ) AS `ordered`
+  ON `ordered`.`product_id` = `product_ingredient`.`product_id`
+  AND `ordered`.`ingredient_id` = `product_ingredient`.`ingredient_id`
+  SET `product_ingredient`.`position` = `ordered`.`position`;
+
+ALTER TABLE `product_ingredient` ADD UNIQUE KEY `product_position` (`product_id`, `position`);
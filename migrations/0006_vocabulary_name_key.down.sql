@@ -0,0 +1,17 @@
+-- Fails on the unique name index, if names differing only in case or accents were stored since;
+-- rows merged by the up migration stay merged
+ALTER TABLE `dietarycertification`
+  DROP INDEX `name_key`,
+  DROP COLUMN `name_key`,
+  MODIFY `name` varchar(255) COLLATE utf8mb4_general_ci NOT NULL,
+  ADD UNIQUE KEY `name` (`name`);
+ALTER TABLE `ingredient`
+  DROP INDEX `name_key`,
+  DROP COLUMN `name_key`,
+  MODIFY `name` varchar(255) COLLATE utf8mb4_general_ci NOT NULL,
+  ADD UNIQUE KEY `name` (`name`);
+ALTER TABLE `sourcingvalue`
+  DROP INDEX `name_key`,
+  DROP COLUMN `name_key`,
+  MODIFY `name` varchar(255) COLLATE utf8mb4_general_ci NOT NULL,
+  ADD UNIQUE KEY `name` (`name`);
@@ -0,0 +1,77 @@
+-- Sourcing values, ingredients and dietary certifications are matched by a normalized key of their name
+-- (trimmed, NFC, case folded, synonyms replaced), which the server computes on every write.
+-- Keys of existing rows are approximated here, run the dedupe command to compute them exactly
+-- and to merge rows sharing an exact key.
+-- Names become case and accent sensitive, so the unique index moves from the name to the key, which alone decides
+-- which names are the same entry. Rows sharing an approximated key (e.g. names differing in leading spaces) are
+-- merged first into the oldest of them, moving their products to it.
+
+
+ALTER TABLE `sourcingvalue`
+  MODIFY `name` varchar(255) COLLATE utf8mb4_0900_as_cs NOT NULL,
+  ADD COLUMN `name_key` varchar(255) COLLATE utf8mb4_bin DEFAULT NULL AFTER `name`;
+UPDATE `sourcingvalue` SET `name_key` = LOWER(TRIM(`name`));
+UPDATE IGNORE `product_sourcingvalue` AS `relation`
+  INNER JOIN `sourcingvalue` AS `entry` ON `relation`.`sourcingvalue_id` = `entry`.`id`
+  INNER JOIN (SELECT `name_key`, MIN(`id`) AS `id` FROM `sourcingvalue` GROUP BY `name_key`) AS `kept`
+    ON `kept`.`name_key` = `entry`.`name_key`
+  SET `relation`.`sourcingvalue_id` = `kept`.`id` WHERE `entry`.`id` <> `kept`.`id`;
+DELETE `relation` FROM `product_sourcingvalue` AS `relation`
+  INNER JOIN `sourcingvalue` AS `entry` ON `relation`.`sourcingvalue_id` = `entry`.`id`
+  INNER JOIN (SELECT `name_key`, MIN(`id`) AS `id` FROM `sourcingvalue` GROUP BY `name_key`) AS `kept`
+    ON `kept`.`name_key` = `entry`.`name_key`
+  WHERE `entry`.`id` <> `kept`.`id`;
+DELETE `entry` FROM `sourcingvalue` AS `entry`
+  INNER JOIN (SELECT `name_key`, MIN(`id`) AS `id` FROM `sourcingvalue` GROUP BY `name_key`) AS `kept`
+    ON `kept`.`name_key` = `entry`.`name_key`
+  WHERE `entry`.`id` <> `kept`.`id`;
+ALTER TABLE `sourcingvalue`
+  MODIFY `name_key` varchar(255) COLLATE utf8mb4_bin NOT NULL,
+  DROP INDEX `name`,
+  ADD UNIQUE KEY `name_key` (`name_key`);
+
+ALTER TABLE `ingredient`
+  MODIFY `name` varchar(255) COLLATE utf8mb4_0900_as_cs NOT NULL,
+  ADD COLUMN `name_key` varchar(255) COLLATE utf8mb4_bin DEFAULT NULL AFTER `name`;
+UPDATE `ingredient` SET `name_key` = LOWER(TRIM(`name`));
+UPDATE IGNORE `product_ingredient` AS `relation`
+  INNER JOIN `ingredient` AS `entry` ON `relation`.`ingredient_id` = `entry`.`id`
+  INNER JOIN (SELECT `name_key`, MIN(`id`) AS `id` FROM `ingredient` GROUP BY `name_key`) AS `kept`
+    ON `kept`.`name_key` = `entry`.`name_key`
+  SET `relation`.`ingredient_id` = `kept`.`id` WHERE `entry`.`id` <> `kept`.`id`;
+DELETE `relation` FROM `product_ingredient` AS `relation`
+  INNER JOIN `ingredient` AS `entry` ON `relation`.`ingredient_id` = `entry`.`id`
+  INNER JOIN (SELECT `name_key`, MIN(`id`) AS `id` FROM `ingredient` GROUP BY `name_key`) AS `kept`
+    ON `kept`.`name_key` = `entry`.`name_key`
+  WHERE `entry`.`id` <> `kept`.`id`;
+DELETE `entry` FROM `ingredient` AS `entry`
+  INNER JOIN (SELECT `name_key`, MIN(`id`) AS `id` FROM `ingredient` GROUP BY `name_key`) AS `kept`
+    ON `kept`.`name_key` = `entry`.`name_key`
+  WHERE `entry`.`id` <> `kept`.`id`;
+ALTER TABLE `ingredient`
+  MODIFY `name_key` varchar(255) COLLATE utf8mb4_bin NOT NULL,
+  DROP INDEX `name`,
+  ADD UNIQUE KEY `name_key` (`name_key`);
+
+ALTER TABLE `dietarycertification`
+  MODIFY `name` varchar(255) COLLATE utf8mb4_0900_as_cs NOT NULL,
+  ADD COLUMN `name_key` varchar(255) COLLATE utf8mb4_bin DEFAULT NULL AFTER `name`;
+UPDATE `dietarycertification` SET `name_key` = LOWER(TRIM(`name`));
+UPDATE IGNORE `product_dietarycertification` AS `relation`
+  INNER JOIN `dietarycertification` AS `entry` ON `relation`.`dietarycertification_id` = `entry`.`id`
+  INNER JOIN (SELECT `name_key`, MIN(`id`) AS `id` FROM `dietarycertification` GROUP BY `name_key`) AS `kept`
+    ON `kept`.`name_key` = `entry`.`name_key`
+  SET `relation`.`dietarycertification_id` = `kept`.`id` WHERE `entry`.`id` <> `kept`.`id`;
+DELETE `relation` FROM `product_dietarycertification` AS `relation`
+  INNER JOIN `dietarycertification` AS `entry` ON `relation`.`dietarycertification_id` = `entry`.`id`
+  INNER JOIN (SELECT `name_key`, MIN(`id`) AS `id` FROM `dietarycertification` GROUP BY `name_key`) AS `kept`
+    ON `kept`.`name_key` = `entry`.`name_key`
+  WHERE `entry`.`id` <> `kept`.`id`;
+DELETE `entry` FROM `dietarycertification` AS `entry`
+  INNER JOIN (SELECT `name_key`, MIN(`id`) AS `id` FROM `dietarycertification` GROUP BY `name_key`) AS `kept`
+    ON `kept`.`name_key` = `entry`.`name_key`
+  WHERE `entry`.`id` <> `kept`.`id`;
+ALTER TABLE `dietarycertification`
+  MODIFY `name_key` varchar(255) COLLATE utf8mb4_bin NOT NULL,
+  DROP INDEX `name`,
+  ADD UNIQUE KEY `name_key` (`name_key`);
@@ -0,0 +1,4 @@
+DROP TABLE `dietarycertification_translation`;
+DROP TABLE `ingredient_translation`;
+DROP TABLE `sourcingvalue_translation`;
+DROP TABLE `product_translation`;
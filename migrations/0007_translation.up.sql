@@ -0,0 +1,40 @@
+-- Translations of product texts and vocabulary names for locales other than the default one (en),
+-- whose content stays in the product and vocabulary tables. A text not translated is NULL and falls back.
+
+CREATE TABLE `product_translation` (
+  `product_id` int(11) NOT NULL,
+  `locale` varchar(35) COLLATE utf8mb4_bin NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
+  `description` text COLLATE utf8mb4_general_ci,
+  `story` text COLLATE utf8mb4_general_ci,
+  PRIMARY KEY (`product_id`,`locale`),
+  CONSTRAINT `fk_product_translation_product` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`)
+    ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `sourcingvalue_translation` (
+  `sourcingvalue_id` int(11) NOT NULL,
+  `locale` varchar(35) COLLATE utf8mb4_bin NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_general_ci NOT NULL,
+  PRIMARY KEY (`sourcingvalue_id`,`locale`),
+  CONSTRAINT `fk_sourcingvalue_translation_sourcingvalue` FOREIGN KEY (`sourcingvalue_id`)
+    REFERENCES `sourcingvalue` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `ingredient_translation` (
+  `ingredient_id` int(11) NOT NULL,
+  `locale` varchar(35) COLLATE utf8mb4_bin NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_general_ci NOT NULL,
+  PRIMARY KEY (`ingredient_id`,`locale`),
+  CONSTRAINT `fk_ingredient_translation_ingredient` FOREIGN KEY (`ingredient_id`)
+    REFERENCES `ingredient` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `dietarycertification_translation` (
+  `dietarycertification_id` int(11) NOT NULL,
+  `locale` varchar(35) COLLATE utf8mb4_bin NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_general_ci NOT NULL,
+  PRIMARY KEY (`dietarycertification_id`,`locale`),
+  CONSTRAINT `fk_dietarycertification_translation_dietarycertification` FOREIGN KEY (`dietarycertification_id`)
+    REFERENCES `dietarycertification` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
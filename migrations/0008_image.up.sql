@@ -0,0 +1,16 @@
+-- Images uploaded to the image storage, by key (sha256 of the file and extension).
+-- Products refer to them by url in image_closed/image_opened, which may still be external urls as well.
+
+CREATE TABLE `image` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `image_key` varchar(80) COLLATE utf8mb4_bin NOT NULL,
+  `thumbnail_key` varchar(80) COLLATE utf8mb4_bin NOT NULL,
+  `content_type` varchar(32) COLLATE utf8mb4_general_ci NOT NULL,
+  `width` int(11) NOT NULL,
+  `height` int(11) NOT NULL,
+  `size` int(11) NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `image_key` (`image_key`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
@@ -0,0 +1,24 @@
+-- Markets (ISO 3166 country codes) a product is listed in, with its availability there,
+-- and its prices per market and currency (ISO 4217) in minor units, effective from a date (UTC) till another one
+-- or without end. Prices are deleted along with their market.
+
+CREATE TABLE `product_market` (
+  `product_id` int(11) NOT NULL,
+  `market` char(2) COLLATE utf8mb4_bin NOT NULL,
+  `available` tinyint(1) NOT NULL DEFAULT '1',
+  PRIMARY KEY (`product_id`,`market`),
+  CONSTRAINT `fk_product_market_product` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`)
+    ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `product_price` (
+  `product_id` int(11) NOT NULL,
+  `market` char(2) COLLATE utf8mb4_bin NOT NULL,
+  `currency` char(3) COLLATE utf8mb4_bin NOT NULL,
+  `amount` bigint(20) NOT NULL,
+  `effective_from` datetime NOT NULL,
+  `effective_to` datetime DEFAULT NULL,
+  PRIMARY KEY (`product_id`,`market`,`currency`,`effective_from`),
+  CONSTRAINT `fk_product_price_product_market` FOREIGN KEY (`product_id`, `market`)
+    REFERENCES `product_market` (`product_id`, `market`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
@@ -0,0 +1,2 @@
+DROP TABLE `product_variant_nutrition`;
+DROP TABLE `product_variant`;
@@ -0,0 +1,27 @@
+-- Variants (formats) of a product, e.g. pint, mini cup or bar, each with its own SKU, size and packaging image,
+-- and nutrition values of a variant overriding those of its product. Deleted along with the product.
+
+CREATE TABLE `product_variant` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `product_id` int(11) NOT NULL,
+  `sku` varchar(64) COLLATE utf8mb4_bin NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_general_ci NOT NULL,
+  `size` decimal(10,2) NOT NULL,
+  `size_unit` varchar(8) COLLATE utf8mb4_bin NOT NULL,
+  `packaging_image` varchar(255) COLLATE utf8mb4_general_ci DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `sku` (`sku`),
+  KEY `product_id` (`product_id`),
+  KEY `packaging_image` (`packaging_image`),
+  CONSTRAINT `fk_product_variant_product` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`)
+    ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `product_variant_nutrition` (
+  `variant_id` int(11) NOT NULL,
+  `nutrient` varchar(64) COLLATE utf8mb4_bin NOT NULL,
+  `amount` decimal(10,3) NOT NULL,
+  PRIMARY KEY (`variant_id`,`nutrient`),
+  CONSTRAINT `fk_product_variant_nutrition_variant` FOREIGN KEY (`variant_id`) REFERENCES `product_variant` (`id`)
+    ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
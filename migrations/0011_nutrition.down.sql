@@ -0,0 +1,10 @@
+-- Nutrition values of variants are restored as they were named before, replacing the values converted from them
+DELETE `product_variant_nutrition` FROM `product_variant_nutrition` INNER JOIN `product_variant_nutrition_legacy`
+  ON `product_variant_nutrition`.`variant_id` = `product_variant_nutrition_legacy`.`variant_id`
+  AND `product_variant_nutrition`.`nutrient` = `product_variant_nutrition_legacy`.`converted_to`;
+INSERT IGNORE INTO `product_variant_nutrition` (`variant_id`, `nutrient`, `amount`)
+  SELECT `variant_id`, `nutrient`, `amount` FROM `product_variant_nutrition_legacy`;
+DROP TABLE `product_variant_nutrition_legacy`;
+
+DROP TABLE `product_nutrient`;
+DROP TABLE `product_nutrition`;
@@ -0,0 +1,80 @@
+-- Nutrition facts of a product: its serving size in grams and amounts of nutrients per 100 g,
+-- in the unit of the nutrient (kcal, g or mg). Amounts per serving are computed from them.
+
+CREATE TABLE `product_nutrition` (
+  `product_id` int(11) NOT NULL,
+  `serving_size` decimal(7,2) NOT NULL,
+  PRIMARY KEY (`product_id`),
+  CONSTRAINT `fk_product_nutrition_product` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`)
+    ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `product_nutrient` (
+  `product_id` int(11) NOT NULL,
+  `nutrient` varchar(32) COLLATE utf8mb4_bin NOT NULL,
+  `amount` decimal(12,3) NOT NULL,
+  PRIMARY KEY (`product_id`,`nutrient`),
+  CONSTRAINT `fk_product_nutrient_product_nutrition` FOREIGN KEY (`product_id`)
+    REFERENCES `product_nutrition` (`product_id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- Nutrition values of variants were named freely with their unit (e.g. sugar_g, sodium_mg, energy_kcal),
+-- they are nutrients of the taxonomy now (e.g. sugar), per 100 g in the unit of the nutrient.
+-- Values of other names are moved as they are to product_variant_nutrition_legacy, which the down migration
+-- restores them from. Names of a nutrient with a unit it can be given in are converted to it (kj by 4.184 per kcal),
+-- unless the variant has a value of the nutrient already. A value that would repeat a nutrient converted from
+-- another name (e.g. sugar_g and sugar_mg) is skipped by IGNORE, it's kept in the legacy table all the same.
+CREATE TABLE `product_variant_nutrition_legacy` (
+  `variant_id` int(11) NOT NULL,
+  `nutrient` varchar(64) COLLATE utf8mb4_bin NOT NULL,
+  `amount` decimal(10,3) NOT NULL,
+  `converted_to` varchar(32) COLLATE utf8mb4_bin DEFAULT NULL,
+  `converted_amount` decimal(10,3) DEFAULT NULL,
+  PRIMARY KEY (`variant_id`,`nutrient`),
+  CONSTRAINT `fk_product_variant_nutrition_legacy_variant` FOREIGN KEY (`variant_id`)
+    REFERENCES `product_variant` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+INSERT INTO `product_variant_nutrition_legacy` (`variant_id`, `nutrient`, `amount`)
+  SELECT `variant_id`, `nutrient`, `amount` FROM `product_variant_nutrition`
+  WHERE `nutrient` NOT IN ('energy', 'fat', 'saturated_fat', 'trans_fat', 'cholesterol', 'sodium', 'carbohydrate',
+    'fiber', 'sugar', 'added_sugar', 'protein', 'salt', 'calcium');
+DELETE `product_variant_nutrition` FROM `product_variant_nutrition` INNER JOIN `product_variant_nutrition_legacy`
+  ON `product_variant_nutrition`.`variant_id` = `product_variant_nutrition_legacy`.`variant_id`
+  AND `product_variant_nutrition`.`nutrient` = `product_variant_nutrition_legacy`.`nutrient`;
+UPDATE `product_variant_nutrition_legacy` INNER JOIN (
+  SELECT 'energy_kcal' AS `name`, 'energy' AS `code`, 1 AS `factor`
+  UNION ALL SELECT 'energy_kj', 'energy', 0.2390057361
+  UNION ALL SELECT 'fat_g', 'fat', 1
+  UNION ALL SELECT 'fat_mg', 'fat', 0.001
+  UNION ALL SELECT 'saturated_fat_g', 'saturated_fat', 1
+  UNION ALL SELECT 'saturated_fat_mg', 'saturated_fat', 0.001
+  UNION ALL SELECT 'trans_fat_g', 'trans_fat', 1
+  UNION ALL SELECT 'trans_fat_mg', 'trans_fat', 0.001
+  UNION ALL SELECT 'cholesterol_g', 'cholesterol', 1000
+  UNION ALL SELECT 'cholesterol_mg', 'cholesterol', 1
+  UNION ALL SELECT 'sodium_g', 'sodium', 1000
+  UNION ALL SELECT 'sodium_mg', 'sodium', 1
+  UNION ALL SELECT 'carbohydrate_g', 'carbohydrate', 1
+  UNION ALL SELECT 'carbohydrate_mg', 'carbohydrate', 0.001
+  UNION ALL SELECT 'fiber_g', 'fiber', 1
+  UNION ALL SELECT 'fiber_mg', 'fiber', 0.001
+  UNION ALL SELECT 'sugar_g', 'sugar', 1
+  UNION ALL SELECT 'sugar_mg', 'sugar', 0.001
+  UNION ALL SELECT 'added_sugar_g', 'added_sugar', 1
+  UNION ALL SELECT 'added_sugar_mg', 'added_sugar', 0.001
+  UNION ALL SELECT 'protein_g', 'protein', 1
+  UNION ALL SELECT 'protein_mg', 'protein', 0.001
+  UNION ALL SELECT 'salt_g', 'salt', 1
+  UNION ALL SELECT 'salt_mg', 'salt', 0.001
+  UNION ALL SELECT 'calcium_g', 'calcium', 1000
+  UNION ALL SELECT 'calcium_mg', 'calcium', 1
+) AS `conversion` ON `product_variant_nutrition_legacy`.`nutrient` = `conversion`.`name`
+SET `product_variant_nutrition_legacy`.`converted_to` = `conversion`.`code`,
+  `product_variant_nutrition_legacy`.`converted_amount` =
+    ROUND(`product_variant_nutrition_legacy`.`amount` * `conversion`.`factor`, 3)
+WHERE NOT EXISTS (SELECT 1 FROM `product_variant_nutrition`
+  WHERE `product_variant_nutrition`.`variant_id` = `product_variant_nutrition_legacy`.`variant_id`
+  AND `product_variant_nutrition`.`nutrient` = `conversion`.`code`);
+INSERT IGNORE INTO `product_variant_nutrition` (`variant_id`, `nutrient`, `amount`)
+  SELECT `variant_id`, `converted_to`, `converted_amount` FROM `product_variant_nutrition_legacy`
+  WHERE `converted_to` IS NOT NULL ORDER BY `variant_id`, `nutrient`;
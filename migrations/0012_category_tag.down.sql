@@ -0,0 +1,5 @@
+DROP TABLE `tag_translation`;
+DROP TABLE `product_tag`;
+DROP TABLE `tag`;
+DROP TABLE `product_category`;
+DROP TABLE `category`;
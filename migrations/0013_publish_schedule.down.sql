@@ -0,0 +1,3 @@
+DROP TABLE `product_schedule_event`;
+ALTER TABLE `product` DROP KEY `unpublish_at`, DROP KEY `publish_at`, DROP COLUMN `unpublish_at`,
+  DROP COLUMN `publish_at`;
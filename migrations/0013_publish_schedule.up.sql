@@ -0,0 +1,22 @@
+-- Scheduled publishing of products: a product is live from publish_at (UTC) till unpublish_at,
+-- NULL meaning since ever or for ever, as long as it isn't inactive.
+-- product_schedule_event records the events of the scheduler when a product goes live or expires,
+-- once per scheduled time, so rescheduling a product emits them again. Events are recorded first
+-- and emitted_at is set once they are emitted, so events not emitted yet are retried.
+
+ALTER TABLE `product`
+  ADD COLUMN `publish_at` datetime DEFAULT NULL,
+  ADD COLUMN `unpublish_at` datetime DEFAULT NULL,
+  ADD KEY `publish_at` (`publish_at`),
+  ADD KEY `unpublish_at` (`unpublish_at`);
+
+CREATE TABLE `product_schedule_event` (
+  `product_id` int(11) NOT NULL,
+  `event` varchar(32) COLLATE utf8mb4_bin NOT NULL,
+  `scheduled_at` datetime NOT NULL,
+  `emitted_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`product_id`,`event`,`scheduled_at`),
+  KEY `emitted_at` (`emitted_at`),
+  CONSTRAINT `fk_product_schedule_event_product` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`)
+    ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
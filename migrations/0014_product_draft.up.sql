@@ -0,0 +1,16 @@
+-- Content workflow of product edits: edits are saved in a draft of the product instead of going live,
+-- submitted for review, approved by a reviewer and then published as a new revision of the product.
+-- data holds the edited fields of the product (json, as sent to the update api) and fields their names,
+-- a product has at most one draft, which is deleted once it's published.
+
+CREATE TABLE `product_draft` (
+  `product_id` int(11) NOT NULL,
+  `data` longtext COLLATE utf8mb4_general_ci NOT NULL,
+  `fields` varchar(512) COLLATE utf8mb4_bin NOT NULL,
+  `status` varchar(16) COLLATE utf8mb4_bin NOT NULL,
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`product_id`),
+  KEY `status` (`status`),
+  CONSTRAINT `fk_product_draft_product` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`)
+    ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
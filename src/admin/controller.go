@@ -0,0 +1,273 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+
+	"admin/structs"
+	"authenticator"
+	"constants"
+	"janitor"
+	"logger"
+	"metrics"
+	"utils"
+)
+
+func ReadLogLevel(ginContext *gin.Context) {
+	/*
+		To fetch current level of application logger
+		Only users with the admin or reviewer role (in their token) may read it, others get status 403
+		Sample Url: "http://host/admin/loglevel/"
+		Request Method: GET
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false,
+			"level": "info"
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.LogLevelResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "admin.ReadLogLevel"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.AdminLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	// Only admins and reviewers may read the log level,
+	// the role would've been dropped in ginContext object by the auth middleware
+	if !authenticator.HasRole(ginContext, constants.RoleAdmin, constants.RoleReviewer) {
+		metrics.AuthFailures.WithLabels(constants.AuthFailureForbiddenRole).Inc()
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnError(ginContext, http.StatusForbidden, constants.AdminRoleRequiredMessage)
+		return
+	}
+
+	response = &structs.LogLevelResponse{
+		Success: true,
+		Message: constants.ReadSuccessMessage,
+		Level:   logger.ZaloraStatsLogger.LevelName(),
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.AdminLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func UpdateLogLevel(ginContext *gin.Context) {
+	/*
+		To change level of application logger without restarting the server
+		Only users with the admin or reviewer role (in their token) may change it, others get status 403
+		Sample Url: "http://host/admin/loglevel/"
+		Request Method: PUT
+		Request Data:
+		{
+			"level": "debug/info/warning/error"
+		}
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false,
+			"level": "debug"
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.LogLevelResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "admin.UpdateLogLevel"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.AdminLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	// Only admins and reviewers may change the log level,
+	// the role would've been dropped in ginContext object by the auth middleware
+	if !authenticator.HasRole(ginContext, constants.RoleAdmin, constants.RoleReviewer) {
+		metrics.AuthFailures.WithLabels(constants.AuthFailureForbiddenRole).Inc()
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnError(ginContext, http.StatusForbidden, constants.AdminRoleRequiredMessage)
+		return
+	}
+
+	level := ginContext.DefaultPostForm("level", "")
+	if err := logger.ZaloraStatsLogger.SetLevelByName(level); err != nil {
+		response = &structs.LogLevelResponse{
+			Message: constants.RequestInvalidErrorMessage,
+			Level:   logger.ZaloraStatsLogger.LevelName(),
+		}
+	} else {
+		requestLogger.Warn(constants.AdminLogBucketName, logIdentifier,
+			"Log level changed to "+logger.ZaloraStatsLogger.LevelName())
+		response = &structs.LogLevelResponse{
+			Success: true,
+			Message: constants.UpdateSuccessMessage,
+			Level:   logger.ZaloraStatsLogger.LevelName(),
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.AdminLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func CleanUp(ginContext *gin.Context) {
+	/*
+		To run the janitor once, deleting sourcing values, ingredients, dietary certifications, tags
+		and uploaded images (older than the grace period) not used by any product
+		Only users with the admin or reviewer role (in their token) may run it, others get status 403
+		Unused entries are only listed, unless deleting them is explicitly asked for with dry_run = 0
+		Sample Url: "http://host/admin/cleanup/"
+		Request Method: POST
+		Request Data:
+		{
+			"dry_run": "0" // optional, to delete unused entries instead of only listing them
+		}
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false,
+			"dry_run": true/false,
+			"sourcing_values": ["names of unused sourcing values"],
+			"ingredients": ["names of unused ingredients"],
+			"dietary_certifications": ["names of unused dietary certifications"],
+			"tags": ["names of unused tags"],
+			"images": ["urls of unreferenced images"]
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CleanUpResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "admin.CleanUp"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.AdminLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	// Only admins and reviewers may run the janitor,
+	// the role would've been dropped in ginContext object by the auth middleware
+	if !authenticator.HasRole(ginContext, constants.RoleAdmin, constants.RoleReviewer) {
+		metrics.AuthFailures.WithLabels(constants.AuthFailureForbiddenRole).Inc()
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnError(ginContext, http.StatusForbidden, constants.AdminRoleRequiredMessage)
+		return
+	}
+
+	// Entries are deleted only if the caller opts out of the dry run, so that a bare call can't delete anything
+	dryRun := ginContext.DefaultPostForm("dry_run", "1") != "0"
+	unUsed, success := janitor.RunOnce(ctx, dryRun)
+	if !success {
+		response = &structs.CleanUpResponse{Message: constants.GenericErrorMessage, DryRun: dryRun}
+	} else {
+		response = &structs.CleanUpResponse{
+			Success:               true,
+			Message:               constants.CleanUpSuccessMessage,
+			DryRun:                dryRun,
+			SourcingValues:        unUsed.SourcingValues,
+			Ingredients:           unUsed.Ingredients,
+			DietaryCertifications: unUsed.DietaryCertifications,
+			Tags:                  unUsed.Tags,
+			Images:                unUsed.Images,
+		}
+		if dryRun {
+			response.Message = constants.CleanUpDryRunMessage
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.AdminLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
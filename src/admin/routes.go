@@ -0,0 +1,18 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"authenticator"
+)
+
+func RoutesAdmin(group *gin.RouterGroup) {
+	// to read current level of application logger
+	group.GET("/loglevel/", authenticator.IsAuthorized, ReadLogLevel)
+
+	// to change level of application logger at runtime
+	group.PUT("/loglevel/", authenticator.IsAuthorized, UpdateLogLevel)
+
+	// to list (or with dry_run=0 delete) unused sourcing values, ingredients, dietary certifications and tags
+	group.POST("/cleanup/", authenticator.IsAuthorized, CleanUp)
+}
@@ -0,0 +1,20 @@
+package structs
+
+// Response structure of read/update log level
+type LogLevelResponse struct {
+	Message string `json:"message"`
+	Level   string `json:"level"`
+	Success bool   `json:"success"`
+}
+
+// Response structure of clean up of unused sourcing values, ingredients, dietary certifications, tags and images
+type CleanUpResponse struct {
+	Message               string   `json:"message"`
+	DietaryCertifications []string `json:"dietary_certifications"`
+	Ingredients           []string `json:"ingredients"`
+	SourcingValues        []string `json:"sourcing_values"`
+	Tags                  []string `json:"tags"`
+	Images                []string `json:"images"`
+	DryRun                bool     `json:"dry_run"`
+	Success               bool     `json:"success"`
+}
@@ -9,13 +9,19 @@ import (
 
 	"constants"
 	"logger"
+	"metrics"
 )
 
 func GenerateJWT() (string, error) {
+	return GenerateJWTWithRole(constants.RoleEditor)
+}
+
+func GenerateJWTWithRole(role string) (string, error) {
 	token := jwt.New(jwt.SigningMethodHS256)
 	claims := token.Claims.(jwt.MapClaims)
 	claims["authorized"] = true
 	claims["client"] = "Zalora Client"
+	claims[constants.RoleKeyName] = role
 	claims["exp"] = time.Now().Add(time.Minute * 30).Unix()
 	tokenString, err := token.SignedString([]byte(constants.JWTSigningKey))
 	if err != nil {
@@ -36,12 +42,37 @@ func IsAuthorized(ginContext *gin.Context) {
 			return []byte(constants.JWTSigningKey), nil
 		})
 		if err != nil {
-			logger.ZaloraStatsLogger.Error(constants.AuthLogBucketName, logIdentifier,
+			logger.FromContext(ginContext.Request.Context()).Error(constants.AuthLogBucketName, logIdentifier,
 				constants.JWTTokenParseErrorMessage, err.Error())
 		}
-		if token.Valid {
+		if err == nil && token != nil && token.Valid {
 			ginContext.Set("is_authorized", 1)
+			// Tokens without a role are of editors, only reviewers may approve drafts of products
+			role := constants.RoleEditor
+			if claims, ok := token.Claims.(jwt.MapClaims); ok {
+				if claimedRole, ok := claims[constants.RoleKeyName].(string); ok && claimedRole != "" {
+					role = claimedRole
+				}
+			}
+			ginContext.Set(constants.RoleKeyName, role)
+		} else {
+			metrics.AuthFailures.WithLabels(constants.AuthFailureInvalidToken).Inc()
 		}
+	} else {
+		metrics.AuthFailures.WithLabels(constants.AuthFailureMissingToken).Inc()
 	}
 	ginContext.Next()
 }
+
+func HasRole(ginContext *gin.Context, roles ...string) bool {
+	/*
+		To check if the role dropped in ginContext object by IsAuthorized is one of the given roles
+	*/
+	role, _ := ginContext.Get(constants.RoleKeyName)
+	for _, allowed := range roles {
+		if role == allowed {
+			return true
+		}
+	}
+	return false
+}
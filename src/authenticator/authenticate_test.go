@@ -0,0 +1,83 @@
+package authenticator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+
+	"constants"
+	"logger"
+)
+
+func TestIsAuthorizedRole(t *testing.T) {
+	/*
+		Testing Scenario: Calling an api with tokens of a reviewer, of an editor and without a role claim
+		Expectation: Request is authorized with the role of the token, tokens without a role are of editors
+	*/
+	logger.Init()
+	withoutRole := jwt.New(jwt.SigningMethodHS256)
+	withoutRole.Claims.(jwt.MapClaims)["authorized"] = true
+	withoutRoleToken, tokenErr := withoutRole.SignedString([]byte(constants.JWTSigningKey))
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	reviewerToken, tokenErr := GenerateJWTWithRole(constants.RoleReviewer)
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	editorToken, tokenErr := GenerateJWT()
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	for token, expected := range map[string]string{reviewerToken: constants.RoleReviewer,
+		editorToken: constants.RoleEditor, withoutRoleToken: constants.RoleEditor} {
+		route := gin.New()
+		var role interface{}
+		route.GET("/", IsAuthorized, func(ginContext *gin.Context) {
+			role, _ = ginContext.Get(constants.RoleKeyName)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Add(constants.JWTTokenKeyNameInHeader, token)
+		route.ServeHTTP(httptest.NewRecorder(), req)
+		if role != expected {
+			t.Fatalf("Expected role %s but got %v\n", expected, role)
+		}
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	/*
+		Testing Scenario: Checking tokens of an admin, a reviewer, an editor and no token against the admin apis' roles
+		Expectation: Only admins and reviewers have one of the roles
+	*/
+	logger.Init()
+	adminToken, tokenErr := GenerateJWTWithRole(constants.RoleAdmin)
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	reviewerToken, tokenErr := GenerateJWTWithRole(constants.RoleReviewer)
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	editorToken, tokenErr := GenerateJWT()
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	for token, expected := range map[string]bool{adminToken: true, reviewerToken: true, editorToken: false,
+		"": false} {
+		route := gin.New()
+		hasRole := !expected
+		route.GET("/", IsAuthorized, func(ginContext *gin.Context) {
+			hasRole = HasRole(ginContext, constants.RoleAdmin, constants.RoleReviewer)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Add(constants.JWTTokenKeyNameInHeader, token)
+		route.ServeHTTP(httptest.NewRecorder(), req)
+		if hasRole != expected {
+			t.Fatalf("Expected token %q to have an admin api role: %v but got %v\n", token, expected, hasRole)
+		}
+	}
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 
 	"authenticator"
+	"constants"
 )
 
 func main() {
-	token, err := authenticator.GenerateJWT()
+	// e.g. -role reviewer, for a token allowed to approve drafts of products
+	role := flag.String("role", constants.RoleEditor, "role of the user: editor, reviewer or admin")
+	flag.Parse()
+	token, err := authenticator.GenerateJWTWithRole(*role)
 	if err == nil {
 		fmt.Println("Token: ", token)
 	}
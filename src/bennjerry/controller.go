@@ -1,17 +1,27 @@
 package bennjerry
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"bennjerry/model"
 	"bennjerry/structs"
 	"constants"
+	"imagestore"
+	"localization"
 	"logger"
+	"metrics"
+	"normalizer"
+	"similarity"
 	"utils"
 )
 
@@ -30,9 +40,17 @@ func CreateData(ginContext *gin.Context) {
 				"image_closed": "Link of closed image",
 				"image_open": "Link of open image",
 				"sourcing_values": ["List", "of", "sourcing", "values"],
-				"ingredients": ["List", "of", "ingredients"],
+				"ingredients": ["Cream", "Sugar", "Cocoa"],
+				"ingredient_percentages": [40.5, null, null],
 				"allergy_info": "Allergy related information",
-				"dietary_certifications": "Name of dietary certifications"
+				"allergens": [{"code": "milk", "level": "contains"}, {"code": "nuts", "level": "may_contain"}],
+				"dietary_certifications": ["List", "of", "dietary", "certifications"],
+				"nutrition": {"serving_size_g": 143, "nutrients": [{"nutrient": "energy", "unit": "kcal", "per_100g": 252},
+					{"nutrient": "sugar", "unit": "g", "per_serving": 28}]},
+				"categories": ["Non-Dairy > Frozen Dessert"], // paths of existing categories
+				"tags": ["List", "of", "tags"],
+				"publish_at": "2019-10-01 00:00:00", // optional (UTC), the product isn't shown before
+				"unpublish_at": "2019-12-31 23:59:59" // optional (UTC), the product isn't shown from then on
 			}
 		}
 		Response Data:
@@ -49,12 +67,14 @@ func CreateData(ginContext *gin.Context) {
 		responseBytes []byte
 		responseErr   error
 		logIdentifier = "bennjerry.CreateData"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
 	)
 
 	// defer block to recover and log if any error occurs
 	defer func() {
 		if r := recover(); r != nil {
-			logger.ZaloraStatsLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
 				constants.GenericErrorMessage, string(debug.Stack()))
 			serializer := utils.GetSerializer(constants.JsonSerializerType)
 			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
@@ -77,7 +97,7 @@ func CreateData(ginContext *gin.Context) {
 	// converting post form data to structure
 	umMarshalErr := json.Unmarshal([]byte(postData), &iceCreamData)
 	if umMarshalErr != nil {
-		logger.ZaloraStatsLogger.Error(constants.BenNJerryLogBucketName, logIdentifier, constants.UnMarshalErrorString,
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier, constants.UnMarshalErrorString,
 			umMarshalErr.Error())
 		response = &structs.CreateUpdateDeleteResponse{
 			Message: umMarshalErr.Error(),
@@ -86,9 +106,53 @@ func CreateData(ginContext *gin.Context) {
 		response = &structs.CreateUpdateDeleteResponse{
 			Message: constants.RequestInvalidErrorMessage,
 		}
+	} else if !model.ValidateIngredients(iceCreamData.Ingredients) {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidIngredientErrorMessage,
+		}
+	} else if !model.ValidateNutrition(iceCreamData.Nutrition) {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidNutritionErrorMessage,
+		}
+	} else if !model.ValidateTags(iceCreamData.Tags) {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidTagErrorMessage,
+		}
+	} else if !model.ValidateSchedule(iceCreamData) {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidScheduleErrorMessage,
+		}
+	} else if valid, success := model.ValidateCategories(ctx, iceCreamData.Categories); !success {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if !valid {
+		// Categories are managed through the catalog apis, so products are only added to existing ones
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidCategoryErrorMessage,
+		}
+	} else if valid, success := model.ValidateAllergens(ctx, iceCreamData.Allergens); !success {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if !valid {
+		// Allergens must be codes of the taxonomy, so that products can reliably be filtered by them
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidAllergenErrorMessage,
+		}
+	} else if valid, success := model.ValidateImageURLs(ctx, iceCreamData.ImageClosed,
+		iceCreamData.ImageOpened); !success {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if !valid {
+		// Urls of the image storage must be of uploaded images, external urls are stored as they are
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidImageErrorMessage,
+		}
 	} else {
 		// Calling function to execute queries in an atomic transaction
-		idList, success := model.InsertRecord([]*structs.IceCreamDataStruct{iceCreamData})
+		idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{iceCreamData})
 		if success && len(idList) > 0 {
 			response = &structs.CreateUpdateDeleteResponse{
 				Success: true,
@@ -106,7 +170,7 @@ func CreateData(ginContext *gin.Context) {
 	// calling common util function to send json response (utils/common.go)
 	serializer := utils.GetSerializer(constants.JsonSerializerType)
 	if responseErr != nil {
-		logger.ZaloraStatsLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
 			constants.JsonSerializationErrorMessage, responseErr.Error())
 		serializer.ReturnError(ginContext, http.StatusInternalServerError,
 			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
@@ -117,13 +181,22 @@ func CreateData(ginContext *gin.Context) {
 func ReadData(ginContext *gin.Context) {
 	/*
 		To fetch information of an ice cream by providing product_id
-		Sample Url: "http://host/bennjerry/2190/"
+		Products are shown only from their publish_at till their unpublish_at, if they are scheduled
+		Edits saved in the draft of a product are shown only in preview, with preview=1, along with the status of
+		the draft, whether the product is live or not
+		Texts are translated as per the Accept-Language header (e.g. "ms-MY,ms;q=0.9"), each text falling back
+		through the locales of the header, their fallbacks and parents, to the default locale it's stored in
+		Locales the texts are in are sent in the Content-Language header
+		Sample Url: "http://host/bennjerry/2190/" or "http://host/bennjerry/2190/?market=SG"
 		Request Method: GET
 		Request Data: product_id to be provided in the url, e.g. 2190 in sample url
+		URL Param: market=SG, for availability and prices effective now in a market (country code)
+		URL Param: preview=1, to read the product with the edits of its draft
 		Response Data:
 		{
 			"message": "Success/Error message",
 			"success": true / false,
+			"draft_status": "draft/in_review/approved", // only in preview, if the product has a draft
 			"data": {
 				"productId": "123",
 				"name": "Name of Ice Cream",
@@ -132,24 +205,39 @@ func ReadData(ginContext *gin.Context) {
 				"image_closed": "Link of closed image",
 				"image_open": "Link of open image",
 				"sourcing_values": ["List", "of", "sourcing", "values"],
-				"ingredients": ["List", "of", "ingredients"],
+				"ingredients": ["Cream", "Sugar"],
+				"ingredient_percentages": [40.5, null],
 				"allergy_info": "Allergy related information",
-				"dietary_certifications": "Name of dietary certifications"
+				"allergens": [{"code": "milk", "level": "contains"}] / null, if allergens are not declared,
+				"dietary_certifications": ["List", "of", "dietary", "certifications"],
+				"nutrition": {"serving_size_g": 143, "nutrients": [{"nutrient": "sugar", "unit": "g", "per_100g": 19.58,
+					"per_serving": 28}]} / null, if nutrition facts are not declared,
+				"categories": ["Non-Dairy > Frozen Dessert"],
+				"tags": ["List", "of", "tags"],
+				"publish_at": "2019-10-01 00:00:00" / "", if it's live since ever,
+				"unpublish_at": "2019-12-31 23:59:59" / "", if it's live for ever,
+				"market": {"market": "SG", "available": true, "prices": [{"currency": "SGD", "amount": 1290,
+					"effective_from": "2019-10-01 00:00:00", "effective_to": ""}]}, // only if market is asked for
+				"variants": [{"sku": "BJ-2190-PINT", "name": "Pint", "size": 473, "size_unit": "ml",
+					"packaging_image": "Link of packaging image", "nutrition": {"sugar": 21.5}}] // if it has any
 			}
 		}
 	*/
 	var (
-		isAuthorized  bool
-		response      *structs.ReadResponse
-		responseBytes []byte
-		responseErr   error
-		logIdentifier = "bennjerry.ReadData"
+		isAuthorized    bool
+		response        *structs.ReadResponse
+		responseBytes   []byte
+		responseErr     error
+		contentLanguage string
+		logIdentifier   = "bennjerry.ReadData"
+		ctx             = ginContext.Request.Context()
+		requestLogger   = logger.FromContext(ctx)
 	)
 
 	// defer block to recover and log if any error occurs
 	defer func() {
 		if r := recover(); r != nil {
-			logger.ZaloraStatsLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
 				constants.GenericErrorMessage, string(debug.Stack()))
 			serializer := utils.GetSerializer(constants.JsonSerializerType)
 			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
@@ -169,11 +257,46 @@ func ReadData(ginContext *gin.Context) {
 	}
 
 	productId := ginContext.Params.ByName("product_id")
-	// fetching data from product table using product id
-	// success: false, if some error occurs while running the query
-	// success: true, productData: {}, if requested product_id is not found or is inactive
-	productData, success := model.SelectFromProductByProductId(productId)
-	if !success {
+	marketCode, validMarket := "", true
+	if market := ginContext.Query(constants.MarketQueryParamName); market != "" {
+		marketCode, validMarket = model.CanonicalMarket(market)
+	}
+	// If URL param preview=1 is present, the product is read with the edits of its draft, whether it's live or not
+	isPreview := ginContext.DefaultQuery(constants.PreviewQueryParamName, "0") == "1"
+	var (
+		markets     []*structs.MarketStruct
+		productData *model.Product
+		previewData *structs.IceCreamDataStruct
+		draftStatus string
+		success     bool
+	)
+	if isPreview {
+		// success: true, previewData: nil, if requested product_id is not found
+		previewData, draftStatus, success = model.PreviewRecord(ctx, productId)
+		productData = &model.Product{}
+		if previewData != nil {
+			productData.Id, productData.ProductId = previewData.Id, previewData.ProductId
+		}
+	} else {
+		// fetching data from product table using product id
+		// success: false, if some error occurs while running the query
+		// success: true, productData: {}, if requested product_id is not found or is inactive
+		productData, success = model.SelectFromProductByProductId(ctx, productId)
+	}
+	if success && productData.ProductId != "" && marketCode != "" {
+		// Fetching availability and all prices of the product in the market, empty if it isn't listed there
+		markets, success = model.SelectFromProductMarketByProductIdPK(ctx, productData.Id, marketCode)
+	}
+	var variants []*structs.VariantStruct
+	if success && productData.ProductId != "" {
+		// Fetching formats the product is sold in, with their nutrition values
+		variants, success = model.SelectFromProductVariantByProductIdPK(ctx, productData.Id)
+	}
+	if !validMarket {
+		response = &structs.ReadResponse{
+			Message: constants.InvalidMarketErrorMessage,
+		}
+	} else if !success {
 		response = &structs.ReadResponse{
 			Message: constants.GenericErrorMessage,
 		}
@@ -186,35 +309,64 @@ func ReadData(ginContext *gin.Context) {
 			Success: true,
 			Message: constants.ReadSuccessMessage,
 		}
-		response.Data = &structs.IceCreamDataStruct{
-			Id:          productData.Id,
-			ProductId:   productData.ProductId,
-			Name:        productData.Name,
-			Description: productData.Description,
-			Story:       productData.Story,
-			ImageClosed: productData.ImageClosed,
-			ImageOpened: productData.ImageOpened,
-			AllergyInfo: productData.Allergy,
+		if previewData != nil {
+			// Edits of drafts are in the default locale, so previews are not translated
+			response.Data = previewData
+			response.DraftStatus = draftStatus
+		} else {
+			response.Data = &structs.IceCreamDataStruct{
+				Id:          productData.Id,
+				ProductId:   productData.ProductId,
+				Name:        productData.Name,
+				Description: productData.Description,
+				Story:       productData.Story,
+				ImageClosed: productData.ImageClosed,
+				ImageOpened: productData.ImageOpened,
+				AllergyInfo: productData.Allergy,
+				PublishAt:   productData.PublishAt,
+				UnpublishAt: productData.UnpublishAt,
+			}
+			// Fetching list of dietary certifications from relation table of product and dietary certification
+			response.Data.DietaryCertifications = model.SelectDietaryCertificationNameByProductIdPK(ctx, productData.Id)
+			// Fetching list of sourcing values from relation table of product and sourcing value
+			response.Data.SourcingValues = model.SelectSourcingValueNameByProductIdPK(ctx, productData.Id)
+			// Fetching list of ingredients from relation table of product and ingredient
+			response.Data.Ingredients = model.SelectIngredientFromProductIngredientByProductIdPK(ctx, productData.Id)
+			// Fetching list of allergens from relation table of product and allergen, if the product has declared them
+			if productData.AllergensDeclared == 1 {
+				response.Data.Allergens = model.SelectAllergenByProductIdPK(ctx, productData.Id)
+			}
+			// Fetching nutrition facts, with amounts per 100 g and per serving
+			response.Data.Nutrition = model.SelectNutritionByProductIdPK(ctx, productData.Id)
+			// Fetching paths of categories from relation table of product and category
+			response.Data.Categories = model.SelectCategoryPathByProductIdPK(ctx, productData.Id)
+			// Fetching list of tags from relation table of product and tag
+			response.Data.Tags = model.SelectTagNameByProductIdPK(ctx, productData.Id)
+			// Translating texts to the locales accepted by the client, as far as translations exist
+			locales := localization.ParseAcceptLanguage(ginContext.GetHeader(constants.AcceptLanguageHeaderName))
+			usedLocales := model.LocalizeRecord(ctx, response.Data, localization.FallbackChain(locales))
+			contentLanguage = strings.Join(usedLocales, ", ")
 		}
-		// Id of a Dietary Certification is in product table as a foreign key
-		// Using the same to fetch it's name from dietarycertification table
-		if productData.DietaryCertificationId != 0 {
-			dietaryCertification := model.SelectFromDietaryCertificationById(productData.DietaryCertificationId)
-			if dietaryCertification != nil {
-				response.Data.DietaryCertifications = dietaryCertification.Name
+		response.Data.Variants = variants
+		// Products not listed in the market are not available there and have no prices
+		if marketCode != "" {
+			response.Data.Market = &structs.MarketStruct{Market: marketCode, Prices: []*structs.PriceStruct{}}
+			if len(markets) > 0 {
+				response.Data.Market = model.EffectiveMarket(markets[0], time.Now())
 			}
 		}
-		// Fetching list of sourcing values from relation table of product and sourcing value
-		response.Data.SourcingValues = model.SelectSourcingValueNameByProductIdPK(productData.Id)
-		// Fetching list of ingredients from relation table of product and ingredient
-		response.Data.Ingredients = model.SelectIngredientNameFromProductIngredientByProductIdPK(productData.Id)
+	}
+	// Response differs by Accept-Language, so caches must not share it across languages
+	ginContext.Header("Vary", constants.AcceptLanguageHeaderName)
+	if contentLanguage != "" {
+		ginContext.Header("Content-Language", contentLanguage)
 	}
 	// Converting response structure to []byte
 	responseBytes, responseErr = json.Marshal(response)
 	// Calling common util function to send json response (utils/common.go)
 	serializer := utils.GetSerializer(constants.JsonSerializerType)
 	if responseErr != nil {
-		logger.ZaloraStatsLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
 			constants.JsonSerializationErrorMessage, responseErr.Error())
 		serializer.ReturnError(ginContext, http.StatusInternalServerError,
 			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
@@ -224,7 +376,9 @@ func ReadData(ginContext *gin.Context) {
 
 func UpdateData(ginContext *gin.Context) {
 	/*
-		To update information of an existing ice cream product by providing product_id
+		To edit information of an existing ice cream product by providing product_id
+		Edits are saved in the draft of the product, which goes live once it's submitted, approved and published
+		(see SubmitDraft, ApproveDraft, PublishDraft), till then it can be read with preview=1 (see ReadData)
 		Sample Url: "http://host/bennjerry/2190/"
 		Request Method: PUT
 		Request Data: product_id to be provided in the url, e.g. 2190 in sample url
@@ -235,9 +389,13 @@ func UpdateData(ginContext *gin.Context) {
 				"image_closed": "Link of closed image",
 				"sourcing_values": ["List", "of", "sourcing", "values"],
 				"allergy_info": "Allergy related information",
-				"dietary_certifications": "Name of dietary certifications"
+				"dietary_certifications": ["List", "of", "dietary", "certifications"]
 			},
 			"fields": "name,story,image_closed,sourcing_values,allergy_info,dietary_certifications"
+			Allergens are updated as a whole, e.g. "allergens": [] with "fields": "allergens" declares none
+			Nutrition facts are updated as a whole too, "nutrition": null with "fields": "nutrition" removes them
+			Categories (paths of existing categories) and tags replace those of the product, e.g. "categories": []
+			An empty publish_at or unpublish_at removes it from the schedule of the product
 		}
 		Response Data:
 		{
@@ -253,12 +411,14 @@ func UpdateData(ginContext *gin.Context) {
 		responseBytes []byte
 		responseErr   error
 		logIdentifier = "bennjerry.UpdateData"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
 	)
 
 	// defer block to recover and log if any error occurs
 	defer func() {
 		if r := recover(); r != nil {
-			logger.ZaloraStatsLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
 				constants.GenericErrorMessage, string(debug.Stack()))
 			serializer := utils.GetSerializer(constants.JsonSerializerType)
 			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
@@ -281,7 +441,7 @@ func UpdateData(ginContext *gin.Context) {
 	// fetching id (primary key) of ice cream product using product_id
 	// success: false, if some error occurs while running the query
 	// success: true, id: 0, if requested product_id is not found
-	id, success := model.SelectIdFromProductByProductId(productId)
+	id, success := model.SelectIdFromProductByProductId(ctx, productId)
 	if !success {
 		response = &structs.CreateUpdateDeleteResponse{
 			Message: constants.GenericErrorMessage,
@@ -296,7 +456,7 @@ func UpdateData(ginContext *gin.Context) {
 		// converting post form data to structure
 		umMarshalErr := json.Unmarshal([]byte(postData), &iceCreamData)
 		if umMarshalErr != nil {
-			logger.ZaloraStatsLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
 				constants.UnMarshalErrorString, umMarshalErr.Error())
 			response = &structs.CreateUpdateDeleteResponse{
 				Message: umMarshalErr.Error(),
@@ -315,12 +475,75 @@ func UpdateData(ginContext *gin.Context) {
 			postFields = strings.Replace(postFields, " ", "", -1)
 			// Splitting postFields on comma and converting it to map {"string":bool}
 			fieldMap := utils.ListToMap(strings.Split(postFields, ","))
-			// Calling function to execute queries in an atomic transaction
-			success := model.UpdateRecord(id, iceCreamData, fieldMap)
+			validFields := model.ValidateFields(fieldMap)
+			_, ingredientsExist := fieldMap["ingredients"]
+			_, nutritionExists := fieldMap["nutrition"]
+			_, tagsExist := fieldMap["tags"]
+			_, publishAtExists := fieldMap["publish_at"]
+			_, unpublishAtExists := fieldMap["unpublish_at"]
+			valid := true
+			if _, exists := fieldMap["allergens"]; exists {
+				// Allergens must be codes of the taxonomy, so that products can reliably be filtered by them
+				valid, success = model.ValidateAllergens(ctx, iceCreamData.Allergens)
+			}
+			validCategories := true
+			if _, exists := fieldMap["categories"]; exists && success {
+				// Categories are managed through the catalog apis, so products are only moved to existing ones
+				validCategories, success = model.ValidateCategories(ctx, iceCreamData.Categories)
+			}
+			validImages := true
 			if success {
+				// Urls of the image storage must be of uploaded images, external urls are stored as they are
+				imageURLs := make([]string, 0, 2)
+				if _, exists := fieldMap["image_closed"]; exists {
+					imageURLs = append(imageURLs, iceCreamData.ImageClosed)
+				}
+				if _, exists := fieldMap["image_open"]; exists {
+					imageURLs = append(imageURLs, iceCreamData.ImageOpened)
+				}
+				validImages, success = model.ValidateImageURLs(ctx, imageURLs...)
+			}
+			if !validFields {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.RequestInvalidErrorMessage,
+				}
+			} else if !success {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.GenericErrorMessage,
+				}
+			} else if !valid {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.InvalidAllergenErrorMessage,
+				}
+			} else if !validImages {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.InvalidImageErrorMessage,
+				}
+			} else if ingredientsExist && !model.ValidateIngredients(iceCreamData.Ingredients) {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.InvalidIngredientErrorMessage,
+				}
+			} else if nutritionExists && !model.ValidateNutrition(iceCreamData.Nutrition) {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.InvalidNutritionErrorMessage,
+				}
+			} else if !validCategories {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.InvalidCategoryErrorMessage,
+				}
+			} else if tagsExist && !model.ValidateTags(iceCreamData.Tags) {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.InvalidTagErrorMessage,
+				}
+			} else if (publishAtExists || unpublishAtExists) && !model.ValidateSchedule(iceCreamData) {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.InvalidScheduleErrorMessage,
+				}
+			} else if model.SaveDraft(ctx, id, iceCreamData, fieldMap) {
+				// SaveDraft executes queries in an atomic transaction, the product itself isn't changed
 				response = &structs.CreateUpdateDeleteResponse{
 					Success: true,
-					Message: constants.UpdateSuccessMessage,
+					Message: constants.DraftSaveSuccessMessage,
 					Id:      id,
 				}
 			} else {
@@ -335,7 +558,7 @@ func UpdateData(ginContext *gin.Context) {
 	// Calling common util function to send json response (utils/common.go)
 	serializer := utils.GetSerializer(constants.JsonSerializerType)
 	if responseErr != nil {
-		logger.ZaloraStatsLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
 			constants.JsonSerializationErrorMessage, responseErr.Error())
 		serializer.ReturnError(ginContext, http.StatusInternalServerError,
 			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
@@ -363,12 +586,14 @@ func DeleteData(ginContext *gin.Context) {
 		responseBytes []byte
 		responseErr   error
 		logIdentifier = "bennjerry.DeleteData"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
 	)
 
 	// defer block to recover and log if any error occurs
 	defer func() {
 		if r := recover(); r != nil {
-			logger.ZaloraStatsLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
 				constants.GenericErrorMessage, string(debug.Stack()))
 			serializer := utils.GetSerializer(constants.JsonSerializerType)
 			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
@@ -394,7 +619,7 @@ func DeleteData(ginContext *gin.Context) {
 		// Primary key, id needs to be fetched because references for record in other tables need to be deleted first
 		// success: false, if an error occurs while running the query
 		// success: true, id: 0, if record is not found for the product_id
-		id, success := model.SelectIdFromProductByProductId(productId)
+		id, success := model.SelectIdFromProductByProductId(ctx, productId)
 		if !success {
 			response = &structs.CreateUpdateDeleteResponse{
 				Message: constants.GenericErrorMessage,
@@ -405,7 +630,7 @@ func DeleteData(ginContext *gin.Context) {
 			}
 		} else {
 			// Calling function to execute queries in an atomic transaction
-			success = model.DropRecord(id)
+			success = model.DropRecord(ctx, id)
 			if success {
 				response = &structs.CreateUpdateDeleteResponse{
 					Success: true,
@@ -423,7 +648,7 @@ func DeleteData(ginContext *gin.Context) {
 		// In read operation, an ice cream product will be fetched only if it's not inactive
 		// success: false, if some error occurs while running query
 		// success: true, id: 0, if requested product_id is not found
-		id, success := model.SoftDeleteFromProductByProductId(productId)
+		id, success := model.SoftDeleteFromProductByProductId(ctx, productId)
 		if !success {
 			response = &structs.CreateUpdateDeleteResponse{
 				Message: constants.GenericErrorMessage,
@@ -445,10 +670,3111 @@ func DeleteData(ginContext *gin.Context) {
 	// Calling common util function to send json response (utils/common.go)
 	serializer := utils.GetSerializer(constants.JsonSerializerType)
 	if responseErr != nil {
-		logger.ZaloraStatsLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func ReadSimilar(ginContext *gin.Context) {
+	/*
+		To fetch live ice cream products most similar to a live product, by the overlap of their ingredients and of
+		their dietary certifications, products sharing neither aren't listed
+		Products are compared in memory, loaded again once the catalog changes
+		Sample Url: "http://host/bennjerry/123/similar/" or "http://host/bennjerry/123/similar/?limit=5"
+		Request Method: GET
+		Request Data: product_id to be provided in the url, e.g. 123 in sample url
+		URL Param: limit=5, number of products listed at most, 10 by default and 50 at most
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true / false,
+			"data": [{"productId": "456", "name": "Name of Ice Cream", "score": 1.1}]
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.SimilarProductListResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.ReadSimilar"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	limit, limitErr := strconv.Atoi(ginContext.DefaultQuery(constants.SimilarLimitQueryParamName,
+		strconv.Itoa(constants.SimilarDefaultLimit)))
+	if limitErr != nil || limit < 1 || limit > constants.SimilarMaxLimit {
+		response = &structs.SimilarProductListResponse{
+			Message: constants.InvalidSimilarLimitErrorMessage,
+		}
+	} else if matches, found, success := similarity.Similar(ctx, productId, limit); !success {
+		response = &structs.SimilarProductListResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if !found {
+		response = &structs.SimilarProductListResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	} else {
+		response = &structs.SimilarProductListResponse{
+			Success: true,
+			Message: constants.ReadSuccessMessage,
+			Data:    make([]*structs.SimilarProductStruct, 0, len(matches)),
+		}
+		for _, match := range matches {
+			response.Data = append(response.Data, &structs.SimilarProductStruct{
+				ProductId: match.Item.ProductId,
+				Name:      match.Item.Name,
+				Score:     match.Score,
+			})
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func ReadRevision(ginContext *gin.Context) {
+	/*
+		To fetch a stored revision (full snapshot) of an ice cream product by providing product_id and revision
+		Every create and update of a product stores a new revision, starting from 1
+		Sample Url: "http://host/bennjerry/2190/revisions/3/"
+		Request Method: GET
+		Request Data: product_id and revision to be provided in the url, e.g. 2190 and 3 in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true / false,
+			"revision": 3,
+			"created_at": "2019-10-10 10:10:10",
+			"data": {
+				"productId": "123",
+				"name": "Name of Ice Cream",
+				"description": "Description of Ice Cream",
+				"story": "Story of Ice Cream",
+				"image_closed": "Link of closed image",
+				"image_open": "Link of open image",
+				"sourcing_values": ["List", "of", "sourcing", "values"],
+				"ingredients": ["List", "of", "ingredients"],
+				"allergy_info": "Allergy related information",
+				"dietary_certifications": ["List", "of", "dietary", "certifications"]
+			}
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.RevisionResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.ReadRevision"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	revision, revisionErr := strconv.Atoi(ginContext.Params.ByName("revision"))
+	if revisionErr != nil || revision <= 0 {
+		response = &structs.RevisionResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else {
+		// fetching id (primary key) of ice cream product using product_id
+		// success: false, if some error occurs while running the query
+		// success: true, id: 0, if requested product_id is not found
+		id, success := model.SelectIdFromProductByProductId(ctx, productId)
+		if !success {
+			response = &structs.RevisionResponse{
+				Message: constants.GenericErrorMessage,
+			}
+		} else if id == 0 {
+			response = &structs.RevisionResponse{
+				Message: constants.NoRecordsFoundMessage,
+			}
+		} else {
+			// success: true, productRevision: nil, if requested revision is not found
+			productRevision, success := model.SelectFromProductRevisionByProductIdPK(ctx, id, revision)
+			if !success {
+				response = &structs.RevisionResponse{
+					Message: constants.GenericErrorMessage,
+				}
+			} else if productRevision == nil {
+				response = &structs.RevisionResponse{
+					Message: constants.NoRecordsFoundMessage,
+				}
+			} else {
+				var iceCreamData *structs.IceCreamDataStruct
+				// snapshot is stored as json of the ice cream data structure
+				umMarshalErr := json.Unmarshal([]byte(productRevision.Snapshot), &iceCreamData)
+				if umMarshalErr != nil {
+					requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+						constants.UnMarshalErrorString, umMarshalErr.Error())
+					response = &structs.RevisionResponse{
+						Message: constants.GenericErrorMessage,
+					}
+				} else {
+					response = &structs.RevisionResponse{
+						Success:   true,
+						Message:   constants.ReadSuccessMessage,
+						Revision:  productRevision.Revision,
+						CreatedAt: productRevision.CreatedAt,
+						Data:      iceCreamData,
+					}
+				}
+			}
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
 			constants.JsonSerializationErrorMessage, responseErr.Error())
 		serializer.ReturnError(ginContext, http.StatusInternalServerError,
 			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
 	}
 	serializer.ReturnOk(ginContext, responseBytes)
 }
+
+func RollbackRevision(ginContext *gin.Context) {
+	/*
+		To restore an ice cream product to how it looked in a previous revision by providing product_id and revision
+		The snapshot of the revision is re-applied through an atomic update, which is itself stored as a new revision
+		Sample Url: "http://host/bennjerry/2190/revisions/3/rollback/"
+		Request Method: POST
+		Request Data: product_id and revision to be provided in the url, e.g. 2190 and 3 in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.RollbackRevision"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	revision, revisionErr := strconv.Atoi(ginContext.Params.ByName("revision"))
+	if revisionErr != nil || revision <= 0 {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else {
+		// fetching id (primary key) of ice cream product using product_id
+		// success: false, if some error occurs while running the query
+		// success: true, id: 0, if requested product_id is not found
+		id, success := model.SelectIdFromProductByProductId(ctx, productId)
+		if !success {
+			response = &structs.CreateUpdateDeleteResponse{
+				Message: constants.GenericErrorMessage,
+			}
+		} else if id == 0 {
+			response = &structs.CreateUpdateDeleteResponse{
+				Message: constants.NoRecordsFoundMessage,
+			}
+		} else {
+			// Calling function to re-apply the revision in an atomic transaction
+			// found: false, if requested revision doesn't exist for the product
+			found, success := model.RollbackRecord(ctx, id, revision)
+			if !found {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.NoRecordsFoundMessage,
+				}
+			} else if !success {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.GenericErrorMessage,
+				}
+			} else {
+				response = &structs.CreateUpdateDeleteResponse{
+					Success: true,
+					Message: constants.RollbackSuccessMessage,
+					Id:      id,
+				}
+			}
+		}
+	}
+	// converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func SubmitDraft(ginContext *gin.Context) {
+	/*
+		To submit the draft of an ice cream product for review by providing product_id, the draft must be in draft status
+		Sample Url: "http://host/bennjerry/2190/draft/submit/"
+		Request Method: POST
+		Request Data: product_id to be provided in the url, e.g. 2190 in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.SubmitDraft"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	response = changeDraft(ctx, productId, func(ctx context.Context, id int) model.DraftResult {
+		return model.ChangeDraftStatus(ctx, id, constants.DraftStatusDraft, constants.DraftStatusInReview)
+	}, constants.SubmitSuccessMessage)
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func ApproveDraft(ginContext *gin.Context) {
+	/*
+		To approve the draft of an ice cream product submitted for review by providing product_id
+		Only users with the reviewer role (in their token) may approve drafts, others get status 403
+		Sample Url: "http://host/bennjerry/2190/draft/approve/"
+		Request Method: POST
+		Request Data: product_id to be provided in the url, e.g. 2190 in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.ApproveDraft"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	// Only reviewers may review drafts, the role would've been dropped in ginContext object by the auth middleware
+	if role, _ := ginContext.Get(constants.RoleKeyName); role != constants.RoleReviewer {
+		metrics.AuthFailures.WithLabels(constants.AuthFailureForbiddenRole).Inc()
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnError(ginContext, http.StatusForbidden, constants.ReviewerRoleRequiredMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	response = changeDraft(ctx, productId, func(ctx context.Context, id int) model.DraftResult {
+		return model.ChangeDraftStatus(ctx, id, constants.DraftStatusInReview, constants.DraftStatusApproved)
+	}, constants.ApproveSuccessMessage)
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func RejectDraft(ginContext *gin.Context) {
+	/*
+		To send the draft of an ice cream product submitted for review back to draft by providing product_id,
+		so that it's edited and submitted again
+		Only users with the reviewer role (in their token) may reject drafts, others get status 403
+		Sample Url: "http://host/bennjerry/2190/draft/reject/"
+		Request Method: POST
+		Request Data: product_id to be provided in the url, e.g. 2190 in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.RejectDraft"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	// Only reviewers may review drafts, the role would've been dropped in ginContext object by the auth middleware
+	if role, _ := ginContext.Get(constants.RoleKeyName); role != constants.RoleReviewer {
+		metrics.AuthFailures.WithLabels(constants.AuthFailureForbiddenRole).Inc()
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnError(ginContext, http.StatusForbidden, constants.ReviewerRoleRequiredMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	response = changeDraft(ctx, productId, func(ctx context.Context, id int) model.DraftResult {
+		return model.ChangeDraftStatus(ctx, id, constants.DraftStatusInReview, constants.DraftStatusDraft)
+	}, constants.RejectSuccessMessage)
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func PublishDraft(ginContext *gin.Context) {
+	/*
+		To publish the approved draft of an ice cream product by providing product_id
+		Edits of the draft are applied to the product and stored as its next revision, then the draft is deleted
+		Sample Url: "http://host/bennjerry/2190/draft/publish/"
+		Request Method: POST
+		Request Data: product_id to be provided in the url, e.g. 2190 in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.PublishDraft"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	response = changeDraft(ctx, productId, model.PublishDraft, constants.PublishSuccessMessage)
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func DeleteDraft(ginContext *gin.Context) {
+	/*
+		To discard the draft of an ice cream product by providing product_id, whatever its status
+		Sample Url: "http://host/bennjerry/2190/draft/"
+		Request Method: DELETE
+		Request Data: product_id to be provided in the url, e.g. 2190 in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.DeleteDraft"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	response = changeDraft(ctx, productId, model.DiscardDraft, constants.PermanentDeleteSuccessMessage)
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func ReadTranslations(ginContext *gin.Context) {
+	/*
+		To fetch all translations of an ice cream product by providing product_id
+		Sample Url: "http://host/bennjerry/2190/translations/"
+		Request Method: GET
+		Request Data: product_id to be provided in the url, e.g. 2190 in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true / false,
+			"data": [
+				{"locale": "ms-MY", "name": "Nama Ais Krim", "description": "", "story": "Kisah Ais Krim"}
+			]
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.TranslationListResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.ReadTranslations"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	// fetching id (primary key) of ice cream product using product_id
+	// success: false, if some error occurs while running the query
+	// success: true, id: 0, if requested product_id is not found
+	id, success := model.SelectIdFromProductByProductId(ctx, productId)
+	if !success {
+		response = &structs.TranslationListResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if id == 0 {
+		response = &structs.TranslationListResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	} else {
+		translations, success := model.SelectFromProductTranslationByProductIdPK(ctx, id)
+		if !success {
+			response = &structs.TranslationListResponse{
+				Message: constants.GenericErrorMessage,
+			}
+		} else {
+			response = &structs.TranslationListResponse{
+				Success: true,
+				Message: constants.ReadSuccessMessage,
+				Data:    translations,
+			}
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func UpdateTranslation(ginContext *gin.Context) {
+	/*
+		To store the translation of an ice cream product to a locale, replacing the existing one of the locale
+		Texts left empty aren't translated, ReadData falls back to another locale for them
+		Sample Url: "http://host/bennjerry/2190/translations/ms-MY/"
+		Request Method: PUT
+		Request Data: product_id and locale to be provided in the url, e.g. 2190 and ms-MY in sample url
+		Post Form Data:
+		{
+			"data": '{"name": "Nama Ais Krim", "description": "Keterangan Ais Krim", "story": "Kisah Ais Krim"}'
+		}
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		translation   *structs.TranslationStruct
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.UpdateTranslation"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	locale, validLocale := translationLocale(ginContext.Params.ByName("locale"))
+	postData := ginContext.DefaultPostForm("data", "")
+	umMarshalErr := json.Unmarshal([]byte(postData), &translation)
+	if !validLocale {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidLocaleErrorMessage,
+		}
+	} else if umMarshalErr != nil || translation == nil ||
+		(translation.Name == "" && translation.Description == "" && translation.Story == "") {
+		if umMarshalErr != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.UnMarshalErrorString, umMarshalErr.Error())
+		}
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else {
+		// fetching id (primary key) of ice cream product using product_id
+		// success: false, if some error occurs while running the query
+		// success: true, id: 0, if requested product_id is not found
+		id, success := model.SelectIdFromProductByProductId(ctx, productId)
+		if !success {
+			response = &structs.CreateUpdateDeleteResponse{
+				Message: constants.GenericErrorMessage,
+			}
+		} else if id == 0 {
+			response = &structs.CreateUpdateDeleteResponse{
+				Message: constants.NoRecordsFoundMessage,
+			}
+		} else {
+			// locale of the url takes precedence over one sent in data
+			translation.Locale = locale
+			if !model.InsertIntoProductTranslation(ctx, id, translation) {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.GenericErrorMessage,
+				}
+			} else {
+				response = &structs.CreateUpdateDeleteResponse{
+					Success: true,
+					Message: constants.UpdateSuccessMessage,
+					Id:      id,
+				}
+			}
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func DeleteTranslation(ginContext *gin.Context) {
+	/*
+		To delete the translation of an ice cream product to a locale
+		Sample Url: "http://host/bennjerry/2190/translations/ms-MY/"
+		Request Method: DELETE
+		Request Data: product_id and locale to be provided in the url, e.g. 2190 and ms-MY in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.DeleteTranslation"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	locale, validLocale := translationLocale(ginContext.Params.ByName("locale"))
+	if !validLocale {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidLocaleErrorMessage,
+		}
+	} else {
+		// fetching id (primary key) of ice cream product using product_id
+		// success: false, if some error occurs while running the query
+		// success: true, id: 0, if requested product_id is not found
+		id, success := model.SelectIdFromProductByProductId(ctx, productId)
+		found := false
+		if success && id != 0 {
+			// found: false, if the product has no translation to the locale
+			found, success = model.DeleteFromProductTranslationByProductIdPK(ctx, id, locale)
+		}
+		if !success {
+			response = &structs.CreateUpdateDeleteResponse{
+				Message: constants.GenericErrorMessage,
+			}
+		} else if !found {
+			response = &structs.CreateUpdateDeleteResponse{
+				Message: constants.NoRecordsFoundMessage,
+			}
+		} else {
+			response = &structs.CreateUpdateDeleteResponse{
+				Success: true,
+				Message: constants.PermanentDeleteSuccessMessage,
+				Id:      id,
+			}
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func ReadMarkets(ginContext *gin.Context) {
+	/*
+		To fetch all markets of an ice cream product with its availability and all prices (past, current and future)
+		Sample Url: "http://host/bennjerry/2190/markets/"
+		Request Method: GET
+		Request Data: product_id to be provided in the url, e.g. 2190 in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true / false,
+			"data": [
+				{"market": "SG", "available": true, "prices": [{"currency": "SGD", "amount": 1290,
+					"effective_from": "2019-10-01 00:00:00", "effective_to": ""}]}
+			]
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.MarketListResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.ReadMarkets"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	// fetching id (primary key) of ice cream product using product_id
+	// success: false, if some error occurs while running the query
+	// success: true, id: 0, if requested product_id is not found
+	id, success := model.SelectIdFromProductByProductId(ctx, productId)
+	if !success {
+		response = &structs.MarketListResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if id == 0 {
+		response = &structs.MarketListResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	} else if markets, success := model.SelectFromProductMarketByProductIdPK(ctx, id, ""); !success {
+		response = &structs.MarketListResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else {
+		response = &structs.MarketListResponse{
+			Success: true,
+			Message: constants.ReadSuccessMessage,
+			Data:    markets,
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func UpdateMarket(ginContext *gin.Context) {
+	/*
+		To list an ice cream product in a market, or change its availability and prices there,
+		by providing product_id and market (country code)
+		Prices of the market are replaced as a whole by the prices sent
+		Sample Url: "http://host/bennjerry/2190/markets/SG/"
+		Request Method: PUT
+		Request Data: product_id and market to be provided in the url, e.g. 2190 and SG in sample url
+		{
+			"data": {
+				"available": true,
+				"prices": [
+					{"currency": "SGD", "amount": 1290, "effective_from": "2019-10-01 00:00:00",
+						"effective_to": "2020-01-01 00:00:00"},
+					{"currency": "SGD", "amount": 1390, "effective_from": "2020-01-01 00:00:00", "effective_to": ""}
+				]
+			}
+		}
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		market        *structs.MarketStruct
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.UpdateMarket"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	code, validMarket := model.CanonicalMarket(ginContext.Params.ByName("market"))
+	postData := ginContext.DefaultPostForm("data", "")
+	umMarshalErr := json.Unmarshal([]byte(postData), &market)
+	if !validMarket {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidMarketErrorMessage,
+		}
+	} else if umMarshalErr != nil || market == nil {
+		if umMarshalErr != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.UnMarshalErrorString, umMarshalErr.Error())
+		}
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else if !model.ValidateMarket(market) {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidPriceErrorMessage,
+		}
+	} else {
+		// fetching id (primary key) of ice cream product using product_id
+		// success: false, if some error occurs while running the query
+		// success: true, id: 0, if requested product_id is not found
+		id, success := model.SelectIdFromProductByProductId(ctx, productId)
+		if !success {
+			response = &structs.CreateUpdateDeleteResponse{
+				Message: constants.GenericErrorMessage,
+			}
+		} else if id == 0 {
+			response = &structs.CreateUpdateDeleteResponse{
+				Message: constants.NoRecordsFoundMessage,
+			}
+		} else {
+			// market of the url takes precedence over one sent in data
+			market.Market = code
+			// Calling function to execute queries in an atomic transaction
+			if model.ReplaceMarket(ctx, id, market) {
+				response = &structs.CreateUpdateDeleteResponse{
+					Success: true,
+					Message: constants.UpdateSuccessMessage,
+					Id:      id,
+				}
+			} else {
+				response = &structs.CreateUpdateDeleteResponse{
+					Message: constants.GenericErrorMessage,
+				}
+			}
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func DeleteMarket(ginContext *gin.Context) {
+	/*
+		To remove an ice cream product from a market, along with its prices there,
+		by providing product_id and market (country code)
+		Sample Url: "http://host/bennjerry/2190/markets/SG/"
+		Request Method: DELETE
+		Request Data: product_id and market to be provided in the url, e.g. 2190 and SG in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.DeleteMarket"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	code, validMarket := model.CanonicalMarket(ginContext.Params.ByName("market"))
+	if !validMarket {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidMarketErrorMessage,
+		}
+	} else if id, success := model.SelectIdFromProductByProductId(ctx, productId); !success {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if id == 0 {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	} else if found, success := model.DeleteFromProductMarket(ctx, id, code); !success {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if !found {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	} else {
+		response = &structs.CreateUpdateDeleteResponse{
+			Success: true,
+			Message: constants.PermanentDeleteSuccessMessage,
+			Id:      id,
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func ReadVariants(ginContext *gin.Context) {
+	/*
+		To fetch all variants (formats) of an ice cream product, e.g. pint, mini cup or bar
+		Sample Url: "http://host/bennjerry/2190/variants/"
+		Request Method: GET
+		Request Data: product_id to be provided in the url, e.g. 2190 in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true / false,
+			"data": [
+				{"sku": "BJ-2190-PINT", "name": "Pint", "size": 473, "size_unit": "ml",
+					"packaging_image": "Link of packaging image", "nutrition": {"sugar": 21.5}}
+			]
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.VariantListResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.ReadVariants"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	// fetching id (primary key) of ice cream product using product_id
+	// success: false, if some error occurs while running the query
+	// success: true, id: 0, if requested product_id is not found
+	id, success := model.SelectIdFromProductByProductId(ctx, productId)
+	if !success {
+		response = &structs.VariantListResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if id == 0 {
+		response = &structs.VariantListResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	} else if variants, success := model.SelectFromProductVariantByProductIdPK(ctx, id); !success {
+		response = &structs.VariantListResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else {
+		response = &structs.VariantListResponse{
+			Success: true,
+			Message: constants.ReadSuccessMessage,
+			Data:    variants,
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func CreateVariant(ginContext *gin.Context) {
+	/*
+		To add a variant (format) to an ice cream product by providing product_id, its sku must not exist already
+		Sample Url: "http://host/bennjerry/2190/variants/"
+		Request Method: POST
+		Request Data: product_id to be provided in the url, e.g. 2190 in sample url
+		{
+			"data": {
+				"sku": "BJ-2190-PINT",
+				"name": "Pint",
+				"size": 473,
+				"size_unit": "ml",
+				"packaging_image": "Link of packaging image",
+				"nutrition": {"sugar": 21.5}
+			}
+		}
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		variant       *structs.VariantStruct
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.CreateVariant"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	postData := ginContext.DefaultPostForm("data", "")
+	umMarshalErr := json.Unmarshal([]byte(postData), &variant)
+	if umMarshalErr != nil || variant == nil {
+		if umMarshalErr != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.UnMarshalErrorString, umMarshalErr.Error())
+		}
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else {
+		response = saveVariant(ginContext, productId, variant, true)
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func UpdateVariant(ginContext *gin.Context) {
+	/*
+		To change a variant (format) of an ice cream product by providing product_id and sku of the variant
+		The variant is replaced as a whole, i.e. name, size, packaging image and nutrition values
+		Sample Url: "http://host/bennjerry/2190/variants/BJ-2190-PINT/"
+		Request Method: PUT
+		Request Data: product_id and sku to be provided in the url, e.g. 2190 and BJ-2190-PINT in sample url
+		{
+			"data": {
+				"name": "Pint",
+				"size": 465,
+				"size_unit": "ml",
+				"packaging_image": "Link of packaging image",
+				"nutrition": {"sugar": 21.5}
+			}
+		}
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		variant       *structs.VariantStruct
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.UpdateVariant"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	postData := ginContext.DefaultPostForm("data", "")
+	umMarshalErr := json.Unmarshal([]byte(postData), &variant)
+	if umMarshalErr != nil || variant == nil {
+		if umMarshalErr != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.UnMarshalErrorString, umMarshalErr.Error())
+		}
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else {
+		// sku of the url takes precedence over one sent in data
+		variant.Sku = ginContext.Params.ByName("sku")
+		response = saveVariant(ginContext, productId, variant, false)
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func DeleteVariant(ginContext *gin.Context) {
+	/*
+		To delete a variant (format) of an ice cream product by providing product_id and sku of the variant
+		Sample Url: "http://host/bennjerry/2190/variants/BJ-2190-PINT/"
+		Request Method: DELETE
+		Request Data: product_id and sku to be provided in the url, e.g. 2190 and BJ-2190-PINT in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.DeleteVariant"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	productId := ginContext.Params.ByName("product_id")
+	sku := ginContext.Params.ByName("sku")
+	// fetching id (primary key) of ice cream product using product_id
+	// success: false, if some error occurs while running the query
+	// success: true, id: 0, if requested product_id is not found
+	if id, success := model.SelectIdFromProductByProductId(ctx, productId); !success {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if id == 0 {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	} else {
+		// Calling function to execute queries in an atomic transaction
+		response = variantChangeResponse(model.DeleteVariant(ctx, id, sku), id,
+			constants.PermanentDeleteSuccessMessage)
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func ReadAllergens(ginContext *gin.Context) {
+	/*
+		To fetch the allergen taxonomy, i.e. codes accepted in "allergens" of an ice cream product
+		Sample Url: "http://host/catalog/allergens/"
+		Request Method: GET
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true / false,
+			"data": [{"code": "milk", "name": "Milk"}, {"code": "nuts", "name": "Nuts"}]
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.AllergenListResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.ReadAllergens"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	allergens, success := model.SelectFromAllergen(ctx)
+	if !success {
+		response = &structs.AllergenListResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else {
+		response = &structs.AllergenListResponse{
+			Success: true,
+			Message: constants.ReadSuccessMessage,
+			Data:    make([]*structs.AllergenTaxonomyStruct, 0, len(allergens)),
+		}
+		for _, allergen := range allergens {
+			response.Data = append(response.Data, &structs.AllergenTaxonomyStruct{
+				Code: allergen.Code,
+				Name: allergen.Name,
+			})
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func ReadFreeFromAllergen(ginContext *gin.Context) {
+	/*
+		To fetch live ice cream products free of an allergen by providing its code
+		Products which haven't declared their allergens are never listed, as they can't be told free of it
+		Sample Url: "http://host/catalog/free-from/nuts/" or "http://host/catalog/free-from/nuts/?include_may_contain=1"
+		Request Method: GET
+		Request Data: allergen code to be provided in the url, e.g. nuts in sample url
+		URL Param: include_may_contain=1, if products that may contain traces of the allergen are to be listed too
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true / false,
+			"data": [{"productId": "123", "name": "Name of Ice Cream"}]
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.ProductListResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.ReadFreeFromAllergen"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	allergen := &structs.AllergenStruct{
+		Code:  ginContext.Params.ByName("allergen"),
+		Level: constants.AllergenLevelContains,
+	}
+	includeMayContain := ginContext.DefaultQuery("include_may_contain", "0") == "1"
+	// An unknown code is refused, as listing every product as free of it would be misleading
+	valid, success := model.ValidateAllergens(ctx, []*structs.AllergenStruct{allergen})
+	if !success {
+		response = &structs.ProductListResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if !valid {
+		response = &structs.ProductListResponse{
+			Message: constants.InvalidAllergenErrorMessage,
+		}
+	} else if productList, success := model.SelectFromProductFreeFromAllergen(ctx, allergen.Code,
+		includeMayContain); !success {
+		response = &structs.ProductListResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else {
+		response = &structs.ProductListResponse{
+			Success: true,
+			Message: constants.ReadSuccessMessage,
+			Data:    make([]*structs.ProductSummaryStruct, 0, len(productList)),
+		}
+		for _, product := range productList {
+			response.Data = append(response.Data, &structs.ProductSummaryStruct{
+				ProductId: product.ProductId,
+				Name:      product.Name,
+			})
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func ReadVocabulary(ginContext *gin.Context) {
+	/*
+		To fetch all entries of a vocabulary with the number of products using them
+		Vocabularies: ingredients, sourcing_values, dietary_certifications, tags
+		Sample Url: "http://host/catalog/vocabularies/ingredients/"
+		Request Method: GET
+		Request Data: vocabulary to be provided in the url, e.g. ingredients in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true / false,
+			"data": [
+				{"id": 3, "name": "Cocoa", "product_count": 12, "translations": {"ms": "Koko", "th": "โกโก้"}},
+				{"id": 7, "name": "Cream", "product_count": 0, "translations": {}}
+			]
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.VocabularyListResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.ReadVocabulary"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	vocabulary, exists := model.Vocabularies[ginContext.Params.ByName("vocabulary")]
+	if !exists {
+		response = &structs.VocabularyListResponse{
+			Message: constants.UnknownVocabularyErrorMessage,
+		}
+	} else {
+		entries, success := model.SelectVocabularyWithUsage(ctx, vocabulary)
+		var translations map[int]map[string]string
+		if success {
+			translations, success = model.SelectFromVocabularyTranslation(ctx, vocabulary)
+		}
+		if !success {
+			response = &structs.VocabularyListResponse{
+				Message: constants.GenericErrorMessage,
+			}
+		} else {
+			response = &structs.VocabularyListResponse{
+				Success: true,
+				Message: constants.ReadSuccessMessage,
+				Data:    make([]*structs.VocabularyEntryStruct, 0, len(entries)),
+			}
+			for _, entry := range entries {
+				entryTranslations := translations[entry.Id]
+				if entryTranslations == nil {
+					entryTranslations = make(map[string]string)
+				}
+				response.Data = append(response.Data, &structs.VocabularyEntryStruct{
+					Id:           entry.Id,
+					Name:         entry.Name,
+					ProductCount: entry.ProductCount,
+					Translations: entryTranslations,
+				})
+			}
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func CreateVocabularyEntry(ginContext *gin.Context) {
+	/*
+		To add a new entry to a vocabulary
+		Sample Url: "http://host/catalog/vocabularies/ingredients/"
+		Request Method: POST
+		Request Data: vocabulary to be provided in the url, e.g. ingredients in sample url
+		{
+			"name": "Name of the entry"
+		}
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.CreateVocabularyEntry"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	vocabulary, exists := model.Vocabularies[ginContext.Params.ByName("vocabulary")]
+	name := strings.TrimSpace(ginContext.DefaultPostForm("name", ""))
+	if !exists {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.UnknownVocabularyErrorMessage,
+		}
+	} else if name == "" {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else {
+		// Calling function to execute queries in an atomic transaction
+		id, result := model.CreateVocabularyEntry(ctx, vocabulary, name)
+		response = vocabularyChangeResponse(result, id, constants.CreateSuccessMessage)
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func RenameVocabularyEntry(ginContext *gin.Context) {
+	/*
+		To rename an entry of a vocabulary, all products using the entry carry the new name
+		Sample Url: "http://host/catalog/vocabularies/ingredients/3/"
+		Request Method: PUT
+		Request Data: vocabulary and id of the entry to be provided in the url, e.g. ingredients and 3 in sample url
+		{
+			"name": "New name of the entry"
+		}
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 3/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.RenameVocabularyEntry"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	vocabulary, exists := model.Vocabularies[ginContext.Params.ByName("vocabulary")]
+	id, idErr := strconv.Atoi(ginContext.Params.ByName("id"))
+	name := strings.TrimSpace(ginContext.DefaultPostForm("name", ""))
+	if !exists {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.UnknownVocabularyErrorMessage,
+		}
+	} else if idErr != nil || id <= 0 || name == "" {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else {
+		// Calling function to execute queries in an atomic transaction
+		result := model.RenameVocabularyEntry(ctx, vocabulary, id, name)
+		response = vocabularyChangeResponse(result, id, constants.UpdateSuccessMessage)
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func DeleteVocabularyEntry(ginContext *gin.Context) {
+	/*
+		To delete an entry of a vocabulary, entries used by any product can't be deleted
+		Sample Url: "http://host/catalog/vocabularies/ingredients/3/"
+		Request Method: DELETE
+		Request Data: vocabulary and id of the entry to be provided in the url, e.g. ingredients and 3 in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 3/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.DeleteVocabularyEntry"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	vocabulary, exists := model.Vocabularies[ginContext.Params.ByName("vocabulary")]
+	id, idErr := strconv.Atoi(ginContext.Params.ByName("id"))
+	if !exists {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.UnknownVocabularyErrorMessage,
+		}
+	} else if idErr != nil || id <= 0 {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else {
+		// Calling function to execute queries in an atomic transaction
+		result := model.DeleteVocabularyEntry(ctx, vocabulary, id)
+		response = vocabularyChangeResponse(result, id, constants.PermanentDeleteSuccessMessage)
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func MergeVocabularyEntry(ginContext *gin.Context) {
+	/*
+		To merge an entry of a vocabulary into another, e.g. "cocoa" into "Cocoa"
+		Products using the entry will use the other entry instead and the entry is deleted
+		Sample Url: "http://host/catalog/vocabularies/ingredients/3/merge/"
+		Request Method: POST
+		Request Data: vocabulary and id of the entry to be provided in the url, e.g. ingredients and 3 in sample url
+		{
+			"into": "id of the entry to be kept"
+		}
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": id of the entry kept/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.MergeVocabularyEntry"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	vocabulary, exists := model.Vocabularies[ginContext.Params.ByName("vocabulary")]
+	id, idErr := strconv.Atoi(ginContext.Params.ByName("id"))
+	intoId, intoIdErr := strconv.Atoi(ginContext.DefaultPostForm("into", ""))
+	if !exists {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.UnknownVocabularyErrorMessage,
+		}
+	} else if idErr != nil || intoIdErr != nil || id <= 0 || intoId <= 0 || id == intoId {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else {
+		// Calling function to execute queries in an atomic transaction
+		result := model.MergeVocabularyEntry(ctx, vocabulary, id, intoId)
+		response = vocabularyChangeResponse(result, intoId, constants.MergeSuccessMessage)
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func UpdateVocabularyTranslation(ginContext *gin.Context) {
+	/*
+		To store the translated name of an entry of a vocabulary in a locale, replacing the existing one
+		Sample Url: "http://host/catalog/vocabularies/ingredients/3/translations/ms-MY/"
+		Request Method: PUT
+		Request Data: vocabulary, id of the entry and locale to be provided in the url,
+		e.g. ingredients, 3 and ms-MY in sample url
+		{
+			"name": "Translated name of the entry"
+		}
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 3/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.UpdateVocabularyTranslation"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	vocabulary, exists := model.Vocabularies[ginContext.Params.ByName("vocabulary")]
+	id, idErr := strconv.Atoi(ginContext.Params.ByName("id"))
+	locale, validLocale := translationLocale(ginContext.Params.ByName("locale"))
+	name := strings.TrimSpace(ginContext.DefaultPostForm("name", ""))
+	if !exists {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.UnknownVocabularyErrorMessage,
+		}
+	} else if !validLocale {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidLocaleErrorMessage,
+		}
+	} else if idErr != nil || id <= 0 || name == "" {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else {
+		// Calling function to execute queries in an atomic transaction
+		result := model.TranslateVocabularyEntry(ctx, vocabulary, id, locale, name)
+		response = vocabularyChangeResponse(result, id, constants.UpdateSuccessMessage)
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func DeleteVocabularyTranslation(ginContext *gin.Context) {
+	/*
+		To delete the translated name of an entry of a vocabulary in a locale
+		Sample Url: "http://host/catalog/vocabularies/ingredients/3/translations/ms-MY/"
+		Request Method: DELETE
+		Request Data: vocabulary, id of the entry and locale to be provided in the url,
+		e.g. ingredients, 3 and ms-MY in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 3/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.DeleteVocabularyTranslation"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	vocabulary, exists := model.Vocabularies[ginContext.Params.ByName("vocabulary")]
+	id, idErr := strconv.Atoi(ginContext.Params.ByName("id"))
+	locale, validLocale := translationLocale(ginContext.Params.ByName("locale"))
+	if !exists {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.UnknownVocabularyErrorMessage,
+		}
+	} else if !validLocale {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidLocaleErrorMessage,
+		}
+	} else if idErr != nil || id <= 0 {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else {
+		// found: false, if the entry has no translated name in the locale
+		found, success := model.DeleteFromVocabularyTranslationById(ctx, vocabulary, id, locale)
+		if !success {
+			response = &structs.CreateUpdateDeleteResponse{
+				Message: constants.GenericErrorMessage,
+			}
+		} else if !found {
+			response = &structs.CreateUpdateDeleteResponse{
+				Message: constants.NoRecordsFoundMessage,
+			}
+		} else {
+			response = &structs.CreateUpdateDeleteResponse{
+				Success: true,
+				Message: constants.PermanentDeleteSuccessMessage,
+				Id:      id,
+			}
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func ReadCategories(ginContext *gin.Context) {
+	/*
+		To fetch the tree of categories with the number of products in each
+		Sample Url: "http://host/catalog/categories/"
+		Request Method: GET
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true / false,
+			"data": [
+				{"id": 1, "name": "Non-Dairy", "path": "Non-Dairy", "product_count": 0, "children": [
+					{"id": 4, "name": "Frozen Dessert", "path": "Non-Dairy > Frozen Dessert", "product_count": 6,
+						"children": []}
+				]}
+			]
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CategoryListResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.ReadCategories"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	categories, success := model.SelectCategoryTree(ctx)
+	if !success {
+		response = &structs.CategoryListResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else {
+		response = &structs.CategoryListResponse{
+			Success: true,
+			Message: constants.ReadSuccessMessage,
+			Data:    categories,
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func CreateCategory(ginContext *gin.Context) {
+	/*
+		To add a new category, at the root or as a subcategory of an existing one
+		Sample Url: "http://host/catalog/categories/"
+		Request Method: POST
+		Request Data:
+		{
+			"name": "Name of the category",
+			"parent_id": "1" // optional, id of the parent category, a root category is added without it
+		}
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 12/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.CreateCategory"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	name := normalizer.Name(ginContext.DefaultPostForm("name", ""))
+	parentId, parentIdErr := strconv.Atoi(ginContext.DefaultPostForm("parent_id", "0"))
+	if parentIdErr != nil || parentId < 0 || !model.ValidCategoryName(name) {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidCategoryNameErrorMessage,
+		}
+	} else {
+		// Calling function to execute queries in an atomic transaction
+		id, result := model.CreateCategory(ctx, parentId, name)
+		response = categoryChangeResponse(result, id, constants.CreateSuccessMessage)
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func UpdateCategory(ginContext *gin.Context) {
+	/*
+		To rename a category and/or move it with its subcategories under another parent
+		Products in the category keep it, their paths change and a new revision is stored for each
+		Sample Url: "http://host/catalog/categories/4/"
+		Request Method: PUT
+		Request Data: id of the category to be provided in the url, e.g. 4 in sample url
+		{
+			"name": "Name of the category",
+			"parent_id": "1" // optional, id of the (new) parent category, the category is moved to the root without it
+		}
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 4/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.UpdateCategory"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	id, idErr := strconv.Atoi(ginContext.Params.ByName("id"))
+	name := normalizer.Name(ginContext.DefaultPostForm("name", ""))
+	parentId, parentIdErr := strconv.Atoi(ginContext.DefaultPostForm("parent_id", "0"))
+	if idErr != nil || id <= 0 {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else if parentIdErr != nil || parentId < 0 || !model.ValidCategoryName(name) {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidCategoryNameErrorMessage,
+		}
+	} else {
+		// Calling function to execute queries in an atomic transaction
+		result := model.UpdateCategory(ctx, id, parentId, name)
+		response = categoryChangeResponse(result, id, constants.UpdateSuccessMessage)
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func DeleteCategory(ginContext *gin.Context) {
+	/*
+		To delete a category, categories with subcategories or products can't be deleted
+		Sample Url: "http://host/catalog/categories/4/"
+		Request Method: DELETE
+		Request Data: id of the category to be provided in the url, e.g. 4 in sample url
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"id": 4/0,
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.CreateUpdateDeleteResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.DeleteCategory"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	id, idErr := strconv.Atoi(ginContext.Params.ByName("id"))
+	if idErr != nil || id <= 0 {
+		response = &structs.CreateUpdateDeleteResponse{
+			Message: constants.RequestInvalidErrorMessage,
+		}
+	} else {
+		// Calling function to execute queries in an atomic transaction
+		result := model.DeleteCategory(ctx, id)
+		response = categoryChangeResponse(result, id, constants.PermanentDeleteSuccessMessage)
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func ReadProducts(ginContext *gin.Context) {
+	/*
+		To fetch live ice cream products in a category (including its subcategories) and/or carrying a tag
+		Sample Url: "http://host/catalog/products/?category=1" or "http://host/catalog/products/?category=1&tag=vegan"
+		Request Method: GET
+		URL Param: category=1, id of the category; tag=vegan, name of the tag; at least one of them is required
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true / false,
+			"data": [{"productId": "123", "name": "Name of Ice Cream"}]
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.ProductListResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.ReadProducts"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	tag := normalizer.Name(ginContext.Query(constants.TagQueryParamName))
+	categoryId, categoryIdErr := strconv.Atoi(ginContext.DefaultQuery(constants.CategoryQueryParamName, "0"))
+	if categoryIdErr != nil || categoryId < 0 || (categoryId == 0 && tag == "") {
+		// Listing all products isn't a search, so at least one filter is required
+		response = &structs.ProductListResponse{
+			Message: constants.InvalidProductFilterErrorMessage,
+		}
+	} else if productList, found, success := model.FindProducts(ctx, categoryId, tag); !success {
+		response = &structs.ProductListResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if !found {
+		response = &structs.ProductListResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	} else {
+		response = &structs.ProductListResponse{
+			Success: true,
+			Message: constants.ReadSuccessMessage,
+			Data:    make([]*structs.ProductSummaryStruct, 0, len(productList)),
+		}
+		for _, product := range productList {
+			response.Data = append(response.Data, &structs.ProductSummaryStruct{
+				ProductId: product.ProductId,
+				Name:      product.Name,
+			})
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func ReadFacets(ginContext *gin.Context) {
+	/*
+		To fetch the number of live ice cream products matching filters, in total and per ingredient, sourcing value
+		and dietary certification used by any of them, e.g. to build a filter sidebar
+		Products must match every filter given, no filter counts all live products
+		Sample Url: "http://host/catalog/facets/?category=1&ingredient=Cocoa&ingredient=Cream&free_from=nuts"
+		Request Method: GET
+		URL Param: category=1, id of the category; tag=vegan, name of the tag; ingredient=Cocoa,
+		sourcing_value=Fairtrade, dietary_certification=Kosher, names of entries used by products;
+		free_from=nuts, code of an allergen products are declared free from; all but category and tag can be repeated
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true / false,
+			"data": {
+				"product_count": 12,
+				"dietary_certifications": [{"id": 2, "name": "Kosher", "product_count": 4}],
+				"ingredients": [{"id": 3, "name": "Cocoa", "product_count": 12}],
+				"sourcing_values": [{"id": 5, "name": "Fairtrade", "product_count": 9}]
+			}
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.FacetResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.ReadFacets"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	// All values of params given several times, e.g. ingredient=Cocoa&ingredient=Cream
+	query := ginContext.Request.URL.Query()
+	filter := &model.FacetFilter{
+		Tag:      ginContext.Query(constants.TagQueryParamName),
+		FreeFrom: make([]string, 0),
+		Properties: map[string][]string{
+			"dietary_certifications": query[constants.DietaryCertificationQueryParamName],
+			"ingredients":            query[constants.IngredientQueryParamName],
+			"sourcing_values":        query[constants.SourcingValueQueryParamName],
+		},
+	}
+	allergens := make([]*structs.AllergenStruct, 0)
+	for code := range utils.ListToMap(query[constants.FreeFromQueryParamName]) {
+		filter.FreeFrom = append(filter.FreeFrom, code)
+		allergens = append(allergens, &structs.AllergenStruct{Code: code, Level: constants.AllergenLevelContains})
+	}
+	var categoryIdErr error
+	filter.CategoryId, categoryIdErr = strconv.Atoi(ginContext.DefaultQuery(constants.CategoryQueryParamName, "0"))
+	// An unknown allergen code is refused, as counting every product as free of it would be misleading
+	valid, success := model.ValidateAllergens(ctx, allergens)
+	if !success {
+		response = &structs.FacetResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if !valid || categoryIdErr != nil || filter.CategoryId < 0 {
+		response = &structs.FacetResponse{
+			Message: constants.InvalidFacetFilterErrorMessage,
+		}
+	} else if productCount, facets, found, success := model.FindFacets(ctx, filter); !success {
+		response = &structs.FacetResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if !found {
+		response = &structs.FacetResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	} else {
+		response = &structs.FacetResponse{
+			Success: true,
+			Message: constants.ReadSuccessMessage,
+			Data: &structs.FacetStruct{
+				ProductCount:          productCount,
+				DietaryCertifications: facetEntries(facets["dietary_certifications"]),
+				Ingredients:           facetEntries(facets["ingredients"]),
+				SourcingValues:        facetEntries(facets["sourcing_values"]),
+			},
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func facetEntries(entries []*model.PropertyUsage) []*structs.FacetEntryStruct {
+	/*
+		To convert entries of a vocabulary counted by facets to their response structure
+	*/
+	result := make([]*structs.FacetEntryStruct, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, &structs.FacetEntryStruct{
+			Id:           entry.Id,
+			Name:         entry.Name,
+			ProductCount: entry.ProductCount,
+		})
+	}
+	return result
+}
+
+func UploadImage(ginContext *gin.Context) {
+	/*
+		To upload an image, to be referred by products in image_closed/image_open by the url in response
+		Sample Url: "http://host/catalog/images/"
+		Request Method: POST
+		Request Data: multipart form with the image file (jpeg, png or gif) in key "image"
+		Response Data:
+		{
+			"message": "Success/Error message",
+			"success": true/false
+			"data": {
+				"url": "http://cdn/images/9f86d0...jpg",
+				"thumbnail_url": "http://cdn/images/9f86d0..._thumb.jpg",
+				"content_type": "image/jpeg",
+				"width": 1200,
+				"height": 800,
+				"size": 245760
+			}
+		}
+	*/
+	var (
+		isAuthorized  bool
+		response      *structs.ImageUploadResponse
+		responseBytes []byte
+		responseErr   error
+		logIdentifier = "bennjerry.UploadImage"
+		ctx           = ginContext.Request.Context()
+		requestLogger = logger.FromContext(ctx)
+	)
+
+	// defer block to recover and log if any error occurs
+	defer func() {
+		if r := recover(); r != nil {
+			requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+				constants.GenericErrorMessage, string(debug.Stack()))
+			serializer := utils.GetSerializer(constants.JsonSerializerType)
+			serializer.ReturnError(ginContext, http.StatusInternalServerError, constants.GenericErrorMessage)
+			return
+		}
+	}()
+
+	// If request is authorized, is_authorized = 1 would've been dropped in ginContext object by the auth middleware
+	if isAuth, isAuthExists := ginContext.Get(constants.IsAuthorizedKeyName); isAuthExists {
+		isAuthorized = isAuth.(int) == 1
+	}
+	if !isAuthorized {
+		// Returning error response if request is not authorized
+		serializer := utils.GetSerializer(constants.JsonSerializerType)
+		serializer.ReturnUnAuthorized(ginContext, constants.UnAuthorizedErrorMessage)
+		return
+	}
+
+	data, found, tooLarge := readImageFile(ginContext)
+	if !found {
+		response = &structs.ImageUploadResponse{
+			Message: constants.ImageMissingErrorMessage,
+		}
+	} else if tooLarge {
+		response = &structs.ImageUploadResponse{
+			Message: constants.ImageTooLargeErrorMessage,
+		}
+	} else if img, processErr := imagestore.Process(data); processErr != nil {
+		// Type, dimensions and decoding of the image are validated, the error tells which one failed
+		response = &structs.ImageUploadResponse{
+			Message: processErr.Error(),
+		}
+	} else if image, success := model.SaveImage(ctx, img); success {
+		response = &structs.ImageUploadResponse{
+			Success: true,
+			Message: constants.ImageUploadSuccessMessage,
+			Data:    image,
+		}
+	} else {
+		response = &structs.ImageUploadResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	}
+	// Converting response structure to []byte
+	responseBytes, responseErr = json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		requestLogger.Error(constants.BenNJerryLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnOk(ginContext, responseBytes)
+}
+
+func readImageFile(ginContext *gin.Context) ([]byte, bool, bool) {
+	/*
+		To read the file of the image form key, at most one byte more than the largest image accepted
+		Return: file content, found: false, if there's no readable file, tooLarge: true, if it's larger than accepted
+	*/
+	fileHeader, err := ginContext.FormFile(constants.ImageFormKeyName)
+	if err != nil {
+		return nil, false, false
+	}
+	if fileHeader.Size > constants.ImageMaxSize {
+		return nil, true, true
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, false, false
+	}
+	defer file.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(file, constants.ImageMaxSize+1))
+	if err != nil {
+		return nil, false, false
+	}
+	return data, true, len(data) > constants.ImageMaxSize
+}
+
+func translationLocale(tag string) (string, bool) {
+	/*
+		To validate the locale of a translation given in the url and return it in canonical case
+		Texts in the default locale are stored in the product and vocabulary tables, so it isn't a valid locale
+	*/
+	locale, valid := localization.Canonical(tag)
+	if !valid || locale == constants.DefaultLocale {
+		return "", false
+	}
+	return locale, true
+}
+
+func vocabularyChangeResponse(result model.VocabularyResult, id int,
+	successMessage string) *structs.CreateUpdateDeleteResponse {
+	/*
+		To build the response of a change to an entry of a vocabulary from its outcome
+	*/
+	switch result {
+	case model.VocabularyChanged:
+		return &structs.CreateUpdateDeleteResponse{
+			Success: true,
+			Message: successMessage,
+			Id:      id,
+		}
+	case model.VocabularyNotFound:
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	case model.VocabularyNameExists:
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.VocabularyNameExistsMessage,
+		}
+	case model.VocabularyInUse:
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.VocabularyInUseMessage,
+		}
+	}
+	return &structs.CreateUpdateDeleteResponse{
+		Message: constants.GenericErrorMessage,
+	}
+}
+
+func saveVariant(ginContext *gin.Context, productId string,
+	variant *structs.VariantStruct, create bool) *structs.CreateUpdateDeleteResponse {
+	/*
+		To validate a variant of an ice cream product and create it, or replace the existing variant of its sku
+	*/
+	ctx := ginContext.Request.Context()
+	if !model.ValidateVariant(variant) {
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidVariantErrorMessage,
+		}
+	}
+	// fetching id (primary key) of ice cream product using product_id
+	// success: false, if some error occurs while running the query
+	// success: true, id: 0, if requested product_id is not found
+	id, success := model.SelectIdFromProductByProductId(ctx, productId)
+	if !success {
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if id == 0 {
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	}
+	// An image of the storage must have been uploaded before being referred to
+	if validImages, success := model.ValidateImageURLs(ctx, variant.PackagingImage); !success {
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if !validImages {
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidImageErrorMessage,
+		}
+	}
+	// Calling function to execute queries in an atomic transaction
+	if create {
+		return variantChangeResponse(model.CreateVariant(ctx, id, variant), id, constants.CreateSuccessMessage)
+	}
+	return variantChangeResponse(model.UpdateVariant(ctx, id, variant), id, constants.UpdateSuccessMessage)
+}
+
+func variantChangeResponse(result model.VariantResult, id int,
+	successMessage string) *structs.CreateUpdateDeleteResponse {
+	/*
+		To build the response of a change to a variant of an ice cream product from its outcome
+	*/
+	switch result {
+	case model.VariantChanged:
+		return &structs.CreateUpdateDeleteResponse{
+			Success: true,
+			Message: successMessage,
+			Id:      id,
+		}
+	case model.VariantNotFound:
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	case model.VariantSkuExists:
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.VariantSkuExistsMessage,
+		}
+	}
+	return &structs.CreateUpdateDeleteResponse{
+		Message: constants.GenericErrorMessage,
+	}
+}
+
+func categoryChangeResponse(result model.CategoryResult, id int,
+	successMessage string) *structs.CreateUpdateDeleteResponse {
+	/*
+		To build the response of a change to a category from its outcome
+	*/
+	switch result {
+	case model.CategoryChanged:
+		return &structs.CreateUpdateDeleteResponse{
+			Success: true,
+			Message: successMessage,
+			Id:      id,
+		}
+	case model.CategoryNotFound:
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	case model.CategoryNameExists:
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.CategoryNameExistsMessage,
+		}
+	case model.CategoryInvalid:
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.InvalidCategoryNameErrorMessage,
+		}
+	case model.CategoryInUse:
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.CategoryInUseMessage,
+		}
+	}
+	return &structs.CreateUpdateDeleteResponse{
+		Message: constants.GenericErrorMessage,
+	}
+}
+
+func changeDraft(ctx context.Context, productId string, change func(ctx context.Context, id int) model.DraftResult,
+	successMessage string) *structs.CreateUpdateDeleteResponse {
+	/*
+		To change the draft of an ice cream product and build the response from the outcome of the change
+	*/
+	// fetching id (primary key) of ice cream product using product_id
+	// success: false, if some error occurs while running the query
+	// success: true, id: 0, if requested product_id is not found
+	id, success := model.SelectIdFromProductByProductId(ctx, productId)
+	if !success {
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.GenericErrorMessage,
+		}
+	} else if id == 0 {
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	}
+	// Calling function to execute queries in an atomic transaction
+	switch change(ctx, id) {
+	case model.DraftChanged:
+		return &structs.CreateUpdateDeleteResponse{
+			Success: true,
+			Message: successMessage,
+			Id:      id,
+		}
+	case model.DraftNotFound:
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.NoRecordsFoundMessage,
+		}
+	case model.DraftInvalidStatus:
+		return &structs.CreateUpdateDeleteResponse{
+			Message: constants.DraftStatusErrorMessage,
+		}
+	}
+	return &structs.CreateUpdateDeleteResponse{
+		Message: constants.GenericErrorMessage,
+	}
+}
@@ -1,151 +1,454 @@
 package model
 
 import (
+	"context"
 	"database/sql"
 	"strconv"
+	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
 
 	"constants"
 	"logger"
+	"metrics"
 	"mysqlc"
 )
 
-func SoftDeleteFromProductByProductId(productId string) (int, bool) {
+func SoftDeleteFromProductByProductId(ctx context.Context, productId string) (int, bool) {
 	/*
 		To take product_id and mark record in product table as inactive
 	*/
-	id, success := SelectIdFromProductByProductId(productId)
+	id, success := SelectIdFromProductByProductId(ctx, productId)
 	if success {
-		return UpdateProductIsInActiveById(id)
+		return UpdateProductIsInActiveById(ctx, id)
 	}
 	return 0, false
 }
 
-func DeleteFromProductById(txn *sql.Tx, id int) bool {
+func DeleteFromProductById(ctx context.Context, txn *sql.Tx, id int) bool {
 	/*
 		To take id as input and delete record from product table
 	*/
 	funcName := "DeleteFromProductById"
 	query := "DELETE FROM product WHERE id = " + strconv.Itoa(id)
-	_, err := txn.Exec(query)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 		return false
 	}
 	return true
 }
 
-func DeleteFromProductSourcingValueByProductIdPK(txn *sql.Tx, ProductIdPK int) bool {
+func DeleteFromProductSourcingValueById(ctx context.Context, txn *sql.Tx, productIdPK int, sourcingValueId int) bool {
 	/*
-		To take product_id(primary key of product table) and delete record from product_sourcingvalue table
+		To take product_id(primary key of product table) and sourcingvalue_id
+		and delete record from product_sourcingvalue table
 	*/
-	funcName := "DeleteFromProductSourcingValueByProductIdPK"
-	query := "DELETE FROM product_sourcingvalue WHERE product_id = " + strconv.Itoa(ProductIdPK)
-	_, err := txn.Exec(query)
+	funcName := "DeleteFromProductSourcingValueById"
+	query := "DELETE FROM product_sourcingvalue" +
+		" WHERE product_id = " + strconv.Itoa(productIdPK) + " AND sourcingvalue_id = " + strconv.Itoa(sourcingValueId)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 		return false
 	}
 	return true
 }
 
-func DeleteFromProductSourcingValueById(txn *sql.Tx, productIdPK int, sourcingValueId int) bool {
+func DeleteFromProductIngredientByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int) bool {
 	/*
-		To take product_id(primary key of product table) and sourcingvalue_id
-		and delete record from product_sourcingvalue table
+		To take product_id (primary key of product table) and delete all its records from product_ingredient table
 	*/
-	funcName := "DeleteFromProductSourcingValueById"
-	query := "DELETE FROM product_sourcingvalue" +
-		" WHERE product_id = " + strconv.Itoa(productIdPK) + " AND sourcingvalue_id = " + strconv.Itoa(sourcingValueId)
-	_, err := txn.Exec(query)
+	funcName := "DeleteFromProductIngredientByProductIdPK"
+	query := "DELETE FROM product_ingredient WHERE product_id = " + strconv.Itoa(productIdPK)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 		return false
 	}
 	return true
 }
 
-func DeleteFromProductIngredientByProductIdPK(txn *sql.Tx, productIdPK int) bool {
+func DeleteFromProductDietaryCertificationById(ctx context.Context, txn *sql.Tx, productIdPK int,
+	dietaryCertificationId int) bool {
 	/*
-		To take product_id(primary key of product table) and delete record from product_ingredient table
+		To take product_id(primary key of product table) and dietarycertification_id
+		and delete record from product_dietarycertification table
 	*/
-	funcName := "DeleteFromProductIngredientByProductIdPK"
-	query := "DELETE FROM product_ingredient WHERE product_id = " + strconv.Itoa(productIdPK)
-	_, err := txn.Exec(query)
+	funcName := "DeleteFromProductDietaryCertificationById"
+	query := "DELETE FROM product_dietarycertification WHERE product_id = " + strconv.Itoa(productIdPK) +
+		" AND dietarycertification_id = " + strconv.Itoa(dietaryCertificationId)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 		return false
 	}
 	return true
 }
 
-func DeleteFromProductIngredientById(txn *sql.Tx, productIdPK int, ingredientId int) bool {
+func DeleteFromProductTagById(ctx context.Context, txn *sql.Tx, productIdPK int, tagId int) bool {
 	/*
-		To take product_id(primary key of product table) and ingredient_id as input
-		and delete record from product_ingredient table
+		To take product_id(primary key of product table) and tag_id and delete record from product_tag table
 	*/
-	funcName := "DeleteFromProductIngredientByProductIdPK"
-	query := "DELETE FROM product_ingredient" + " WHERE product_id = " + strconv.Itoa(productIdPK) +
-		" AND ingredient_id = " + strconv.Itoa(ingredientId)
-	_, err := txn.Exec(query)
+	funcName := "DeleteFromProductTagById"
+	query := "DELETE FROM product_tag WHERE product_id = " + strconv.Itoa(productIdPK) +
+		" AND tag_id = " + strconv.Itoa(tagId)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 		return false
 	}
 	return true
 }
 
-func DeleteUnUsedSourcingValue() bool {
+func DeleteFromProductCategoryByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int) bool {
 	/*
-		Deleting unused data from sourcingvalue table
+		To take product_id(primary key of product table) and delete its records from product_category table
+	*/
+	funcName := "DeleteFromProductCategoryByProductIdPK"
+	query := "DELETE FROM product_category WHERE product_id = " + strconv.Itoa(productIdPK)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func DeleteUnUsedSourcingValue(ctx context.Context, txn *sql.Tx) (int64, bool) {
+	/*
+		Deleting data from sourcingvalue table that isn't used by any product
+		Return: Number of rows deleted
 	*/
 	funcName := "DeleteUnUsedSourcingValue"
 	query := "DELETE sourcingvalue FROM sourcingvalue LEFT JOIN product_sourcingvalue" +
 		" ON sourcingvalue.id = product_sourcingvalue.sourcingvalue_id" +
 		" WHERE product_sourcingvalue.sourcingvalue_id is NULL"
-	_, err := mysqlc.MySqlDB.Exec(query)
+	return deleteUnUsed(ctx, txn, funcName, query)
+}
+
+func DeleteUnUsedIngredient(ctx context.Context, txn *sql.Tx) (int64, bool) {
+	/*
+		Deleting data from ingredient table that isn't used by any product
+		Return: Number of rows deleted
+	*/
+	funcName := "DeleteUnUsedIngredient"
+	query := "DELETE ingredient FROM ingredient LEFT JOIN product_ingredient" +
+		" ON ingredient.id = product_ingredient.ingredient_id WHERE product_ingredient.ingredient_id is NULL"
+	return deleteUnUsed(ctx, txn, funcName, query)
+}
+
+func DeleteUnUsedDietaryCertification(ctx context.Context, txn *sql.Tx) (int64, bool) {
+	/*
+		Deleting data from dietarycertification table that isn't used by any product
+		Return: Number of rows deleted
+	*/
+	funcName := "DeleteUnUsedDietaryCertification"
+	query := "DELETE dietarycertification FROM dietarycertification LEFT JOIN product_dietarycertification" +
+		" ON dietarycertification.id = product_dietarycertification.dietarycertification_id" +
+		" WHERE product_dietarycertification.dietarycertification_id is NULL"
+	return deleteUnUsed(ctx, txn, funcName, query)
+}
+
+func DeleteUnUsedTag(ctx context.Context, txn *sql.Tx) (int64, bool) {
+	/*
+		Deleting data from tag table that isn't used by any product
+		Return: Number of rows deleted
+	*/
+	funcName := "DeleteUnUsedTag"
+	query := "DELETE tag FROM tag LEFT JOIN product_tag ON tag.id = product_tag.tag_id" +
+		" WHERE product_tag.tag_id is NULL"
+	return deleteUnUsed(ctx, txn, funcName, query)
+}
+
+func deleteUnUsed(ctx context.Context, txn *sql.Tx, funcName string, query string) (int64, bool) {
+	/*
+		To run a delete query of unused data inside the transaction and count the rows deleted
+	*/
+	queryCtx, cancel := queryContext(ctx)
+	result, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return 0, false
+	}
+	rowsDeleted, err := result.RowsAffected()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		return 0, true
+	}
+	return rowsDeleted, true
+}
+
+func DeleteFromProductAllergenByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int) bool {
+	/*
+		To take product_id (primary key of product table) and delete all its records from product_allergen table
+	*/
+	funcName := "DeleteFromProductAllergenByProductIdPK"
+	query := "DELETE FROM product_allergen WHERE product_id = " + strconv.Itoa(productIdPK)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 		return false
 	}
 	return true
 }
 
-func DeleteUnUsedIngredient() bool {
+func DeleteFromVocabularyById(ctx context.Context, txn *sql.Tx, vocabulary *Vocabulary, id int) bool {
 	/*
-		Deleting unused data from ingredient table
+		To take a vocabulary and id and delete the entry, foreign keys refuse it if a product still uses the entry
 	*/
-	funcName := "DeleteUnUsedSourcingValue"
-	query := "DELETE ingredient FROM ingredient LEFT JOIN product_ingredient" +
-		" ON ingredient.id = product_ingredient.ingredient_id WHERE product_ingredient.ingredient_id is NULL"
-	_, err := mysqlc.MySqlDB.Exec(query)
+	funcName := "DeleteFromVocabularyById"
+	query := "DELETE FROM " + vocabulary.Table + " WHERE id = " + strconv.Itoa(id)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 		return false
 	}
 	return true
 }
 
-func DeleteUnUsedDietaryCertification() bool {
+func DeleteFromProductVocabularyByVocabularyId(ctx context.Context, txn *sql.Tx, vocabulary *Vocabulary,
+	id int) bool {
 	/*
-		Deleting unused data from dietarycertification table
+		To take a vocabulary and id of an entry and delete all its records from the relation table
 	*/
-	funcName := "DeleteUnUsedDietaryCertification"
-	query := "DELETE dietarycertification FROM dietarycertification LEFT JOIN product" +
-		" ON dietarycertification.id = product.dietary_certification_id WHERE product.id is NULL"
-	_, err := mysqlc.MySqlDB.Exec(query)
+	funcName := "DeleteFromProductVocabularyByVocabularyId"
+	query := "DELETE FROM " + vocabulary.RelationTable + " WHERE " + vocabulary.Column + " = " + strconv.Itoa(id)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 		return false
 	}
 	return true
 }
+
+func DeleteFromProductTranslationByProductIdPK(ctx context.Context, productIdPK int, locale string) (bool, bool) {
+	/*
+		To take product_id (primary key of product table) and a locale and delete the translation to that locale
+		Return: found: false, if the product has no translation to the locale
+	*/
+	funcName := "DeleteFromProductTranslationByProductIdPK"
+	query := "DELETE FROM product_translation WHERE product_id = " + strconv.Itoa(productIdPK) +
+		" AND locale = '" + strings.Replace(locale, "'", "''", -1) + "'"
+	return deleteFound(ctx, funcName, query)
+}
+
+func DeleteFromVocabularyTranslationById(ctx context.Context, vocabulary *Vocabulary, id int,
+	locale string) (bool, bool) {
+	/*
+		To take an entry of a vocabulary and a locale and delete the translated name of the entry in that locale
+		Return: found: false, if the entry has no translated name in the locale
+	*/
+	funcName := "DeleteFromVocabularyTranslationById"
+	query := "DELETE FROM " + vocabulary.TranslationTable + " WHERE " + vocabulary.Column + " = " + strconv.Itoa(id) +
+		" AND locale = '" + strings.Replace(locale, "'", "''", -1) + "'"
+	return deleteFound(ctx, funcName, query)
+}
+
+func deleteFound(ctx context.Context, funcName string, query string) (bool, bool) {
+	/*
+		To run a delete query and tell whether any row was found (and deleted)
+	*/
+	queryCtx, cancel := queryContext(ctx)
+	result, err := mysqlc.MySqlDB.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false, false
+	}
+	rowsDeleted, err := result.RowsAffected()
+	if err != nil {
+		return false, true
+	}
+	return rowsDeleted > 0, true
+}
+
+func DeleteUnReferencedImageByKey(ctx context.Context, key string, url string) (bool, bool) {
+	/*
+		To take key and url of an image and delete it from image table, only if no product, variant or draft refers
+		to the url, as a draft referring to it can still be published
+		Checked in the same query, so an image referred by a product saved in the meantime is never deleted
+		Return: deleted: false, if the image doesn't exist or is referred; success: false, if an error occurs
+	*/
+	funcName := "DeleteUnReferencedImageByKey"
+	escapedURL := strings.Replace(url, "'", "''", -1)
+	query := "DELETE FROM image WHERE image_key = '" + strings.Replace(key, "'", "''", -1) + "'" +
+		" AND NOT EXISTS (SELECT id FROM product WHERE image_closed = '" + escapedURL + "'" +
+		" OR image_opened = '" + escapedURL + "')" +
+		" AND NOT EXISTS (SELECT id FROM product_variant WHERE packaging_image = '" + escapedURL + "')" +
+		" AND NOT EXISTS (SELECT product_id FROM product_draft WHERE " + draftImageCondition(escapedURL) + ")"
+	return deleteFound(ctx, funcName, query)
+}
+
+func DeleteFromProductPriceByMarket(ctx context.Context, txn *sql.Tx, productIdPK int, market string) bool {
+	/*
+		To take product_id (primary key of product table) and a market and delete all prices of the product there
+	*/
+	funcName := "DeleteFromProductPriceByMarket"
+	query := "DELETE FROM product_price WHERE product_id = " + strconv.Itoa(productIdPK) +
+		" AND market = '" + strings.Replace(market, "'", "''", -1) + "'"
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func DeleteFromProductMarket(ctx context.Context, productIdPK int, market string) (bool, bool) {
+	/*
+		To take product_id (primary key of product table) and a market and delete it from product_market table
+		Its prices are deleted along by the cascading foreign key
+		Return: found: false, if the product isn't listed in the market; success: false, if an error occurs
+	*/
+	funcName := "DeleteFromProductMarket"
+	query := "DELETE FROM product_market WHERE product_id = " + strconv.Itoa(productIdPK) +
+		" AND market = '" + strings.Replace(market, "'", "''", -1) + "'"
+	return deleteFound(ctx, funcName, query)
+}
+
+func DeleteFromProductVariantNutritionByVariantId(ctx context.Context, txn *sql.Tx, variantId int) bool {
+	/*
+		To take id of a variant and delete all its nutrition values
+	*/
+	funcName := "DeleteFromProductVariantNutritionByVariantId"
+	query := "DELETE FROM product_variant_nutrition WHERE variant_id = " + strconv.Itoa(variantId)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func DeleteFromProductVariantById(ctx context.Context, txn *sql.Tx, id int) bool {
+	/*
+		To take id of a variant and delete it from product_variant table
+		Its nutrition values are deleted along by the cascading foreign key
+	*/
+	funcName := "DeleteFromProductVariantById"
+	query := "DELETE FROM product_variant WHERE id = " + strconv.Itoa(id)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func DeleteFromProductNutritionByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int) bool {
+	/*
+		To take product_id (primary key of product table) and delete its nutrition facts
+		Amounts of its nutrients are deleted along by the cascading foreign key
+	*/
+	funcName := "DeleteFromProductNutritionByProductIdPK"
+	query := "DELETE FROM product_nutrition WHERE product_id = " + strconv.Itoa(productIdPK)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func DeleteFromCategoryById(ctx context.Context, txn *sql.Tx, id int) bool {
+	/*
+		To take id of a category and delete it, foreign keys refuse it if it has subcategories or products
+	*/
+	funcName := "DeleteFromCategoryById"
+	query := "DELETE FROM category WHERE id = " + strconv.Itoa(id)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func DeleteFromProductDraftByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int) bool {
+	/*
+		To take product_id (primary key of product table) and delete its draft inside a transaction
+	*/
+	funcName := "DeleteFromProductDraftByProductIdPK"
+	query := "DELETE FROM product_draft WHERE product_id = " + strconv.Itoa(productIdPK)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func DeleteFromProductDraft(ctx context.Context, productIdPK int) (bool, bool) {
+	/*
+		To take product_id (primary key of product table) and discard its draft
+		Return: found: false, if the product has no draft; success: false, if an error occurs
+	*/
+	funcName := "DeleteFromProductDraft"
+	query := "DELETE FROM product_draft WHERE product_id = " + strconv.Itoa(productIdPK)
+	return deleteFound(ctx, funcName, query)
+}
@@ -1,14 +1,46 @@
 package model
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	"bennjerry/structs"
-	"mysqlc"
+	"constants"
+	"imagestore"
+	"localization"
+	"logger"
+	"metrics"
+	"normalizer"
+	"nutrition"
 	"utils"
 )
 
-var logIdentifier = "bennjerry.model."
+var (
+	logIdentifier = "bennjerry.model."
+	// names of all fields of an ice cream product, as expected in the 'fields' of an update request
+	allFields = []string{"name", "description", "story", "image_closed", "image_open", "allergy_info",
+		"dietary_certifications", "sourcing_values", "ingredients", "allergens", "nutrition", "categories", "tags",
+		"publish_at", "unpublish_at"}
+	// vocabularies that can be managed through the apis, by the name of their field in ice cream data
+	Vocabularies = map[string]*Vocabulary{
+		"dietary_certifications": {Table: "dietarycertification", RelationTable: "product_dietarycertification",
+			Column: "dietarycertification_id", TranslationTable: "dietarycertification_translation"},
+		"ingredients": {Table: "ingredient", RelationTable: "product_ingredient", Column: "ingredient_id",
+			TranslationTable: "ingredient_translation"},
+		"sourcing_values": {Table: "sourcingvalue", RelationTable: "product_sourcingvalue", Column: "sourcingvalue_id",
+			TranslationTable: "sourcingvalue_translation"},
+		"tags": {Table: "tag", RelationTable: "product_tag", Column: "tag_id", TranslationTable: "tag_translation"},
+	}
+	// vocabularies counted by the facets api, by the name of their field in ice cream data
+	FacetVocabularies = []string{"ingredients", "sourcing_values", "dietary_certifications"}
+)
 
-func InsertRecord(iceCreamData []*structs.IceCreamDataStruct) ([]int, bool) {
+func InsertRecord(ctx context.Context, iceCreamData []*structs.IceCreamDataStruct) ([]int, bool) {
 	/*
 		To take a list of ice cream data and insert data using an atomic transaction
 		Arguments: List of ice cream data
@@ -18,161 +50,2097 @@ func InsertRecord(iceCreamData []*structs.IceCreamDataStruct) ([]int, bool) {
 	sourcingValuesMap := make(map[string]bool)
 	ingredientsMap := make(map[string]bool)
 	dietaryCertificationsMap := make(map[string]bool)
+	tagsMap := make(map[string]bool)
 	for _, iceCream := range iceCreamData {
-		// Creating maps out of sourcing values, ingredients and dietary certifications
+		// Creating maps out of sourcing values, ingredients and dietary certifications of all products
 		// Using Maps to fetch and keep unique values of each property
 		// These unique values will later be inserted into corresponding tables
-		sourcingValuesMap = utils.ListToMap(iceCream.SourcingValues)
-		ingredientsMap = utils.ListToMap(iceCream.Ingredients)
-		if iceCream.DietaryCertifications != "" {
-			dietaryCertificationsMap[iceCream.DietaryCertifications] = true
+		for name := range utils.ListToMap(iceCream.SourcingValues) {
+			sourcingValuesMap[name] = true
+		}
+		for name := range utils.ListToMap(iceCream.Ingredients.Names()) {
+			ingredientsMap[name] = true
+		}
+		for name := range utils.ListToMap(iceCream.DietaryCertifications) {
+			dietaryCertificationsMap[name] = true
+		}
+		for name := range utils.ListToMap(iceCream.Tags) {
+			tagsMap[name] = true
 		}
 	}
 	// Creating mysql transaction
 	// If an query operation returns success=false, transaction will be rolled back, else committed at last
-	mySqlTxn, mySqlTxnErr := mysqlc.MySqlDB.Begin()
+	// All queries of the transaction run with its context, cancelling it rolls the transaction back
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
 	if mySqlTxnErr != nil {
-		panic(mySqlTxnErr.Error())
+		return nil, false
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
+	// Inserting data into sourcingvalue, ingredient, dietarycertification, tag tables
+	// Names are normalized and skipped if an entry with the same normalized key exists already
+	// (e.g. "milk " when "Milk" exists), so that variants of a name don't become duplicate entries
+	success = InsertIntoSourcingValue(txnCtx, mySqlTxn, sourcingValuesMap)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
+		return nil, false
 	}
-	// Inserting data into sourcingvalue, ingredient, dietarycertification tables
-	// All three tables have unique constraint on name column to avoid duplicate entries
-	// Therefore, insert ignore query is being used to avoid error if name already exists in table
-	success = InsertIntoSourcingValue(mySqlTxn, sourcingValuesMap)
+	success = InsertIntoIngredient(txnCtx, mySqlTxn, ingredientsMap)
 	if !success {
-		mySqlTxn.Rollback()
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
 		return nil, false
 	}
-	success = InsertIntoIngredient(mySqlTxn, ingredientsMap)
+	success = InsertIntoDietaryCertification(txnCtx, mySqlTxn, dietaryCertificationsMap)
 	if !success {
-		mySqlTxn.Rollback()
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
 		return nil, false
 	}
-	success = InsertIntoDietaryCertification(mySqlTxn, dietaryCertificationsMap)
+	success = InsertIntoTag(txnCtx, mySqlTxn, tagsMap)
 	if !success {
-		mySqlTxn.Rollback()
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
 		return nil, false
 	}
 
 	idList := make([]int, 0)
 	for _, iceCream := range iceCreamData {
-		id, success := InsertIntoProduct(mySqlTxn, iceCream)
+		id, success := InsertIntoProduct(txnCtx, mySqlTxn, iceCream)
 		// success: false, if an error occurs while running the query
 		// success: true, if record is inserted successfully
 		// id: the primary key of the inserted record and will be 0 in case of error
 		if !success {
-			mySqlTxn.Rollback()
+			rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
 			return nil, false
 		} else {
 			idList = append(idList, id)
 			// Data will be inserted to relation table of product and sourcing value
 			if len(iceCream.SourcingValues) > 0 {
-				success = InsertIntoProductSourcingValue(mySqlTxn, id, iceCream.SourcingValues)
+				success = InsertIntoProductSourcingValue(txnCtx, mySqlTxn, id, iceCream.SourcingValues)
+				if !success {
+					rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
+					return nil, false
+				}
+			}
+			// Data will be inserted to relation table of product and dietary certification
+			if len(iceCream.DietaryCertifications) > 0 {
+				success = InsertIntoProductDietaryCertification(txnCtx, mySqlTxn, id, iceCream.DietaryCertifications)
 				if !success {
-					mySqlTxn.Rollback()
+					rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
 					return nil, false
 				}
 			}
 			// Data will be inserted to relation table of product and ingredient
 			if len(iceCream.Ingredients) > 0 {
-				success = InsertIntoProductIngredient(mySqlTxn, id, iceCream.Ingredients)
+				success = InsertIntoProductIngredient(txnCtx, mySqlTxn, id, iceCream.Ingredients)
+				if !success {
+					rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
+					return nil, false
+				}
+			}
+			// Data will be inserted to relation table of product and allergen
+			if len(iceCream.Allergens) > 0 {
+				success = InsertIntoProductAllergen(txnCtx, mySqlTxn, id, iceCream.Allergens)
+				if !success {
+					rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
+					return nil, false
+				}
+			}
+			// Nutrition facts will be inserted, if the product declares them
+			if iceCream.Nutrition != nil {
+				success = InsertIntoProductNutrition(txnCtx, mySqlTxn, id, iceCream.Nutrition)
+				if !success {
+					rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
+					return nil, false
+				}
+			}
+			// Data will be inserted to relation table of product and category, missing categories are inserted
+			if len(iceCream.Categories) > 0 {
+				categoryIds, success := insertCategoryPaths(txnCtx, mySqlTxn, iceCream.Categories)
+				if success {
+					success = InsertIntoProductCategory(txnCtx, mySqlTxn, id, categoryIds)
+				}
+				if !success {
+					rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
+					return nil, false
+				}
+			}
+			// Data will be inserted to relation table of product and tag
+			if len(iceCream.Tags) > 0 {
+				success = InsertIntoProductTag(txnCtx, mySqlTxn, id, iceCream.Tags)
 				if !success {
-					mySqlTxn.Rollback()
+					rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
 					return nil, false
 				}
 			}
+			// Storing the newly created product as its first revision
+			success = InsertRevision(txnCtx, mySqlTxn, id)
+			if !success {
+				rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert)
+				return nil, false
+			}
 		}
 	}
-	mySqlTxn.Commit()
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionInsert) {
+		return nil, false
+	}
+	logger.FromContext(ctx).Info(constants.BenNJerryLogBucketName, logIdentifier+"InsertRecord",
+		"Inserted "+strconv.Itoa(len(idList))+" record(s)")
 	return idList, true
 }
 
-func UpdateRecord(id int, iceCreamData *structs.IceCreamDataStruct, fieldMap map[string]bool) bool {
+func UpdateRecord(ctx context.Context, id int, iceCreamData *structs.IceCreamDataStruct,
+	fieldMap map[string]bool) bool {
 	/*
 		To take an id and ice cream data and update data for that id using an atomic transaction
 		Arguments: List of ice cream data
 		Return: Boolean to indicate success or failure
 	*/
-	success := true
 	// Creating mysql transaction
 	// If an query operation returns success=false, transaction will be rolled back, else committed at last
-	mySqlTxn, mySqlTxnErr := mysqlc.MySqlDB.Begin()
+	// All queries of the transaction run with its context, cancelling it rolls the transaction back
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
 	if mySqlTxnErr != nil {
-		panic(mySqlTxnErr.Error())
+		return false
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionUpdate)
+	if !updateRecord(txnCtx, mySqlTxn, id, iceCreamData, fieldMap) {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionUpdate)
+		return false
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionUpdate) {
+		return false
 	}
+	logger.FromContext(ctx).Info(constants.BenNJerryLogBucketName, logIdentifier+"UpdateRecord",
+		"Updated record with id "+strconv.Itoa(id))
+	return true
+}
+
+func updateRecord(ctx context.Context, txn *sql.Tx, id int, iceCreamData *structs.IceCreamDataStruct,
+	fieldMap map[string]bool) bool {
+	/*
+		To take an id and ice cream data and update data of the fields for that id inside a transaction,
+		storing the updated product as its next revision
+		The transaction is rolled back by the caller, if it fails
+	*/
 	// Updating data in product table
-	success = UpdateProductById(mySqlTxn, id, iceCreamData, fieldMap)
+	success := UpdateProductById(ctx, txn, id, iceCreamData, fieldMap)
 	if !success {
-		mySqlTxn.Rollback()
 		return false
 	}
 	if _, exists := fieldMap["sourcing_values"]; exists {
 		sourcingValuesMap := utils.ListToMap(iceCreamData.SourcingValues)
 		// Inserting any sourcing value name that is not already in table
-		success = InsertIntoSourcingValue(mySqlTxn, sourcingValuesMap)
+		success = InsertIntoSourcingValue(ctx, txn, sourcingValuesMap)
 		if !success {
-			mySqlTxn.Rollback()
 			return false
 		}
 		// Updating relation table of product and sourcingvalue
-		success = UpdateProductSourcingValueByProductIdPK(mySqlTxn, id, sourcingValuesMap)
+		success = UpdateProductSourcingValueByProductIdPK(ctx, txn, id, sourcingValuesMap)
+		if !success {
+			return false
+		}
+	}
+	if _, exists := fieldMap["dietary_certifications"]; exists {
+		dietaryCertificationsMap := utils.ListToMap(iceCreamData.DietaryCertifications)
+		// Inserting any dietary certification name that is not already in table
+		success = InsertIntoDietaryCertification(ctx, txn, dietaryCertificationsMap)
+		if !success {
+			return false
+		}
+		// Updating relation table of product and dietarycertification
+		success = UpdateProductDietaryCertificationByProductIdPK(ctx, txn, id, dietaryCertificationsMap)
 		if !success {
-			mySqlTxn.Rollback()
 			return false
 		}
 	}
 	if _, exists := fieldMap["ingredients"]; exists {
-		ingredientsMap := utils.ListToMap(iceCreamData.Ingredients)
+		ingredientsMap := utils.ListToMap(iceCreamData.Ingredients.Names())
 		// Inserting any ingredient name that is not already in table
-		success = InsertIntoIngredient(mySqlTxn, ingredientsMap)
+		success = InsertIntoIngredient(ctx, txn, ingredientsMap)
 		if !success {
-			mySqlTxn.Rollback()
 			return false
 		}
-		// Updating relation table of product and ingredient
-		success = UpdateProductIngredientByProductIdPK(mySqlTxn, id, ingredientsMap)
+		// Updating relation table of product and ingredient, keeping the order of the list
+		success = UpdateProductIngredientByProductIdPK(ctx, txn, id, iceCreamData.Ingredients)
 		if !success {
-			mySqlTxn.Rollback()
 			return false
 		}
 	}
-	mySqlTxn.Commit()
-	return true
+	if _, exists := fieldMap["allergens"]; exists {
+		// Updating relation table of product and allergen, allergens must already exist in the taxonomy
+		success = UpdateProductAllergenByProductIdPK(ctx, txn, id, iceCreamData.Allergens)
+		if !success {
+			return false
+		}
+	}
+	if _, exists := fieldMap["nutrition"]; exists {
+		// Replacing nutrition facts as a whole, null removes them
+		success = UpdateProductNutritionByProductIdPK(ctx, txn, id, iceCreamData.Nutrition)
+		if !success {
+			return false
+		}
+	}
+	if _, exists := fieldMap["categories"]; exists {
+		// Selecting ids of the categories, inserting any missing along their paths (e.g. rolling back
+		// to a category deleted since), then replacing the categories of the product
+		categoryIds, success := insertCategoryPaths(ctx, txn, iceCreamData.Categories)
+		if success {
+			success = UpdateProductCategoryByProductIdPK(ctx, txn, id, categoryIds)
+		}
+		if !success {
+			return false
+		}
+	}
+	if _, exists := fieldMap["tags"]; exists {
+		tagsMap := utils.ListToMap(iceCreamData.Tags)
+		// Inserting any tag name that is not already in table
+		success = InsertIntoTag(ctx, txn, tagsMap)
+		if !success {
+			return false
+		}
+		// Updating relation table of product and tag
+		success = UpdateProductTagByProductIdPK(ctx, txn, id, tagsMap)
+		if !success {
+			return false
+		}
+	}
+	// Storing a full snapshot of the updated product as its next revision
+	return InsertRevision(ctx, txn, id)
 }
 
-func DropRecord(id int) bool {
+func DropRecord(ctx context.Context, id int) bool {
 	/*
 		To take an id and delete data for that id using an atomic transaction
 		Arguments: id
 		Return: Boolean to indicate success or failure
 	*/
 	success := true
-	mySqlTxn, mySqlTxnErr := mysqlc.MySqlDB.Begin()
+	// All queries of the transaction run with its context, cancelling it rolls the transaction back
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
 	if mySqlTxnErr != nil {
-		panic(mySqlTxnErr.Error())
+		return false
 	}
-	// Deleting references of record in relation table of product and sourcingvalue
-	success = DeleteFromProductSourcingValueByProductIdPK(mySqlTxn, id)
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionDrop)
+	// Urls of its images, to delete the uploaded ones no other product refers to, once the product is deleted
+	imageURLs, success := SelectImageURLFromProductById(txnCtx, mySqlTxn, id)
 	if !success {
-		mySqlTxn.Rollback()
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDrop)
 		return false
 	}
-	// Deleting references of record in relation table of product and ingredient
-	success = DeleteFromProductIngredientByProductIdPK(mySqlTxn, id)
+	packagingImageURLs, success := SelectPackagingImageFromProductVariantByProductIdPK(txnCtx, mySqlTxn, id)
 	if !success {
-		mySqlTxn.Rollback()
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDrop)
 		return false
 	}
+	imageURLs = append(imageURLs, packagingImageURLs...)
 	// Deleting actual record from product table
-	success = DeleteFromProductById(mySqlTxn, id)
+	// Its sourcing values, ingredients, revisions and variants are deleted along by the cascading foreign keys
+	success = DeleteFromProductById(txnCtx, mySqlTxn, id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDrop)
+		return false
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDrop) {
+		return false
+	}
+	logger.FromContext(ctx).Info(constants.BenNJerryLogBucketName, logIdentifier+"DropRecord",
+		"Deleted record with id "+strconv.Itoa(id))
+	// Images left behind, if this fails, are deleted by the janitor along with other unreferenced images
+	CleanUpProductImages(ctx, imageURLs)
+	// Sourcing values, ingredients, dietary certifications and tags left unused are deleted by the janitor
+	return true
+}
+
+func CleanUpUnUsed(ctx context.Context, dryRun bool) (*UnUsedProperties, bool) {
+	/*
+		To find sourcing values, ingredients, dietary certifications and tags not used by any product
+		and delete them (unless dryRun) using an atomic transaction
+		Return: Names of unused entries, as found before deleting them, with the number of rows actually deleted
+	*/
+	unUsed := &UnUsedProperties{RowsDeleted: make(map[string]int64)}
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return nil, false
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionCleanUp)
+	var success bool
+	if unUsed.SourcingValues, success = SelectUnUsedSourcingValue(txnCtx, mySqlTxn); !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCleanUp)
+		return nil, false
+	}
+	if unUsed.Ingredients, success = SelectUnUsedIngredient(txnCtx, mySqlTxn); !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCleanUp)
+		return nil, false
+	}
+	if unUsed.DietaryCertifications, success = SelectUnUsedDietaryCertification(txnCtx, mySqlTxn); !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCleanUp)
+		return nil, false
+	}
+	if unUsed.Tags, success = SelectUnUsedTag(txnCtx, mySqlTxn); !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCleanUp)
+		return nil, false
+	}
+	if !dryRun {
+		// Foreign keys refuse deleting an entry that has been attached to a product in the meantime,
+		// so rows deleted may be fewer than the names found
+		deleteFuncs := []struct {
+			table      string
+			deleteFunc func(context.Context, *sql.Tx) (int64, bool)
+		}{
+			{"sourcingvalue", DeleteUnUsedSourcingValue},
+			{"ingredient", DeleteUnUsedIngredient},
+			{"dietarycertification", DeleteUnUsedDietaryCertification},
+			{"tag", DeleteUnUsedTag},
+		}
+		for _, unUsedDelete := range deleteFuncs {
+			rowsDeleted, success := unUsedDelete.deleteFunc(txnCtx, mySqlTxn)
+			if !success {
+				rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCleanUp)
+				return nil, false
+			}
+			unUsed.RowsDeleted[unUsedDelete.table] += rowsDeleted
+		}
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCleanUp) {
+		return nil, false
+	}
+	return unUsed, true
+}
+
+func ValidateIngredients(ingredients structs.IngredientList) bool {
+	/*
+		To check that every ingredient has a name and that declared percentages are between 0 and 100, in total too
+	*/
+	total := 0.0
+	for _, ingredient := range ingredients {
+		if ingredient == nil || strings.TrimSpace(ingredient.Name) == "" {
+			return false
+		}
+		if ingredient.Percentage != nil {
+			if *ingredient.Percentage < 0 || *ingredient.Percentage > 100 {
+				return false
+			}
+			total += *ingredient.Percentage
+		}
+	}
+	// allowing for rounding of percentages declared with decimals
+	return total <= 100.005
+}
+
+func ValidateAllergens(ctx context.Context, allergens []*structs.AllergenStruct) (bool, bool) {
+	/*
+		To check that every allergen is a code of the taxonomy, with a known level and listed only once
+		Return: valid: false, if an allergen isn't valid; success: false, if an error occurs
+	*/
+	if len(allergens) == 0 {
+		return true, true
+	}
+	taxonomy, success := SelectFromAllergen(ctx)
 	if !success {
-		mySqlTxn.Rollback()
+		return false, false
+	}
+	codeMap := make(map[string]bool)
+	for _, allergen := range taxonomy {
+		codeMap[allergen.Code] = true
+	}
+	seenCodeMap := make(map[string]bool)
+	for _, allergen := range allergens {
+		if allergen == nil || !codeMap[allergen.Code] || seenCodeMap[allergen.Code] {
+			return false, true
+		}
+		if allergen.Level != constants.AllergenLevelContains && allergen.Level != constants.AllergenLevelMayContain {
+			return false, true
+		}
+		seenCodeMap[allergen.Code] = true
+	}
+	return true, true
+}
+
+func ValidateNutrition(facts *structs.NutritionStruct) bool {
+	/*
+		To check nutrition facts (nil, if not declared): a serving size > 0 grams and nutrients of the taxonomy,
+		each once, in a unit it can be given in, with an amount >= 0 per 100 g, else per serving
+		Amounts are converted to the unit of the nutrient per 100 g, within what 100 g can hold,
+		and stored in it along with the amounts per serving
+	*/
+	if facts == nil {
+		return true
+	}
+	if facts.ServingSize <= 0 || facts.ServingSize > constants.NutritionServingSizeMax {
 		return false
 	}
-	mySqlTxn.Commit()
-	// Post deletion, there might be unused sourcing values, ingredients and dietary certifications
-	// Deleting such unused entries from the tables to avoid stale data
-	DeleteUnUsedSourcingValue()
-	DeleteUnUsedIngredient()
-	DeleteUnUsedDietaryCertification()
+	seenNutrients := make(map[string]bool)
+	for _, nutrient := range facts.Nutrients {
+		if nutrient == nil {
+			return false
+		}
+		taxonomy, _, exists := nutrition.Lookup(nutrient.Nutrient)
+		if !exists || seenNutrients[taxonomy.Code] {
+			return false
+		}
+		seenNutrients[taxonomy.Code] = true
+		var amount float64
+		if nutrient.Per100g != nil {
+			amount = *nutrient.Per100g
+		} else if nutrient.PerServing != nil {
+			amount = nutrition.Per100g(*nutrient.PerServing, facts.ServingSize)
+		} else {
+			return false
+		}
+		per100g, validUnit := taxonomy.Convert(amount, nutrient.Unit)
+		if !validUnit || amount < 0 || nutrition.Round(per100g) > taxonomy.Max {
+			return false
+		}
+		per100g = nutrition.Round(per100g)
+		nutrient.Nutrient = taxonomy.Code
+		nutrient.Per100g = &per100g
+	}
+	completeNutrition(facts)
 	return true
 }
+
+func completeNutrition(facts *structs.NutritionStruct) {
+	/*
+		To take nutrition facts with amounts per 100 g and set the unit and amount per serving of every nutrient,
+		and sort them in the order of the label
+	*/
+	for _, nutrient := range facts.Nutrients {
+		perServing := nutrition.PerServing(*nutrient.Per100g, facts.ServingSize)
+		nutrient.PerServing = &perServing
+		if taxonomy, _, exists := nutrition.Lookup(nutrient.Nutrient); exists {
+			nutrient.Unit = taxonomy.Unit
+		}
+	}
+	sort.SliceStable(facts.Nutrients, func(i, j int) bool {
+		_, first, _ := nutrition.Lookup(facts.Nutrients[i].Nutrient)
+		_, second, _ := nutrition.Lookup(facts.Nutrients[j].Nutrient)
+		return first < second
+	})
+}
+
+func RollbackRecord(ctx context.Context, id int, revision int) (bool, bool) {
+	/*
+		To take an id and a revision number and re-apply the snapshot stored in that revision
+		Snapshot is applied through UpdateRecord, so it's atomic and is itself stored as a new revision
+		Return: found: false, if revision doesn't exist for the id; success: false, if an error occurs
+	*/
+	funcName := "RollbackRecord"
+	productRevision, success := SelectFromProductRevisionByProductIdPK(ctx, id, revision)
+	if !success {
+		return true, false
+	}
+	if productRevision == nil {
+		return false, true
+	}
+	var iceCreamData *structs.IceCreamDataStruct
+	umMarshalErr := json.Unmarshal([]byte(productRevision.Snapshot), &iceCreamData)
+	if umMarshalErr != nil || iceCreamData == nil {
+		if umMarshalErr != nil {
+			logger.FromContext(ctx).Error(constants.BenNJerryLogBucketName, logIdentifier+funcName,
+				constants.UnMarshalErrorString, umMarshalErr.Error())
+		}
+		return true, false
+	}
+	// Every field of the product is part of the snapshot, so all of them are re-applied
+	fieldMap := utils.ListToMap(allFields)
+	return true, UpdateRecord(ctx, id, iceCreamData, fieldMap)
+}
+
+func InsertRevision(ctx context.Context, txn *sql.Tx, id int) bool {
+	/*
+		To take an id, build a full snapshot of the product (including ingredients, sourcing values,
+		dietary certifications, categories and tags) as seen inside the transaction and store it in product_revision table
+	*/
+	funcName := "InsertRevision"
+	iceCreamData, success := SelectSnapshotById(ctx, txn, id)
+	if !success {
+		return false
+	}
+	snapshot, marshalErr := json.Marshal(iceCreamData)
+	if marshalErr != nil {
+		logger.FromContext(ctx).Error(constants.BenNJerryLogBucketName, logIdentifier+funcName,
+			constants.JsonSerializationErrorMessage, marshalErr.Error())
+		return false
+	}
+	_, success = InsertIntoProductRevision(ctx, txn, id, snapshot)
+	return success
+}
+
+func SelectSnapshotById(ctx context.Context, txn *sql.Tx, id int) (*structs.IceCreamDataStruct, bool) {
+	/*
+		To take an id and collect complete information of the product inside a transaction
+	*/
+	productData, success := SelectFromProductById(ctx, txn, id)
+	if !success || productData.Id == 0 {
+		return nil, false
+	}
+	iceCreamData := &structs.IceCreamDataStruct{
+		Id:                    productData.Id,
+		ProductId:             productData.ProductId,
+		Name:                  productData.Name,
+		Description:           productData.Description,
+		Story:                 productData.Story,
+		ImageClosed:           productData.ImageClosed,
+		ImageOpened:           productData.ImageOpened,
+		AllergyInfo:           productData.Allergy,
+		PublishAt:             productData.PublishAt,
+		UnpublishAt:           productData.UnpublishAt,
+		SourcingValues:        make([]string, 0),
+		DietaryCertifications: make([]string, 0),
+	}
+	if productData.AllergensDeclared == 1 {
+		iceCreamData.Allergens, success = SelectFromProductAllergenByProductIdPK(ctx, txn, id)
+		if !success {
+			return nil, false
+		}
+	}
+	certifications, success := SelectFromProductDietaryCertificationByProductIdPK(ctx, txn, id)
+	if !success {
+		return nil, false
+	}
+	for _, productProperty := range certifications {
+		iceCreamData.DietaryCertifications = append(iceCreamData.DietaryCertifications, productProperty.PropertyName)
+	}
+	sourcingValues, success := SelectFromProductSourcingValueByProductIdPK(ctx, txn, id)
+	if !success {
+		return nil, false
+	}
+	for _, productProperty := range sourcingValues {
+		iceCreamData.SourcingValues = append(iceCreamData.SourcingValues, productProperty.PropertyName)
+	}
+	iceCreamData.Ingredients, success = SelectFromProductIngredientByProductIdPK(ctx, txn, id)
+	if !success {
+		return nil, false
+	}
+	iceCreamData.Nutrition, success = SelectFromProductNutritionByProductIdPK(ctx, txn, id)
+	if !success {
+		return nil, false
+	}
+	iceCreamData.Categories, success = SelectFromProductCategoryByProductIdPK(ctx, txn, id)
+	if !success {
+		return nil, false
+	}
+	tags, success := SelectFromProductTagByProductIdPK(ctx, txn, id)
+	if !success {
+		return nil, false
+	}
+	iceCreamData.Tags = make([]string, 0)
+	for _, productProperty := range tags {
+		iceCreamData.Tags = append(iceCreamData.Tags, productProperty.PropertyName)
+	}
+	return iceCreamData, true
+}
+
+func CreateVocabularyEntry(ctx context.Context, vocabulary *Vocabulary, name string) (int, VocabularyResult) {
+	/*
+		To take a vocabulary and a name and insert it as a new entry using an atomic transaction
+		The name is stored normalized, and one with the same normalized key (e.g. differing only in case) already exists
+		Return: id of the inserted entry, 0 if it isn't inserted
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return 0, VocabularyError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+	existing, success := SelectFromVocabularyByName(txnCtx, mySqlTxn, vocabulary, name)
+	if !success || existing != nil {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		if !success {
+			return 0, VocabularyError
+		}
+		return 0, VocabularyNameExists
+	}
+	id, success := InsertIntoVocabulary(txnCtx, mySqlTxn, vocabulary, name)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return 0, VocabularyError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary) {
+		return 0, VocabularyError
+	}
+	return id, VocabularyChanged
+}
+
+func RenameVocabularyEntry(ctx context.Context, vocabulary *Vocabulary, id int, name string) VocabularyResult {
+	/*
+		To take an entry of a vocabulary and rename it using an atomic transaction
+		Products refer to the entry by id, so all of them carry the new name, a new revision is stored for each
+		Renaming to the name of another entry is refused, those entries are to be merged instead
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return VocabularyError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+	entry, success := SelectFromVocabularyById(txnCtx, mySqlTxn, vocabulary, id)
+	if !success || entry == nil {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return vocabularyLookupResult(success)
+	}
+	existing, success := SelectFromVocabularyByName(txnCtx, mySqlTxn, vocabulary, name)
+	if !success || (existing != nil && existing.Id != id) {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		if !success {
+			return VocabularyError
+		}
+		return VocabularyNameExists
+	}
+	productIdPKs, success := SelectProductIdPKByVocabularyId(txnCtx, mySqlTxn, vocabulary, id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return VocabularyError
+	}
+	success = UpdateVocabularyNameById(txnCtx, mySqlTxn, vocabulary, id, name)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return VocabularyError
+	}
+	// Storing a snapshot of every product carrying the new name as its next revision
+	success = insertRevisions(txnCtx, mySqlTxn, productIdPKs)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return VocabularyError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary) {
+		return VocabularyError
+	}
+	return VocabularyChanged
+}
+
+func MergeVocabularyEntry(ctx context.Context, vocabulary *Vocabulary, id int, intoId int) VocabularyResult {
+	/*
+		To take two entries of a vocabulary and merge the first into the second using an atomic transaction
+		Products using the first entry are moved to the second (keeping it where they use both) and the first is
+		deleted, a new revision is stored for each product moved
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return VocabularyError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+	for _, entryId := range []int{id, intoId} {
+		entry, success := SelectFromVocabularyById(txnCtx, mySqlTxn, vocabulary, entryId)
+		if !success || entry == nil {
+			rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+			return vocabularyLookupResult(success)
+		}
+	}
+	productIdPKs, success := SelectProductIdPKByVocabularyId(txnCtx, mySqlTxn, vocabulary, id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return VocabularyError
+	}
+	// Moving products to the other entry, rows of products already using it are left to be deleted below
+	success = UpdateProductVocabularyIdByVocabularyId(txnCtx, mySqlTxn, vocabulary, id, intoId)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return VocabularyError
+	}
+	success = DeleteFromProductVocabularyByVocabularyId(txnCtx, mySqlTxn, vocabulary, id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return VocabularyError
+	}
+	success = DeleteFromVocabularyById(txnCtx, mySqlTxn, vocabulary, id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return VocabularyError
+	}
+	// Storing a snapshot of every product moved as its next revision
+	success = insertRevisions(txnCtx, mySqlTxn, productIdPKs)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return VocabularyError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary) {
+		return VocabularyError
+	}
+	return VocabularyChanged
+}
+
+func DeleteVocabularyEntry(ctx context.Context, vocabulary *Vocabulary, id int) VocabularyResult {
+	/*
+		To take an entry of a vocabulary and delete it using an atomic transaction
+		Deleting an entry used by any product (even an inactive one) is refused
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return VocabularyError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+	entry, success := SelectFromVocabularyById(txnCtx, mySqlTxn, vocabulary, id)
+	if !success || entry == nil {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return vocabularyLookupResult(success)
+	}
+	productIdPKs, success := SelectProductIdPKByVocabularyId(txnCtx, mySqlTxn, vocabulary, id)
+	if !success || len(productIdPKs) > 0 {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		if !success {
+			return VocabularyError
+		}
+		return VocabularyInUse
+	}
+	// Foreign keys refuse deleting the entry, if it has been attached to a product in the meantime
+	success = DeleteFromVocabularyById(txnCtx, mySqlTxn, vocabulary, id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return VocabularyError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary) {
+		return VocabularyError
+	}
+	return VocabularyChanged
+}
+
+func TranslateVocabularyEntry(ctx context.Context, vocabulary *Vocabulary, id int, locale string,
+	name string) VocabularyResult {
+	/*
+		To take an entry of a vocabulary and store its translated name in a locale using an atomic transaction
+		The translated name of the locale is replaced, if it exists already
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return VocabularyError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+	entry, success := SelectFromVocabularyById(txnCtx, mySqlTxn, vocabulary, id)
+	if !success || entry == nil {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return vocabularyLookupResult(success)
+	}
+	success = InsertIntoVocabularyTranslation(txnCtx, mySqlTxn, vocabulary, id, locale, name)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary)
+		return VocabularyError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVocabulary) {
+		return VocabularyError
+	}
+	return VocabularyChanged
+}
+
+func LocalizeRecord(ctx context.Context, iceCreamData *structs.IceCreamDataStruct, chain []string) []string {
+	/*
+		To take ice cream data of a product and a fallback chain of locales (see localization.FallbackChain)
+		and replace name, description, story and names of sourcing values, ingredients, dietary certifications and tags
+		by their first translation in the chain, texts without any are kept as stored (in the default locale)
+		Return: locales the texts are in, in order of the chain, the default locale last if any text is kept
+	*/
+	if len(chain) == 0 {
+		return []string{constants.DefaultLocale}
+	}
+	usedLocaleMap := make(map[string]bool)
+	localize := func(text string, translations map[string]string) string {
+		translation, locale := localization.Pick(chain, translations)
+		if locale == "" {
+			usedLocaleMap[constants.DefaultLocale] = true
+			return text
+		}
+		usedLocaleMap[locale] = true
+		return translation
+	}
+	// Texts of the product, a text is only localized if it's there to begin with
+	textTranslations := map[string]map[string]string{"name": {}, "description": {}, "story": {}}
+	productTranslations, _ := SelectFromProductTranslationByProductIdPK(ctx, iceCreamData.Id)
+	for _, translation := range productTranslations {
+		textTranslations["name"][translation.Locale] = translation.Name
+		textTranslations["description"][translation.Locale] = translation.Description
+		textTranslations["story"][translation.Locale] = translation.Story
+	}
+	if iceCreamData.Name != "" {
+		iceCreamData.Name = localize(iceCreamData.Name, textTranslations["name"])
+	}
+	if iceCreamData.Description != "" {
+		iceCreamData.Description = localize(iceCreamData.Description, textTranslations["description"])
+	}
+	if iceCreamData.Story != "" {
+		iceCreamData.Story = localize(iceCreamData.Story, textTranslations["story"])
+	}
+	// Names of the entries of vocabularies used by the product
+	nameTranslations := SelectVocabularyTranslationByProductIdPK(ctx, Vocabularies["sourcing_values"],
+		iceCreamData.Id, chain)
+	for index, name := range iceCreamData.SourcingValues {
+		iceCreamData.SourcingValues[index] = localize(name, nameTranslations[name])
+	}
+	nameTranslations = SelectVocabularyTranslationByProductIdPK(ctx, Vocabularies["ingredients"], iceCreamData.Id,
+		chain)
+	for _, ingredient := range iceCreamData.Ingredients {
+		ingredient.Name = localize(ingredient.Name, nameTranslations[ingredient.Name])
+	}
+	nameTranslations = SelectVocabularyTranslationByProductIdPK(ctx, Vocabularies["dietary_certifications"],
+		iceCreamData.Id, chain)
+	for index, name := range iceCreamData.DietaryCertifications {
+		iceCreamData.DietaryCertifications[index] = localize(name, nameTranslations[name])
+	}
+	nameTranslations = SelectVocabularyTranslationByProductIdPK(ctx, Vocabularies["tags"], iceCreamData.Id, chain)
+	for index, name := range iceCreamData.Tags {
+		iceCreamData.Tags[index] = localize(name, nameTranslations[name])
+	}
+	usedLocales := make([]string, 0, len(usedLocaleMap))
+	for _, locale := range chain {
+		if usedLocaleMap[locale] {
+			usedLocales = append(usedLocales, locale)
+		}
+	}
+	if usedLocaleMap[constants.DefaultLocale] {
+		usedLocales = append(usedLocales, constants.DefaultLocale)
+	}
+	return usedLocales
+}
+
+func vocabularyLookupResult(success bool) VocabularyResult {
+	/*
+		To tell why an entry of a vocabulary couldn't be selected: an error occurred or it doesn't exist
+	*/
+	if !success {
+		return VocabularyError
+	}
+	return VocabularyNotFound
+}
+
+func insertRevisions(ctx context.Context, txn *sql.Tx, productIdPKs []int) bool {
+	/*
+		To store a new revision of every product in the list, e.g. after an entry they use has been renamed
+	*/
+	for _, productIdPK := range productIdPKs {
+		if !InsertRevision(ctx, txn, productIdPK) {
+			return false
+		}
+	}
+	return true
+}
+
+func SaveImage(ctx context.Context, img *imagestore.Image) (*structs.ImageStruct, bool) {
+	/*
+		To store an uploaded (and validated) image and its thumbnail and insert it into image table
+		The row is inserted first, so that files stored are always known to the clean up of unreferenced images
+	*/
+	if imagestore.Store == nil {
+		logger.FromContext(ctx).Error(constants.ImageStoreLogBucketName, logIdentifier+"SaveImage",
+			constants.ImageStoreErrorMessage, "image storage not initialized")
+		return nil, false
+	}
+	image := &Image{Key: img.Key, ThumbnailKey: img.ThumbnailKey, ContentType: img.ContentType,
+		Width: img.Width, Height: img.Height, Size: img.Size}
+	if !InsertIntoImage(ctx, image) {
+		return nil, false
+	}
+	if err := imagestore.Save(ctx, img); err != nil {
+		logger.FromContext(ctx).Error(constants.ImageStoreLogBucketName, logIdentifier+"SaveImage",
+			constants.ImageStoreErrorMessage, err.Error())
+		metrics.ImageStorageErrors.WithLabels(constants.ImageStoragePut).Inc()
+		return nil, false
+	}
+	return &structs.ImageStruct{
+		URL:          imagestore.Store.URL(image.Key),
+		ThumbnailURL: imagestore.Store.URL(image.ThumbnailKey),
+		ContentType:  image.ContentType,
+		Width:        image.Width,
+		Height:       image.Height,
+		Size:         image.Size,
+	}, true
+}
+
+func ValidateImageURLs(ctx context.Context, urls ...string) (bool, bool) {
+	/*
+		To check that every url of the image storage refers to an uploaded image, external urls are kept as they are
+		Return: valid: false, if an image isn't found; success: false, if an error occurs
+	*/
+	keys := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if key, isStored := imagestore.KeyFromURL(url); isStored {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return true, true
+	}
+	images, success := SelectFromImageByKeys(ctx, keys)
+	if !success {
+		return false, false
+	}
+	found := make(map[string]bool)
+	for _, image := range images {
+		found[image.Key] = true
+	}
+	for _, key := range keys {
+		if !found[key] {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+func CleanUpProductImages(ctx context.Context, urls []string) ([]string, bool) {
+	/*
+		To delete the uploaded images of urls (e.g. of a deleted product) no product refers to anymore
+		Return: urls of images deleted
+	*/
+	keys := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if key, isStored := imagestore.KeyFromURL(url); isStored {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return []string{}, true
+	}
+	images, success := SelectFromImageByKeys(ctx, keys)
+	if !success {
+		return nil, false
+	}
+	return cleanUpImages(ctx, images, false)
+}
+
+func CleanUpStaleImages(ctx context.Context, dryRun bool) ([]string, bool) {
+	/*
+		To delete images no product refers to (unless dryRun), left behind e.g. by updated products or uploads
+		never referred, once they are older than the grace period
+		Return: urls of unreferenced images, as found before deleting them
+	*/
+	if imagestore.Store == nil {
+		return []string{}, true
+	}
+	images, success := SelectFromImageCreatedBefore(ctx, time.Now().Add(-constants.ImageUnReferencedGracePeriod))
+	if !success {
+		return nil, false
+	}
+	return cleanUpImages(ctx, images, dryRun)
+}
+
+func cleanUpImages(ctx context.Context, images []*Image, dryRun bool) ([]string, bool) {
+	/*
+		To delete every image no product refers to from image table, then its files from the image storage
+		A file that can't be deleted is logged and left in the storage, its image is deleted anyway
+		Return: urls of unreferenced images
+	*/
+	funcName := "cleanUpImages"
+	unReferenced := make([]string, 0)
+	for _, image := range images {
+		url := imagestore.Store.URL(image.Key)
+		if dryRun {
+			count, success := SelectImageReferenceCount(ctx, url)
+			if !success {
+				return nil, false
+			}
+			if count == 0 {
+				unReferenced = append(unReferenced, url)
+			}
+			continue
+		}
+		deleted, success := DeleteUnReferencedImageByKey(ctx, image.Key, url)
+		if !success {
+			return nil, false
+		}
+		if !deleted {
+			continue
+		}
+		unReferenced = append(unReferenced, url)
+		for _, key := range []string{image.Key, image.ThumbnailKey} {
+			if err := imagestore.Store.Delete(ctx, key); err != nil {
+				logger.FromContext(ctx).Error(constants.ImageStoreLogBucketName, logIdentifier+funcName,
+					constants.ImageDeleteErrorMessage, key+": "+err.Error())
+				metrics.ImageStorageErrors.WithLabels(constants.ImageStorageDelete).Inc()
+			}
+		}
+	}
+	return unReferenced, true
+}
+
+func ReplaceMarket(ctx context.Context, productIdPK int, market *structs.MarketStruct) bool {
+	/*
+		To take product_id (primary key of product table) and a (validated) market
+		and store its availability and prices using an atomic transaction
+		Prices of the market are replaced as a whole, so ranges of dates can be moved without overlapping in between
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return false
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionMarket)
+	success := InsertIntoProductMarket(txnCtx, mySqlTxn, productIdPK, market)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionMarket)
+		return false
+	}
+	success = DeleteFromProductPriceByMarket(txnCtx, mySqlTxn, productIdPK, market.Market)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionMarket)
+		return false
+	}
+	success = InsertIntoProductPrice(txnCtx, mySqlTxn, productIdPK, market.Market, market.Prices)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionMarket)
+		return false
+	}
+	return commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionMarket)
+}
+
+func CanonicalMarket(market string) (string, bool) {
+	/*
+		To validate a market, a country code of two letters (ISO 3166-1 alpha-2), and return it in upper case
+	*/
+	market = strings.ToUpper(strings.TrimSpace(market))
+	if len(market) != 2 || market[0] < 'A' || market[0] > 'Z' || market[1] < 'A' || market[1] > 'Z' {
+		return "", false
+	}
+	return market, true
+}
+
+func ValidateMarket(market *structs.MarketStruct) bool {
+	/*
+		To check that every price of a market has a currency code of three letters (ISO 4217), an amount >= 0
+		and dates as 2006-01-02 15:04:05, effective_from before effective_to, and that prices of a currency
+		don't overlap, at most the latest of them being without end
+		Currencies are stored in upper case and dates as formatted by PriceTimeLayout
+	*/
+	latestPrice := make(map[string]*structs.PriceStruct)
+	prices := append([]*structs.PriceStruct{}, market.Prices...)
+	for _, price := range prices {
+		if price == nil || price.Amount < 0 {
+			return false
+		}
+		price.Currency = strings.ToUpper(strings.TrimSpace(price.Currency))
+		if len(price.Currency) != 3 || strings.IndexFunc(price.Currency, func(r rune) bool {
+			return r < 'A' || r > 'Z'
+		}) != -1 {
+			return false
+		}
+		from, err := time.Parse(constants.PriceTimeLayout, strings.TrimSpace(price.EffectiveFrom))
+		if err != nil {
+			return false
+		}
+		price.EffectiveFrom = from.Format(constants.PriceTimeLayout)
+		if strings.TrimSpace(price.EffectiveTo) != "" {
+			to, err := time.Parse(constants.PriceTimeLayout, strings.TrimSpace(price.EffectiveTo))
+			if err != nil || !to.After(from) {
+				return false
+			}
+			price.EffectiveTo = to.Format(constants.PriceTimeLayout)
+		} else {
+			price.EffectiveTo = ""
+		}
+	}
+	// dates formatted alike compare in order as strings, so prices of a currency can be checked one after the other
+	sort.SliceStable(prices, func(i, j int) bool {
+		return prices[i].EffectiveFrom < prices[j].EffectiveFrom
+	})
+	for _, price := range prices {
+		if previous, exists := latestPrice[price.Currency]; exists &&
+			(previous.EffectiveTo == "" || previous.EffectiveTo > price.EffectiveFrom) {
+			return false
+		}
+		latestPrice[price.Currency] = price
+	}
+	return true
+}
+
+func EffectiveMarket(market *structs.MarketStruct, at time.Time) *structs.MarketStruct {
+	/*
+		To take a market with all its prices and return it with only the prices effective at a time,
+		i.e. at most one per currency
+	*/
+	moment := at.UTC().Format(constants.PriceTimeLayout)
+	effective := &structs.MarketStruct{Market: market.Market, Available: market.Available,
+		Prices: make([]*structs.PriceStruct, 0)}
+	for _, price := range market.Prices {
+		if price.EffectiveFrom <= moment && (price.EffectiveTo == "" || moment < price.EffectiveTo) {
+			effective.Prices = append(effective.Prices, price)
+		}
+	}
+	return effective
+}
+
+func CreateVariant(ctx context.Context, productIdPK int, variant *structs.VariantStruct) VariantResult {
+	/*
+		To take product_id (primary key of product table) and a (validated) variant
+		and insert it along with its nutrition values using an atomic transaction
+		Skus are unique across products, a variant with the same sku must not exist already
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return VariantError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionVariant)
+	existing, success := SelectFromProductVariantBySku(txnCtx, mySqlTxn, variant.Sku)
+	if !success || existing != nil {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVariant)
+		if !success {
+			return VariantError
+		}
+		return VariantSkuExists
+	}
+	id, success := InsertIntoProductVariant(txnCtx, mySqlTxn, productIdPK, variant)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVariant)
+		return VariantError
+	}
+	success = InsertIntoProductVariantNutrition(txnCtx, mySqlTxn, id, variant.Nutrition)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVariant)
+		return VariantError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVariant) {
+		return VariantError
+	}
+	return VariantChanged
+}
+
+func UpdateVariant(ctx context.Context, productIdPK int, variant *structs.VariantStruct) VariantResult {
+	/*
+		To take product_id (primary key of product table) and a (validated) variant of it, by sku,
+		and replace its name, size, packaging image and nutrition values using an atomic transaction
+		A packaging image no longer referred is deleted by the janitor along with other unreferenced images
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return VariantError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionVariant)
+	existing, success := SelectFromProductVariantBySku(txnCtx, mySqlTxn, variant.Sku)
+	if !success || existing == nil || existing.ProductId != productIdPK {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVariant)
+		return variantLookupResult(success)
+	}
+	success = UpdateProductVariantById(txnCtx, mySqlTxn, existing.Id, variant)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVariant)
+		return VariantError
+	}
+	success = DeleteFromProductVariantNutritionByVariantId(txnCtx, mySqlTxn, existing.Id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVariant)
+		return VariantError
+	}
+	success = InsertIntoProductVariantNutrition(txnCtx, mySqlTxn, existing.Id, variant.Nutrition)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVariant)
+		return VariantError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVariant) {
+		return VariantError
+	}
+	return VariantChanged
+}
+
+func DeleteVariant(ctx context.Context, productIdPK int, sku string) VariantResult {
+	/*
+		To take product_id (primary key of product table) and sku of a variant of it
+		and delete the variant using an atomic transaction
+		Its packaging image is deleted as well, unless another product or variant refers to it
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return VariantError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionVariant)
+	existing, success := SelectFromProductVariantBySku(txnCtx, mySqlTxn, sku)
+	if !success || existing == nil || existing.ProductId != productIdPK {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVariant)
+		return variantLookupResult(success)
+	}
+	success = DeleteFromProductVariantById(txnCtx, mySqlTxn, existing.Id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVariant)
+		return VariantError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionVariant) {
+		return VariantError
+	}
+	if existing.PackagingImage != "" {
+		CleanUpProductImages(ctx, []string{existing.PackagingImage})
+	}
+	return VariantChanged
+}
+
+func variantLookupResult(success bool) VariantResult {
+	/*
+		To tell the outcome of a change to a variant that isn't found (of the product), or couldn't be looked up
+	*/
+	if !success {
+		return VariantError
+	}
+	return VariantNotFound
+}
+
+func ValidateVariant(variant *structs.VariantStruct) bool {
+	/*
+		To check that a variant has a sku of letters, digits, '-', '_' and '.', a name and a size > 0 in a known unit,
+		and that its nutrition values are amounts per 100 g in the unit of nutrients of the taxonomy
+		Texts are stored trimmed, units and nutrients in lower case
+	*/
+	variant.Sku = strings.TrimSpace(variant.Sku)
+	variant.Name = strings.TrimSpace(variant.Name)
+	variant.SizeUnit = strings.ToLower(strings.TrimSpace(variant.SizeUnit))
+	variant.PackagingImage = strings.TrimSpace(variant.PackagingImage)
+	if !validSku(variant.Sku) || variant.Name == "" || variant.Size <= 0 {
+		return false
+	}
+	switch variant.SizeUnit {
+	case constants.VariantSizeUnitMilliliter, constants.VariantSizeUnitLiter, constants.VariantSizeUnitGram,
+		constants.VariantSizeUnitKilogram:
+	default:
+		return false
+	}
+	overrides := make(map[string]float64, len(variant.Nutrition))
+	for code, amount := range variant.Nutrition {
+		taxonomy, _, exists := nutrition.Lookup(code)
+		if !exists || amount < 0 || amount > taxonomy.Max {
+			return false
+		}
+		if _, seen := overrides[taxonomy.Code]; seen {
+			return false
+		}
+		overrides[taxonomy.Code] = nutrition.Round(amount)
+	}
+	variant.Nutrition = overrides
+	return true
+}
+
+func validSku(sku string) bool {
+	/*
+		To check that a sku isn't too long, starts with a letter or digit and has only letters, digits, '-', '_'
+		and '.', so that it can be given in urls as it is
+	*/
+	if sku == "" || len(sku) > constants.VariantSkuMaxLength || sku[0] == '-' || sku[0] == '_' || sku[0] == '.' {
+		return false
+	}
+	return strings.IndexFunc(sku, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_' && r != '.'
+	}) == -1
+}
+
+// Categories by id and by parent, to walk the category tree in memory, as it's small and read as a whole
+type categoryTree struct {
+	byId map[int]*Category
+	// children by id of their parent, 0 for root categories
+	children map[int][]*Category
+}
+
+func newCategoryTree(categories []*Category) *categoryTree {
+	tree := &categoryTree{byId: make(map[int]*Category), children: make(map[int][]*Category)}
+	for _, category := range categories {
+		tree.add(category)
+	}
+	return tree
+}
+
+func (tree *categoryTree) add(category *Category) {
+	tree.byId[category.Id] = category
+	tree.children[category.ParentId] = append(tree.children[category.ParentId], category)
+}
+
+func (tree *categoryTree) child(parentId int, name string) *Category {
+	/*
+		To find the child of a category (0 for root categories) by name, matched by its normalized key
+	*/
+	key := normalizer.Key(name)
+	for _, category := range tree.children[parentId] {
+		if category.NameKey == key {
+			return category
+		}
+	}
+	return nil
+}
+
+func (tree *categoryTree) resolve(path string) (*Category, bool) {
+	/*
+		To find a category by its path, e.g. "non-dairy > frozen dessert" finds "Frozen Dessert" of "Non-Dairy"
+	*/
+	names, valid := SplitCategoryPath(path)
+	if !valid {
+		return nil, false
+	}
+	var category *Category
+	for _, name := range names {
+		parentId := 0
+		if category != nil {
+			parentId = category.Id
+		}
+		if category = tree.child(parentId, name); category == nil {
+			return nil, false
+		}
+	}
+	return category, true
+}
+
+func (tree *categoryTree) path(id int) string {
+	/*
+		To join the names of a category and its parents, from the root category down
+	*/
+	names := make([]string, 0)
+	for category := tree.byId[id]; category != nil && len(names) < constants.CategoryMaxDepth; {
+		names = append([]string{category.Name}, names...)
+		category = tree.byId[category.ParentId]
+	}
+	return strings.Join(names, constants.CategoryPathSeparator)
+}
+
+func (tree *categoryTree) depth(id int) int {
+	/*
+		To count the levels from the root down to a category, 1 for a root category and 0 for none
+	*/
+	depth := 0
+	for category := tree.byId[id]; category != nil && depth < constants.CategoryMaxDepth; {
+		depth++
+		category = tree.byId[category.ParentId]
+	}
+	return depth
+}
+
+func (tree *categoryTree) height(id int) int {
+	/*
+		To count the levels of a category and its subcategories, 1 for a category without any
+	*/
+	height := 0
+	for _, child := range tree.children[id] {
+		if childHeight := tree.height(child.Id); childHeight > height {
+			height = childHeight
+		}
+	}
+	return height + 1
+}
+
+func (tree *categoryTree) descendants(id int) []int {
+	/*
+		To list ids of a category and all of its subcategories, level by level
+	*/
+	ids := []int{id}
+	for index := 0; index < len(ids); index++ {
+		for _, child := range tree.children[ids[index]] {
+			ids = append(ids, child.Id)
+		}
+	}
+	return ids
+}
+
+func (tree *categoryTree) branch(parentId int) []*structs.CategoryStruct {
+	/*
+		To build the children of a category (0 for root categories) with their subcategories, in order of name
+	*/
+	result := make([]*structs.CategoryStruct, 0, len(tree.children[parentId]))
+	for _, category := range tree.children[parentId] {
+		result = append(result, &structs.CategoryStruct{
+			Id:           category.Id,
+			Name:         category.Name,
+			Path:         tree.path(category.Id),
+			ProductCount: category.ProductCount,
+			Children:     tree.branch(category.Id),
+		})
+	}
+	return result
+}
+
+func categoryPathList(categories []*Category, categoryIds []int) []string {
+	/*
+		To take all categories and ids of some of them and return the paths of those, in order
+	*/
+	tree := newCategoryTree(categories)
+	paths := make([]string, 0, len(categoryIds))
+	for _, categoryId := range categoryIds {
+		paths = append(paths, tree.path(categoryId))
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func categoryParentValue(parentId int) string {
+	/*
+		To give the parent_id of a category as sql value, NULL for a root category
+	*/
+	if parentId == 0 {
+		return "NULL"
+	}
+	return strconv.Itoa(parentId)
+}
+
+func SplitCategoryPath(path string) ([]string, bool) {
+	/*
+		To split the path of a category into the normalized names of its parents and itself,
+		e.g. "Non-Dairy > Frozen  Dessert" into ["Non-Dairy", "Frozen Dessert"]
+		Return: valid: false, if a name is empty or the path is deeper than categories can be nested
+	*/
+	names := strings.Split(path, constants.CategoryPathSeparatorChar)
+	if len(names) > constants.CategoryMaxDepth {
+		return nil, false
+	}
+	for index, name := range names {
+		names[index] = normalizer.Name(name)
+		if names[index] == "" {
+			return nil, false
+		}
+	}
+	return names, true
+}
+
+func ValidCategoryName(name string) bool {
+	/*
+		To check that a name of a category isn't empty and has no '>', which separates names in paths
+	*/
+	return normalizer.Name(name) != "" && !strings.Contains(name, constants.CategoryPathSeparatorChar)
+}
+
+func ValidateCategories(ctx context.Context, paths []string) (bool, bool) {
+	/*
+		To check that every path is of an existing category, names are matched by their normalized key
+		Return: valid: false, if a category doesn't exist; success: false, if an error occurs
+	*/
+	if len(paths) == 0 {
+		return true, true
+	}
+	categories, success := SelectCategories(ctx)
+	if !success {
+		return false, false
+	}
+	tree := newCategoryTree(categories)
+	for _, path := range paths {
+		if _, exists := tree.resolve(path); !exists {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+func ValidateTags(tags []string) bool {
+	/*
+		To check that every tag has a name
+	*/
+	for _, tag := range tags {
+		if normalizer.Name(tag) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func insertCategoryPaths(ctx context.Context, txn *sql.Tx, paths []string) ([]int, bool) {
+	/*
+		To take paths of categories and select their ids inside a transaction, inserting the categories missing
+		along the paths, e.g. for products uploaded in bulk or rolled back to a category deleted since
+		Return: unique ids of the categories, in order of the paths
+	*/
+	categoryIds := make([]int, 0, len(paths))
+	if len(paths) == 0 {
+		return categoryIds, true
+	}
+	categories, success := SelectFromCategory(ctx, txn)
+	if !success {
+		return nil, false
+	}
+	tree := newCategoryTree(categories)
+	seenIdMap := make(map[int]bool)
+	for _, path := range paths {
+		names, valid := SplitCategoryPath(path)
+		if !valid {
+			return nil, false
+		}
+		parentId := 0
+		for _, name := range names {
+			category := tree.child(parentId, name)
+			if category == nil {
+				id, success := InsertIntoCategory(ctx, txn, parentId, name)
+				if !success {
+					return nil, false
+				}
+				category = &Category{Id: id, ParentId: parentId, Name: name, NameKey: normalizer.Key(name)}
+				tree.add(category)
+			}
+			parentId = category.Id
+		}
+		if !seenIdMap[parentId] {
+			seenIdMap[parentId] = true
+			categoryIds = append(categoryIds, parentId)
+		}
+	}
+	return categoryIds, true
+}
+
+func SelectCategoryTree(ctx context.Context) ([]*structs.CategoryStruct, bool) {
+	/*
+		To select all categories as a tree of root categories with their subcategories, in order of name
+	*/
+	categories, success := SelectCategories(ctx)
+	if !success {
+		return nil, false
+	}
+	return newCategoryTree(categories).branch(0), true
+}
+
+func FindProducts(ctx context.Context, categoryId int, tag string) ([]*Product, bool, bool) {
+	/*
+		To take id of a category and/or a tag name and select active products in the category or any of its
+		subcategories and carrying the tag, 0 or an empty tag doesn't filter by it
+		Return: found: false, if the category doesn't exist; success: false, if an error occurs
+	*/
+	var categoryIds []int
+	if categoryId != 0 {
+		categories, success := SelectCategories(ctx)
+		if !success {
+			return nil, true, false
+		}
+		tree := newCategoryTree(categories)
+		if tree.byId[categoryId] == nil {
+			return nil, false, true
+		}
+		categoryIds = tree.descendants(categoryId)
+	}
+	products, success := SelectFromProductByCategoryAndTag(ctx, categoryIds, normalizer.Name(tag))
+	return products, true, success
+}
+
+func FindFacets(ctx context.Context, filter *FacetFilter) (int, map[string][]*PropertyUsage, bool, bool) {
+	/*
+		To take filters of products and count live products matching all of them, in total and per entry of
+		each of the FacetVocabularies, entries not used by any matching product are left out
+		Return: found: false, if the category doesn't exist; success: false, if an error occurs
+	*/
+	var categoryIds []int
+	if filter.CategoryId != 0 {
+		categories, success := SelectCategories(ctx)
+		if !success {
+			return 0, nil, true, false
+		}
+		tree := newCategoryTree(categories)
+		if tree.byId[filter.CategoryId] == nil {
+			return 0, nil, false, true
+		}
+		categoryIds = tree.descendants(filter.CategoryId)
+	}
+	condition := liveProductCondition(time.Now()) + facetFilterCondition(filter, categoryIds)
+	total, success := SelectProductCountByCondition(ctx, condition)
+	if !success {
+		return 0, nil, true, false
+	}
+	facets := make(map[string][]*PropertyUsage)
+	for _, field := range FacetVocabularies {
+		if facets[field], success = SelectFacetCounts(ctx, Vocabularies[field], condition); !success {
+			return 0, nil, true, false
+		}
+	}
+	return total, facets, true, true
+}
+
+func facetFilterCondition(filter *FacetFilter, categoryIds []int) string {
+	/*
+		To build the conditions, each starting with AND, of products matching every filter
+		Entries of vocabularies are matched by their normalized key, and allergens as by the free-from api,
+		i.e. products must have declared their allergens without any level of it
+	*/
+	condition := categoryAndTagCondition(categoryIds, normalizer.Name(filter.Tag))
+	fields := make([]string, 0, len(filter.Properties))
+	for field := range filter.Properties {
+		fields = append(fields, field)
+	}
+	// Sorting fields, so that the same filters always build the same query
+	sort.Strings(fields)
+	for _, field := range fields {
+		vocabulary := Vocabularies[field]
+		if vocabulary == nil {
+			continue
+		}
+		for _, name := range filter.Properties[field] {
+			condition += " AND EXISTS (SELECT 1 FROM " + vocabulary.RelationTable + " INNER JOIN " +
+				vocabulary.Table + " ON " + vocabulary.RelationTable + "." + vocabulary.Column + " = " +
+				vocabulary.Table + ".id WHERE " + vocabulary.RelationTable + ".product_id = product.id" +
+				" AND " + vocabulary.Table + ".name_key = '" +
+				strings.Replace(normalizer.Key(name), "'", "''", -1) + "')"
+		}
+	}
+	if len(filter.FreeFrom) != 0 {
+		codes := make([]string, 0, len(filter.FreeFrom))
+		for _, code := range filter.FreeFrom {
+			codes = append(codes, "'"+strings.Replace(code, "'", "''", -1)+"'")
+		}
+		sort.Strings(codes)
+		condition += " AND allergens_declared = 1" +
+			" AND NOT EXISTS (SELECT 1 FROM product_allergen INNER JOIN allergen" +
+			" ON product_allergen.allergen_id = allergen.id WHERE product_allergen.product_id = product.id" +
+			" AND allergen.code IN (" + strings.Join(codes, ", ") + "))"
+	}
+	return condition
+}
+
+func CreateCategory(ctx context.Context, parentId int, name string) (int, CategoryResult) {
+	/*
+		To take id of the parent category (0 for a root category) and a (valid) name
+		and insert it as a new category using an atomic transaction
+		The parent must exist, the name must not be taken among its children and categories are nested
+		at most constants.CategoryMaxDepth levels deep
+		Return: id of the inserted category, 0 if it isn't inserted
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return 0, CategoryError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+	categories, success := SelectFromCategory(txnCtx, mySqlTxn)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return 0, CategoryError
+	}
+	tree := newCategoryTree(categories)
+	if (parentId != 0 && tree.byId[parentId] == nil) || tree.depth(parentId) >= constants.CategoryMaxDepth {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return 0, CategoryInvalid
+	}
+	if tree.child(parentId, name) != nil {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return 0, CategoryNameExists
+	}
+	id, success := InsertIntoCategory(txnCtx, mySqlTxn, parentId, name)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return 0, CategoryError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory) {
+		return 0, CategoryError
+	}
+	return id, CategoryChanged
+}
+
+func UpdateCategory(ctx context.Context, id int, parentId int, name string) CategoryResult {
+	/*
+		To take a category, id of its (new) parent (0 for the root) and a (valid) name and rename and/or move it
+		using an atomic transaction
+		Products refer to the category by id, so paths of all products in it or its subcategories change,
+		a new revision is stored for each
+		Moving a category into itself or one of its subcategories, or deeper than categories can be nested,
+		is refused
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return CategoryError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+	categories, success := SelectFromCategory(txnCtx, mySqlTxn)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return CategoryError
+	}
+	tree := newCategoryTree(categories)
+	if tree.byId[id] == nil {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return CategoryNotFound
+	}
+	categoryIds := tree.descendants(id)
+	for _, categoryId := range categoryIds {
+		if categoryId == parentId {
+			rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+			return CategoryInvalid
+		}
+	}
+	if (parentId != 0 && tree.byId[parentId] == nil) ||
+		tree.depth(parentId)+tree.height(id) > constants.CategoryMaxDepth {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return CategoryInvalid
+	}
+	if existing := tree.child(parentId, name); existing != nil && existing.Id != id {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return CategoryNameExists
+	}
+	productIdPKs, success := SelectProductIdPKByCategoryIds(txnCtx, mySqlTxn, categoryIds)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return CategoryError
+	}
+	success = UpdateCategoryById(txnCtx, mySqlTxn, id, parentId, name)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return CategoryError
+	}
+	// Storing a snapshot of every product with a changed category path as its next revision
+	success = insertRevisions(txnCtx, mySqlTxn, productIdPKs)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return CategoryError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory) {
+		return CategoryError
+	}
+	return CategoryChanged
+}
+
+func DeleteCategory(ctx context.Context, id int) CategoryResult {
+	/*
+		To take a category and delete it using an atomic transaction
+		Deleting a category with subcategories or products (even inactive ones) is refused
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return CategoryError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+	categories, success := SelectFromCategory(txnCtx, mySqlTxn)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return CategoryError
+	}
+	tree := newCategoryTree(categories)
+	category := tree.byId[id]
+	if category == nil || len(tree.children[id]) > 0 || category.ProductCount > 0 {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		if category == nil {
+			return CategoryNotFound
+		}
+		return CategoryInUse
+	}
+	// Foreign keys refuse deleting the category, if a product has been added to it in the meantime
+	success = DeleteFromCategoryById(txnCtx, mySqlTxn, id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory)
+		return CategoryError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionCategory) {
+		return CategoryError
+	}
+	return CategoryChanged
+}
+
+func ValidateSchedule(iceCreamData *structs.IceCreamDataStruct) bool {
+	/*
+		To check that publish_at and unpublish_at of a product are empty or times as 2006-01-02 15:04:05 (UTC)
+		and that unpublish_at is after publish_at, if both are given
+		Times are stored as formatted by ScheduleTimeLayout
+	*/
+	times := []*string{&iceCreamData.PublishAt, &iceCreamData.UnpublishAt}
+	for _, value := range times {
+		*value = strings.TrimSpace(*value)
+		if *value == "" {
+			continue
+		}
+		at, err := time.Parse(constants.ScheduleTimeLayout, *value)
+		if err != nil {
+			return false
+		}
+		*value = at.Format(constants.ScheduleTimeLayout)
+	}
+	// times formatted alike compare in order as strings
+	return iceCreamData.PublishAt == "" || iceCreamData.UnpublishAt == "" ||
+		iceCreamData.PublishAt < iceCreamData.UnpublishAt
+}
+
+func EmitScheduleEvents(ctx context.Context, at time.Time,
+	emit func(*structs.ScheduleEventStruct) error) ([]*structs.ScheduleEventStruct, bool) {
+	/*
+		To record events of products that went live or expired at or before a time using an atomic transaction,
+		then emit every recorded event not emitted yet, in order of scheduled time, and mark it emitted
+		Events are emitted outside the transaction, so a slow receiver doesn't hold locks on products
+		Emitting stops at the first error, the event is emitted again by the next call (at least once)
+		Return: events emitted by this call
+	*/
+	emitted := make([]*structs.ScheduleEventStruct, 0)
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return emitted, false
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionSchedule)
+	scheduleEvents, success := SelectDueScheduleEvents(txnCtx, mySqlTxn, at)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionSchedule)
+		return emitted, false
+	}
+	success = InsertIntoProductScheduleEvent(txnCtx, mySqlTxn, scheduleEvents)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionSchedule)
+		return emitted, false
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionSchedule) {
+		return emitted, false
+	}
+
+	scheduleEvents, success = SelectPendingScheduleEvents(ctx)
+	if !success {
+		return emitted, false
+	}
+	emittedAt := at.UTC().Format(constants.ScheduleTimeLayout)
+	for _, scheduleEvent := range scheduleEvents {
+		event := &structs.ScheduleEventStruct{
+			Event:       scheduleEvent.Event,
+			ProductId:   scheduleEvent.ProductId,
+			Name:        scheduleEvent.Name,
+			ScheduledAt: scheduleEvent.ScheduledAt,
+			EmittedAt:   emittedAt,
+		}
+		if err := emit(event); err != nil {
+			logger.FromContext(ctx).Error(constants.SchedulerLogBucketName, logIdentifier+"EmitScheduleEvents",
+				constants.SchedulerPublishErrorMessage, event.Event+" "+event.ProductId+": "+err.Error())
+			return emitted, false
+		}
+		emitted = append(emitted, event)
+		if !UpdateProductScheduleEventEmitted(ctx, scheduleEvent, emittedAt) {
+			return emitted, false
+		}
+	}
+	return emitted, true
+}
+
+func ValidateFields(fieldMap map[string]bool) bool {
+	/*
+		To check that the fields of an update request name at least one field of an ice cream product
+	*/
+	for _, field := range allFields {
+		if _, exists := fieldMap[field]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+func draftFieldMap(fields string) map[string]bool {
+	/*
+		To take names of fields separated by commas, as stored in a draft, and keep those of an ice cream product
+	*/
+	fieldMap := make(map[string]bool)
+	for _, field := range strings.Split(fields, ",") {
+		for _, knownField := range allFields {
+			if field == knownField {
+				fieldMap[field] = true
+			}
+		}
+	}
+	return fieldMap
+}
+
+func draftFields(fieldMap map[string]bool) string {
+	/*
+		To take fields of an ice cream product and join their names by commas, in the order of allFields
+	*/
+	fields := make([]string, 0, len(fieldMap))
+	for _, field := range allFields {
+		if _, exists := fieldMap[field]; exists {
+			fields = append(fields, field)
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+func applyFields(iceCreamData *structs.IceCreamDataStruct, edited *structs.IceCreamDataStruct,
+	fieldMap map[string]bool) {
+	/*
+		To take ice cream data and copy the fields into it from edited ice cream data, e.g. a draft
+	*/
+	for field := range fieldMap {
+		switch field {
+		case "name":
+			iceCreamData.Name = edited.Name
+		case "description":
+			iceCreamData.Description = edited.Description
+		case "story":
+			iceCreamData.Story = edited.Story
+		case "image_closed":
+			iceCreamData.ImageClosed = edited.ImageClosed
+		case "image_open":
+			iceCreamData.ImageOpened = edited.ImageOpened
+		case "allergy_info":
+			iceCreamData.AllergyInfo = edited.AllergyInfo
+		case "dietary_certifications":
+			iceCreamData.DietaryCertifications = edited.DietaryCertifications
+		case "sourcing_values":
+			iceCreamData.SourcingValues = edited.SourcingValues
+		case "ingredients":
+			iceCreamData.Ingredients = edited.Ingredients
+		case "allergens":
+			iceCreamData.Allergens = edited.Allergens
+		case "nutrition":
+			iceCreamData.Nutrition = edited.Nutrition
+		case "categories":
+			iceCreamData.Categories = edited.Categories
+		case "tags":
+			iceCreamData.Tags = edited.Tags
+		case "publish_at":
+			iceCreamData.PublishAt = edited.PublishAt
+		case "unpublish_at":
+			iceCreamData.UnpublishAt = edited.UnpublishAt
+		}
+	}
+}
+
+func selectDraft(ctx context.Context, txn *sql.Tx, id int) (*Draft, *structs.IceCreamDataStruct, bool) {
+	/*
+		To take an id and select the draft of the product inside a transaction, with its edited ice cream data
+		success: true, draft: nil, if the product has no draft
+	*/
+	funcName := "selectDraft"
+	draft, success := SelectFromProductDraftByProductIdPK(ctx, txn, id)
+	if !success || draft == nil {
+		return nil, nil, success
+	}
+	var iceCreamData *structs.IceCreamDataStruct
+	umMarshalErr := json.Unmarshal([]byte(draft.Data), &iceCreamData)
+	if umMarshalErr != nil || iceCreamData == nil {
+		if umMarshalErr != nil {
+			logger.FromContext(ctx).Error(constants.BenNJerryLogBucketName, logIdentifier+funcName,
+				constants.UnMarshalErrorString, umMarshalErr.Error())
+		}
+		return nil, nil, false
+	}
+	return draft, iceCreamData, true
+}
+
+func SaveDraft(ctx context.Context, id int, iceCreamData *structs.IceCreamDataStruct,
+	fieldMap map[string]bool) bool {
+	/*
+		To take an id and ice cream data and save data of the fields in the draft of the product using an atomic
+		transaction, instead of updating the product itself
+		Fields are added to those edited in the draft before, a draft submitted or approved goes back to draft,
+		as its edits have to be reviewed again
+	*/
+	funcName := "SaveDraft"
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return false
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+	draft, draftData, success := selectDraft(txnCtx, mySqlTxn, id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+		return false
+	}
+	if draft == nil {
+		draft, draftData = &Draft{ProductId: id}, &structs.IceCreamDataStruct{}
+	}
+	// Fields edited before and now, unknown fields of the request are left out
+	editedFields := draftFieldMap(draft.Fields + "," + draftFields(fieldMap))
+	applyFields(draftData, iceCreamData, fieldMap)
+	data, marshalErr := json.Marshal(draftData)
+	if marshalErr != nil {
+		logger.FromContext(ctx).Error(constants.BenNJerryLogBucketName, logIdentifier+funcName,
+			constants.JsonSerializationErrorMessage, marshalErr.Error())
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+		return false
+	}
+	draft.Data, draft.Fields, draft.Status = string(data), draftFields(editedFields), constants.DraftStatusDraft
+	success = InsertIntoProductDraft(txnCtx, mySqlTxn, draft)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+		return false
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft) {
+		return false
+	}
+	logger.FromContext(ctx).Info(constants.BenNJerryLogBucketName, logIdentifier+funcName,
+		"Saved draft of record with id "+strconv.Itoa(id))
+	return true
+}
+
+func PreviewRecord(ctx context.Context, productId string) (*structs.IceCreamDataStruct, string, bool) {
+	/*
+		To take product_id and collect complete information of the product with the edits of its draft applied,
+		whether the product is live or not
+		Return: ice cream data, nil if the product doesn't exist; status of the draft, empty if it has none
+	*/
+	id, success := SelectIdFromProductByProductId(ctx, productId)
+	if !success || id == 0 {
+		return nil, "", success
+	}
+	// Reading the product and its draft in a transaction, so that a draft being published isn't applied twice
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return nil, "", false
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+	iceCreamData, success := SelectSnapshotById(txnCtx, mySqlTxn, id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+		return nil, "", false
+	}
+	draft, draftData, success := selectDraft(txnCtx, mySqlTxn, id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+		return nil, "", false
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft) {
+		return nil, "", false
+	}
+	if draft == nil {
+		return iceCreamData, "", true
+	}
+	applyFields(iceCreamData, draftData, draftFieldMap(draft.Fields))
+	return iceCreamData, draft.Status, true
+}
+
+func ChangeDraftStatus(ctx context.Context, id int, from string, to string) DraftResult {
+	/*
+		To take an id and move the draft of the product from a status to another using an atomic transaction,
+		e.g. from in_review to approved
+		Changing a draft in another status than the one expected is refused
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return DraftError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+	draft, success := SelectFromProductDraftByProductIdPK(txnCtx, mySqlTxn, id)
+	if !success || draft == nil || draft.Status != from {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+		if !success {
+			return DraftError
+		} else if draft == nil {
+			return DraftNotFound
+		}
+		return DraftInvalidStatus
+	}
+	success = UpdateProductDraftStatusByProductIdPK(txnCtx, mySqlTxn, id, to)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+		return DraftError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft) {
+		return DraftError
+	}
+	logger.FromContext(ctx).Info(constants.BenNJerryLogBucketName, logIdentifier+"ChangeDraftStatus",
+		"Changed draft of record with id "+strconv.Itoa(id)+" from "+from+" to "+to)
+	return DraftChanged
+}
+
+func PublishDraft(ctx context.Context, id int) DraftResult {
+	/*
+		To take an id and apply the edits of the approved draft of the product to the product using an atomic
+		transaction, storing the product as its next revision and deleting the draft
+		Publishing a draft which hasn't been approved is refused
+	*/
+	txnCtx, mySqlTxn, mySqlTxnErr := beginTransaction(ctx)
+	if mySqlTxnErr != nil {
+		return DraftError
+	}
+	defer rollbackOnPanic(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+	draft, draftData, success := selectDraft(txnCtx, mySqlTxn, id)
+	if !success || draft == nil || draft.Status != constants.DraftStatusApproved {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+		if !success {
+			return DraftError
+		} else if draft == nil {
+			return DraftNotFound
+		}
+		return DraftInvalidStatus
+	}
+	// Only the fields edited in the draft are applied, other fields keep their published values
+	success = updateRecord(txnCtx, mySqlTxn, id, draftData, draftFieldMap(draft.Fields))
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+		return DraftError
+	}
+	success = DeleteFromProductDraftByProductIdPK(txnCtx, mySqlTxn, id)
+	if !success {
+		rollbackTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft)
+		return DraftError
+	}
+	if !commitTransaction(txnCtx, mySqlTxn, constants.MySQLTransactionDraft) {
+		return DraftError
+	}
+	logger.FromContext(ctx).Info(constants.BenNJerryLogBucketName, logIdentifier+"PublishDraft",
+		"Published draft of record with id "+strconv.Itoa(id))
+	return DraftChanged
+}
+
+func DiscardDraft(ctx context.Context, id int) DraftResult {
+	/*
+		To take an id and delete the draft of the product whatever its status, the product itself is left as it is
+	*/
+	found, success := DeleteFromProductDraft(ctx, id)
+	if !success {
+		return DraftError
+	} else if !found {
+		return DraftNotFound
+	}
+	return DraftChanged
+}
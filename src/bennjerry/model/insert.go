@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"database/sql"
 	"strconv"
 	"strings"
@@ -10,9 +11,12 @@ import (
 	"bennjerry/structs"
 	"constants"
 	"logger"
+	"metrics"
+	"mysqlc"
+	"normalizer"
 )
 
-func InsertIntoProduct(txn *sql.Tx, iceCreamData *structs.IceCreamDataStruct) (int, bool) {
+func InsertIntoProduct(ctx context.Context, txn *sql.Tx, iceCreamData *structs.IceCreamDataStruct) (int, bool) {
 	/*
 		To take ice cream data and insert it into product table
 	*/
@@ -20,11 +24,12 @@ func InsertIntoProduct(txn *sql.Tx, iceCreamData *structs.IceCreamDataStruct) (i
 	if iceCreamData == nil {
 		return 0, false
 	}
-	query := "INSERT INTO product (product_id, name, description, story, image_closed, image_opened, allergy"
-	if iceCreamData.DietaryCertifications != "" {
-		query += ", dietary_certification_id"
+	allergensDeclared := "0"
+	if iceCreamData.Allergens != nil {
+		allergensDeclared = "1"
 	}
-	query += ")"
+	query := "INSERT INTO product (product_id, name, description, story, image_closed, image_opened, allergy," +
+		" allergens_declared, publish_at, unpublish_at)"
 	query += " VALUES ('" + iceCreamData.ProductId + "'"
 	query += ", '" + strings.Replace(iceCreamData.Name, "'", "''", -1) + "'"
 	query += ", '" + strings.Replace(iceCreamData.Description, "'", "''", -1) + "'"
@@ -32,15 +37,16 @@ func InsertIntoProduct(txn *sql.Tx, iceCreamData *structs.IceCreamDataStruct) (i
 	query += ", '" + strings.Replace(iceCreamData.ImageClosed, "'", "''", -1) + "'"
 	query += ", '" + strings.Replace(iceCreamData.ImageOpened, "'", "''", -1) + "'"
 	query += ", '" + strings.Replace(iceCreamData.AllergyInfo, "'", "''", -1) + "'"
-	if iceCreamData.DietaryCertifications != "" {
-		dietaryCertificationId := SelectFromDietaryCertification(txn, []string{iceCreamData.DietaryCertifications})
-		query += ", '" + strconv.Itoa(dietaryCertificationId[0].Id) + "'"
-	}
-	query += ")"
-	insert, err := txn.Exec(query)
+	query += ", " + allergensDeclared
+	query += ", NULLIF('" + strings.Replace(iceCreamData.PublishAt, "'", "''", -1) + "', '')"
+	query += ", NULLIF('" + strings.Replace(iceCreamData.UnpublishAt, "'", "''", -1) + "', ''))"
+	queryCtx, cancel := queryContext(ctx)
+	insert, err := txn.ExecContext(queryCtx, query)
+	cancel()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 	} else {
 		id, _ := insert.LastInsertId()
 		return int(id), true
@@ -48,61 +54,80 @@ func InsertIntoProduct(txn *sql.Tx, iceCreamData *structs.IceCreamDataStruct) (i
 	return 0, false
 }
 
-func InsertIntoSourcingValue(txn *sql.Tx, nameMap map[string]bool) bool {
+func InsertIntoSourcingValue(ctx context.Context, txn *sql.Tx, nameMap map[string]bool) bool {
 	/*
 		To take map {name: true}, and insert into sourcingvalue, if it doesn't exist already
 	*/
-	funcName := "InsertIntoSourcingValue"
-	for name := range nameMap {
-		query := "INSERT IGNORE INTO sourcingvalue (name)" +
-			" VALUES ('" + strings.Replace(name, "'", "''", -1) + "')"
-		_, err := txn.Exec(query)
-		if err != nil {
-			logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
-				constants.MySQLQueryRunErrorMessage, err.Error())
-			return false
-		}
-	}
-	return true
+	return insertIntoVocabularyByNames(ctx, txn, "InsertIntoSourcingValue", "sourcingvalue", nameMap)
 }
 
-func InsertIntoIngredient(txn *sql.Tx, nameMap map[string]bool) bool {
+func InsertIntoIngredient(ctx context.Context, txn *sql.Tx, nameMap map[string]bool) bool {
 	/*
 		To take map {name: true} and insert into ingredient, if it doesn't exist already
 	*/
-	funcName := "InsertIntoIngredient"
-	for name := range nameMap {
-		query := "INSERT IGNORE INTO ingredient (name)" +
-			" VALUES ('" + strings.Replace(name, "'", "''", -1) + "')"
-		_, err := txn.Exec(query)
-		if err != nil {
-			logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
-				constants.MySQLQueryRunErrorMessage, err.Error())
-			return false
-		}
-	}
-	return true
+	return insertIntoVocabularyByNames(ctx, txn, "InsertIntoIngredient", "ingredient", nameMap)
 }
 
-func InsertIntoDietaryCertification(txn *sql.Tx, nameMap map[string]bool) bool {
+func InsertIntoDietaryCertification(ctx context.Context, txn *sql.Tx, nameMap map[string]bool) bool {
 	/*
 		To take map {name: true} and insert into dietarycertification, if it doesn't exist already
 	*/
-	funcName := "InsertIntoDietaryCertification"
+	return insertIntoVocabularyByNames(ctx, txn, "InsertIntoDietaryCertification", "dietarycertification", nameMap)
+}
+
+func InsertIntoTag(ctx context.Context, txn *sql.Tx, nameMap map[string]bool) bool {
+	/*
+		To take map {name: true} and insert into tag, if it doesn't exist already
+	*/
+	return insertIntoVocabularyByNames(ctx, txn, "InsertIntoTag", "tag", nameMap)
+}
+
+func insertIntoVocabularyByNames(ctx context.Context, txn *sql.Tx, funcName string, table string,
+	nameMap map[string]bool) bool {
+	/*
+		To take map {name: true} and insert the normalized names into a vocabulary table
+		A name is skipped by the unique key, if an entry with the same normalized key exists already,
+		e.g. "milk " when "Milk" exists
+	*/
 	for name := range nameMap {
-		query := "INSERT IGNORE INTO dietarycertification (name)" +
-			" VALUES ('" + strings.Replace(name, "'", "''", -1) + "')"
-		_, err := txn.Exec(query)
+		query := "INSERT IGNORE INTO " + table + " (name, name_key)" +
+			" VALUES ('" + strings.Replace(normalizer.Name(name), "'", "''", -1) + "'" +
+			", '" + strings.Replace(normalizer.Key(name), "'", "''", -1) + "')"
+		queryCtx, cancel := queryContext(ctx)
+		_, err := txn.ExecContext(queryCtx, query)
+		cancel()
 		if err != nil {
-			logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 				constants.MySQLQueryRunErrorMessage, err.Error())
+			metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 			return false
 		}
 	}
 	return true
 }
 
-func InsertToProductSourcingValueById(txn *sql.Tx, productIdPk int, sourcingValueId int) bool {
+func InsertIntoVocabulary(ctx context.Context, txn *sql.Tx, vocabulary *Vocabulary, name string) (int, bool) {
+	/*
+		To take a vocabulary and name and insert it normalized as a new entry, its key must not exist already
+	*/
+	funcName := "InsertIntoVocabulary"
+	query := "INSERT INTO " + vocabulary.Table + " (name, name_key)" +
+		" VALUES ('" + strings.Replace(normalizer.Name(name), "'", "''", -1) + "'" +
+		", '" + strings.Replace(normalizer.Key(name), "'", "''", -1) + "')"
+	queryCtx, cancel := queryContext(ctx)
+	insert, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return 0, false
+	}
+	id, _ := insert.LastInsertId()
+	return int(id), true
+}
+
+func InsertToProductSourcingValueById(ctx context.Context, txn *sql.Tx, productIdPk int, sourcingValueId int) bool {
 	/*
 		To take product_id (primary key of product table) and sourcingvalue_id
 		and insert into product_sourcingvalue table
@@ -110,22 +135,25 @@ func InsertToProductSourcingValueById(txn *sql.Tx, productIdPk int, sourcingValu
 	funcName := "InsertToProductSourcingValueById"
 	query := "INSERT INTO product_sourcingvalue (product_id, sourcingvalue_id)" +
 		" VALUES (" + strconv.Itoa(productIdPk) + ", " + strconv.Itoa(sourcingValueId) + ")"
-	_, err := txn.Exec(query)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 		return false
 	}
 	return true
 }
 
-func InsertIntoProductSourcingValue(txn *sql.Tx, productIdPK int, sourcingValues []string) bool {
+func InsertIntoProductSourcingValue(ctx context.Context, txn *sql.Tx, productIdPK int, sourcingValues []string) bool {
 	/*
 		To take a list of sourcing values and insert into product_sourcingvalue table
 	*/
-	sourcingValueDataFromDB := SelectFromSourcingValue(txn, sourcingValues)
+	sourcingValueDataFromDB := SelectFromSourcingValue(ctx, txn, sourcingValues)
 	for _, data := range sourcingValueDataFromDB {
-		success := InsertToProductSourcingValueById(txn, productIdPK, data.Id)
+		success := InsertToProductSourcingValueById(ctx, txn, productIdPK, data.Id)
 		if !success {
 			return false
 		}
@@ -133,32 +161,529 @@ func InsertIntoProductSourcingValue(txn *sql.Tx, productIdPK int, sourcingValues
 	return true
 }
 
-func InsertToProductIngredientById(txn *sql.Tx, productIdPK int, ingredientId int) bool {
+func InsertToProductIngredientById(ctx context.Context, txn *sql.Tx, productIdPK int, ingredientId int,
+	position int, percentage *float64) bool {
 	/*
-		To take product_id (primary key of product table) and ingredient_id and insert into product_ingredient table
+		To take product_id (primary key of product table), ingredient_id, position of the ingredient on the label
+		and its percentage (nil, if not declared) and insert into product_ingredient table
 	*/
 	funcName := "InsertToProductIngredientById"
-	query := "INSERT INTO product_ingredient (product_id, ingredient_id)" +
-		" VALUES (" + strconv.Itoa(productIdPK) + ", " + strconv.Itoa(ingredientId) + ")"
-	_, err := txn.Exec(query)
+	percentageValue := "NULL"
+	if percentage != nil {
+		percentageValue = strconv.FormatFloat(*percentage, 'f', 2, 64)
+	}
+	query := "INSERT INTO product_ingredient (product_id, ingredient_id, position, percentage)" +
+		" VALUES (" + strconv.Itoa(productIdPK) + ", " + strconv.Itoa(ingredientId) + ", " + strconv.Itoa(position) +
+		", " + percentageValue + ")"
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func InsertIntoProductIngredient(ctx context.Context, txn *sql.Tx, productId int,
+	ingredients structs.IngredientList) bool {
+	/*
+		To take a list of ingredients and insert into product_ingredient table, numbering them in order from 1
+		An ingredient listed again is skipped, keeping where it was first listed
+	*/
+	usedIngredientIdMap := make(map[int]bool)
+	position := 0
+	for _, ingredient := range ingredients {
+		// Selecting one name at a time, names are matched as per collation of the table (e.g. ignoring case)
+		for _, data := range SelectFromIngredient(ctx, txn, []string{ingredient.Name}) {
+			if usedIngredientIdMap[data.Id] {
+				continue
+			}
+			usedIngredientIdMap[data.Id] = true
+			position++
+			success := InsertToProductIngredientById(ctx, txn, productId, data.Id, position, ingredient.Percentage)
+			if !success {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func InsertToProductDietaryCertificationById(ctx context.Context, txn *sql.Tx, productIdPK int,
+	dietaryCertificationId int) bool {
+	/*
+		To take product_id (primary key of product table) and dietarycertification_id
+		and insert into product_dietarycertification table
+	*/
+	funcName := "InsertToProductDietaryCertificationById"
+	query := "INSERT INTO product_dietarycertification (product_id, dietarycertification_id)" +
+		" VALUES (" + strconv.Itoa(productIdPK) + ", " + strconv.Itoa(dietaryCertificationId) + ")"
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 		return false
 	}
 	return true
 }
 
-func InsertIntoProductIngredient(txn *sql.Tx, productId int, ingredients []string) bool {
+func InsertIntoProductDietaryCertification(ctx context.Context, txn *sql.Tx, productIdPK int,
+	dietaryCertifications []string) bool {
 	/*
-		To take a list of ingredients and insert into product_ingredient table
+		To take a list of dietary certifications and insert into product_dietarycertification table
 	*/
-	IngredientDataFromDB := SelectFromIngredient(txn, ingredients)
-	for _, data := range IngredientDataFromDB {
-		success := InsertToProductIngredientById(txn, productId, data.Id)
+	for _, data := range SelectFromDietaryCertification(ctx, txn, dietaryCertifications) {
+		success := InsertToProductDietaryCertificationById(ctx, txn, productIdPK, data.Id)
 		if !success {
 			return false
 		}
 	}
 	return true
 }
+
+func InsertToProductTagById(ctx context.Context, txn *sql.Tx, productIdPK int, tagId int) bool {
+	/*
+		To take product_id (primary key of product table) and tag_id and insert into product_tag table
+	*/
+	funcName := "InsertToProductTagById"
+	query := "INSERT INTO product_tag (product_id, tag_id)" +
+		" VALUES (" + strconv.Itoa(productIdPK) + ", " + strconv.Itoa(tagId) + ")"
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func InsertIntoProductTag(ctx context.Context, txn *sql.Tx, productIdPK int, tags []string) bool {
+	/*
+		To take a list of tags and insert into product_tag table
+	*/
+	for _, data := range SelectFromTag(ctx, txn, tags) {
+		success := InsertToProductTagById(ctx, txn, productIdPK, data.Id)
+		if !success {
+			return false
+		}
+	}
+	return true
+}
+
+func InsertToProductAllergenById(ctx context.Context, txn *sql.Tx, productIdPK int, allergenId int,
+	level string) bool {
+	/*
+		To take product_id (primary key of product table), allergen_id and level
+		and insert into product_allergen table
+	*/
+	funcName := "InsertToProductAllergenById"
+	query := "INSERT INTO product_allergen (product_id, allergen_id, level)" +
+		" VALUES (" + strconv.Itoa(productIdPK) + ", " + strconv.Itoa(allergenId) +
+		", '" + strings.Replace(level, "'", "''", -1) + "')"
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func InsertIntoProductAllergen(ctx context.Context, txn *sql.Tx, productIdPK int,
+	allergens []*structs.AllergenStruct) bool {
+	/*
+		To take a list of allergens (code and level) and insert into product_allergen table
+		Allergens are not created on the fly, so an unknown code fails the insert
+	*/
+	funcName := "InsertIntoProductAllergen"
+	codeList := make([]string, 0, len(allergens))
+	for _, allergen := range allergens {
+		codeList = append(codeList, allergen.Code)
+	}
+	allergenIdMap := make(map[string]int)
+	for _, data := range SelectFromAllergenByCode(ctx, txn, codeList) {
+		allergenIdMap[data.Code] = data.Id
+	}
+	for _, allergen := range allergens {
+		allergenId, exists := allergenIdMap[allergen.Code]
+		if !exists {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.InvalidAllergenErrorMessage, allergen.Code)
+			return false
+		}
+		success := InsertToProductAllergenById(ctx, txn, productIdPK, allergenId, allergen.Level)
+		if !success {
+			return false
+		}
+	}
+	return true
+}
+
+func InsertIntoProductRevision(ctx context.Context, txn *sql.Tx, productIdPK int, snapshot []byte) (int, bool) {
+	/*
+		To take product_id (primary key of product table) and json snapshot of the product
+		and insert it into product_revision table as the next revision of that product
+		The product row is locked first, so that concurrent transactions don't take the same revision number
+	*/
+	funcName := "InsertIntoProductRevision"
+	if _, success := SelectIdFromProductByIdForUpdate(ctx, txn, productIdPK); !success {
+		return 0, false
+	}
+	revision, success := SelectMaxRevisionFromProductRevisionByProductIdPK(ctx, txn, productIdPK)
+	if !success {
+		return 0, false
+	}
+	revision++
+	query := "INSERT INTO product_revision (product_id, revision, snapshot)" +
+		" VALUES (" + strconv.Itoa(productIdPK) + ", " + strconv.Itoa(revision) +
+		", '" + strings.Replace(string(snapshot), "'", "''", -1) + "')"
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return 0, false
+	}
+	return revision, true
+}
+
+func InsertIntoProductTranslation(ctx context.Context, productIdPK int, translation *structs.TranslationStruct) bool {
+	/*
+		To take product_id (primary key of product table) and a translation
+		and insert it into product_translation table, replacing the translation of the locale if it exists already
+		An empty text is stored as NULL, i.e. not translated
+	*/
+	funcName := "InsertIntoProductTranslation"
+	query := "INSERT INTO product_translation (product_id, locale, name, description, story)" +
+		" VALUES (" + strconv.Itoa(productIdPK) +
+		", '" + strings.Replace(translation.Locale, "'", "''", -1) + "'" +
+		", NULLIF('" + strings.Replace(translation.Name, "'", "''", -1) + "', '')" +
+		", NULLIF('" + strings.Replace(translation.Description, "'", "''", -1) + "', '')" +
+		", NULLIF('" + strings.Replace(translation.Story, "'", "''", -1) + "', ''))" +
+		" ON DUPLICATE KEY UPDATE name = VALUES(name), description = VALUES(description), story = VALUES(story)"
+	queryCtx, cancel := queryContext(ctx)
+	_, err := mysqlc.MySqlDB.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func InsertIntoVocabularyTranslation(ctx context.Context, txn *sql.Tx, vocabulary *Vocabulary, id int, locale string,
+	name string) bool {
+	/*
+		To take an entry of a vocabulary, a locale and a name and insert it normalized as the translated name,
+		replacing the translated name of the locale if it exists already
+	*/
+	funcName := "InsertIntoVocabularyTranslation"
+	query := "INSERT INTO " + vocabulary.TranslationTable + " (" + vocabulary.Column + ", locale, name)" +
+		" VALUES (" + strconv.Itoa(id) + ", '" + strings.Replace(locale, "'", "''", -1) + "'" +
+		", '" + strings.Replace(normalizer.Name(name), "'", "''", -1) + "')" +
+		" ON DUPLICATE KEY UPDATE name = VALUES(name)"
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func InsertIntoImage(ctx context.Context, image *Image) bool {
+	/*
+		To insert an uploaded image into image table
+		Uploading the same file again (same key) restarts the grace period it is kept without being referenced
+	*/
+	funcName := "InsertIntoImage"
+	query := "INSERT INTO image (image_key, thumbnail_key, content_type, width, height, size) VALUES (" +
+		"'" + strings.Replace(image.Key, "'", "''", -1) + "'" +
+		", '" + strings.Replace(image.ThumbnailKey, "'", "''", -1) + "'" +
+		", '" + strings.Replace(image.ContentType, "'", "''", -1) + "'" +
+		", " + strconv.Itoa(image.Width) + ", " + strconv.Itoa(image.Height) + ", " + strconv.Itoa(image.Size) + ")" +
+		" ON DUPLICATE KEY UPDATE created_at = CURRENT_TIMESTAMP"
+	queryCtx, cancel := queryContext(ctx)
+	_, err := mysqlc.MySqlDB.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func InsertIntoProductMarket(ctx context.Context, txn *sql.Tx, productIdPK int, market *structs.MarketStruct) bool {
+	/*
+		To take product_id (primary key of product table) and a market and insert it into product_market table,
+		replacing the availability if the market exists already
+	*/
+	funcName := "InsertIntoProductMarket"
+	available := "0"
+	if market.Available {
+		available = "1"
+	}
+	query := "INSERT INTO product_market (product_id, market, available) VALUES (" + strconv.Itoa(productIdPK) +
+		", '" + strings.Replace(market.Market, "'", "''", -1) + "', " + available + ")" +
+		" ON DUPLICATE KEY UPDATE available = VALUES(available)"
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func InsertIntoProductPrice(ctx context.Context, txn *sql.Tx, productIdPK int, market string,
+	prices []*structs.PriceStruct) bool {
+	/*
+		To take product_id (primary key of product table), a market and its prices and insert them
+		into product_price table, an empty effective_to is stored as NULL, i.e. without end
+	*/
+	funcName := "InsertIntoProductPrice"
+	if len(prices) == 0 {
+		return true
+	}
+	query := "INSERT INTO product_price (product_id, market, currency, amount, effective_from, effective_to) VALUES "
+	for index, price := range prices {
+		if index > 0 {
+			query += ", "
+		}
+		query += "(" + strconv.Itoa(productIdPK) +
+			", '" + strings.Replace(market, "'", "''", -1) + "'" +
+			", '" + strings.Replace(price.Currency, "'", "''", -1) + "'" +
+			", " + strconv.FormatInt(price.Amount, 10) +
+			", '" + strings.Replace(price.EffectiveFrom, "'", "''", -1) + "'" +
+			", NULLIF('" + strings.Replace(price.EffectiveTo, "'", "''", -1) + "', ''))"
+	}
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func InsertIntoProductVariant(ctx context.Context, txn *sql.Tx, productIdPK int,
+	variant *structs.VariantStruct) (int, bool) {
+	/*
+		To take product_id (primary key of product table) and a (validated) variant and insert it
+		into product_variant table, an empty packaging image is stored as NULL
+		Return: id of the inserted variant
+	*/
+	funcName := "InsertIntoProductVariant"
+	query := "INSERT INTO product_variant (product_id, sku, name, size, size_unit, packaging_image) VALUES (" +
+		strconv.Itoa(productIdPK) +
+		", '" + strings.Replace(variant.Sku, "'", "''", -1) + "'" +
+		", '" + strings.Replace(variant.Name, "'", "''", -1) + "'" +
+		", " + strconv.FormatFloat(variant.Size, 'f', 2, 64) +
+		", '" + strings.Replace(variant.SizeUnit, "'", "''", -1) + "'" +
+		", NULLIF('" + strings.Replace(variant.PackagingImage, "'", "''", -1) + "', ''))"
+	queryCtx, cancel := queryContext(ctx)
+	insert, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return 0, false
+	}
+	id, _ := insert.LastInsertId()
+	return int(id), true
+}
+
+func InsertIntoProductVariantNutrition(ctx context.Context, txn *sql.Tx, variantId int,
+	nutrition map[string]float64) bool {
+	/*
+		To take id of a variant and its nutrition values by nutrient and insert them into product_variant_nutrition table
+	*/
+	funcName := "InsertIntoProductVariantNutrition"
+	if len(nutrition) == 0 {
+		return true
+	}
+	query := "INSERT INTO product_variant_nutrition (variant_id, nutrient, amount) VALUES "
+	separator := ""
+	for nutrient, amount := range nutrition {
+		query += separator + "(" + strconv.Itoa(variantId) +
+			", '" + strings.Replace(nutrient, "'", "''", -1) + "'" +
+			", " + strconv.FormatFloat(amount, 'f', 3, 64) + ")"
+		separator = ", "
+	}
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func InsertIntoProductNutrition(ctx context.Context, txn *sql.Tx, productIdPK int,
+	nutrition *structs.NutritionStruct) bool {
+	/*
+		To take product_id (primary key of product table) and its (validated) nutrition facts
+		and insert its serving size into product_nutrition table and amounts per 100 g into product_nutrient table
+	*/
+	funcName := "InsertIntoProductNutrition"
+	queries := []string{"INSERT INTO product_nutrition (product_id, serving_size) VALUES (" +
+		strconv.Itoa(productIdPK) + ", " + strconv.FormatFloat(nutrition.ServingSize, 'f', 2, 64) + ")"}
+	if len(nutrition.Nutrients) > 0 {
+		query := "INSERT INTO product_nutrient (product_id, nutrient, amount) VALUES "
+		for index, nutrient := range nutrition.Nutrients {
+			if index > 0 {
+				query += ", "
+			}
+			query += "(" + strconv.Itoa(productIdPK) +
+				", '" + strings.Replace(nutrient.Nutrient, "'", "''", -1) + "'" +
+				", " + strconv.FormatFloat(*nutrient.Per100g, 'f', constants.NutritionDecimals, 64) + ")"
+		}
+		queries = append(queries, query)
+	}
+	for _, query := range queries {
+		queryCtx, cancel := queryContext(ctx)
+		_, err := txn.ExecContext(queryCtx, query)
+		cancel()
+		if err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLQueryRunErrorMessage, err.Error())
+			metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+			return false
+		}
+	}
+	return true
+}
+
+func InsertIntoCategory(ctx context.Context, txn *sql.Tx, parentId int, name string) (int, bool) {
+	/*
+		To take id of the parent category (0 for a root category) and a name
+		and insert it normalized as a new category, its key must not exist among the children of the parent
+	*/
+	funcName := "InsertIntoCategory"
+	query := "INSERT INTO category (parent_id, name, name_key) VALUES (" + categoryParentValue(parentId) +
+		", '" + strings.Replace(normalizer.Name(name), "'", "''", -1) + "'" +
+		", '" + strings.Replace(normalizer.Key(name), "'", "''", -1) + "')"
+	queryCtx, cancel := queryContext(ctx)
+	insert, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return 0, false
+	}
+	id, _ := insert.LastInsertId()
+	return int(id), true
+}
+
+func InsertIntoProductCategory(ctx context.Context, txn *sql.Tx, productIdPK int, categoryIds []int) bool {
+	/*
+		To take product_id (primary key of product table) and ids of its categories
+		and insert into product_category table
+	*/
+	funcName := "InsertIntoProductCategory"
+	if len(categoryIds) == 0 {
+		return true
+	}
+	query := "INSERT INTO product_category (product_id, category_id) VALUES "
+	for index, categoryId := range categoryIds {
+		if index > 0 {
+			query += ", "
+		}
+		query += "(" + strconv.Itoa(productIdPK) + ", " + strconv.Itoa(categoryId) + ")"
+	}
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func InsertIntoProductScheduleEvent(ctx context.Context, txn *sql.Tx, scheduleEvents []*ScheduleEvent) bool {
+	/*
+		To take events of scheduled products and record them in product_schedule_event table, not emitted yet
+	*/
+	funcName := "InsertIntoProductScheduleEvent"
+	if len(scheduleEvents) == 0 {
+		return true
+	}
+	query := "INSERT INTO product_schedule_event (product_id, event, scheduled_at) VALUES "
+	for index, scheduleEvent := range scheduleEvents {
+		if index > 0 {
+			query += ", "
+		}
+		query += "(" + strconv.Itoa(scheduleEvent.ProductIdPK) +
+			", '" + strings.Replace(scheduleEvent.Event, "'", "''", -1) + "'" +
+			", '" + strings.Replace(scheduleEvent.ScheduledAt, "'", "''", -1) + "')"
+	}
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func InsertIntoProductDraft(ctx context.Context, txn *sql.Tx, draft *Draft) bool {
+	/*
+		To take the draft of a product and insert it into product_draft table, replacing its previous draft
+	*/
+	funcName := "InsertIntoProductDraft"
+	query := "INSERT INTO product_draft (product_id, data, fields, status) VALUES (" +
+		strconv.Itoa(draft.ProductId) +
+		", '" + strings.Replace(draft.Data, "'", "''", -1) + "'" +
+		", '" + strings.Replace(draft.Fields, "'", "''", -1) + "'" +
+		", '" + strings.Replace(draft.Status, "'", "''", -1) + "')" +
+		" ON DUPLICATE KEY UPDATE data = VALUES(data), fields = VALUES(fields), status = VALUES(status)," +
+		" updated_at = CURRENT_TIMESTAMP"
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
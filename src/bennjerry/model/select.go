@@ -1,58 +1,76 @@
 package model
 
 import (
+	"context"
 	"database/sql"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 
+	"bennjerry/structs"
 	"constants"
 	"logger"
+	"metrics"
 	"mysqlc"
+	"normalizer"
 )
 
-func SelectFromProductByProductId(productId string) (*Product, bool) {
+func SelectFromProductByProductId(ctx context.Context, productId string) (*Product, bool) {
 	/*
 		To take product_id and select columns from product table
+		Only live products are selected, i.e. active ones published now (see liveProductCondition)
 	*/
 	funcName := "SelectFromProductByProductId"
 	query := "SELECT id, product_id, name, description, story, image_closed, image_opened, allergy," +
-		" dietary_certification_id FROM product WHERE is_inactive = 0 and product_id = '" + productId + "'"
-	selectQ, err := mysqlc.MySqlDB.Query(query)
+		" allergens_declared, publish_at, unpublish_at FROM product WHERE " + liveProductCondition(time.Now()) +
+		" and product_id = '" + productId + "'"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 	} else {
 		product := &Product{}
 		for selectQ.Next() {
+			var publishAt, unpublishAt sql.NullString
 			err := selectQ.Scan(&product.Id, &product.ProductId, &product.Name, &product.Description, &product.Story,
-				&product.ImageClosed, &product.ImageOpened, &product.Allergy, &product.DietaryCertificationId)
+				&product.ImageClosed, &product.ImageOpened, &product.Allergy, &product.AllergensDeclared, &publishAt,
+				&unpublishAt)
 			if err != nil {
-				logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 					constants.MySQLSelectScanErrorMessage, err.Error())
 			}
+			product.PublishAt, product.UnpublishAt = publishAt.String, unpublishAt.String
 		}
 		return product, true
 	}
 	return nil, false
 }
 
-func SelectIdFromProductByProductId(productId string) (int, bool) {
+func SelectIdFromProductByProductId(ctx context.Context, productId string) (int, bool) {
 	/*
 		To take product_id and select id from product table
 	*/
 	funcName := "SelectIdFromProductByProductId"
 	query := "SELECT id FROM product WHERE product_id = '" + productId + "'"
-	selectQ, err := mysqlc.MySqlDB.Query(query)
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 	} else {
 		var id int
 		for selectQ.Next() {
 			err := selectQ.Scan(&id)
 			if err != nil {
-				logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 					constants.MySQLSelectScanErrorMessage, err.Error())
 			}
 		}
@@ -61,125 +79,143 @@ func SelectIdFromProductByProductId(productId string) (int, bool) {
 	return 0, false
 }
 
-func SelectFromSourcingValue(txn *sql.Tx, nameList []string) []*Property {
+func SelectFromSourcingValue(ctx context.Context, txn *sql.Tx, nameList []string) []*Property {
 	/*
 		To take list of names and select id, name from sourcingvalue table
 	*/
-	funcName := "SelectFromSourcingValue"
-	result := make([]*Property, 0)
-	lenNameList := len(nameList)
-	query := "SELECT id, name FROM sourcingvalue WHERE name In ("
-	for index := 0; index < lenNameList-1; index++ {
-		query += "'" + nameList[index] + "', "
-	}
-	query += "'" + nameList[lenNameList-1] + "')"
-	selectQ, err := txn.Query(query)
-	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
-			constants.MySQLQueryRunErrorMessage, err.Error())
-	} else {
-		for selectQ.Next() {
-			sourcingValue := &Property{}
-			err := selectQ.Scan(&sourcingValue.Id, &sourcingValue.Name)
-			if err != nil {
-				logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
-					constants.MySQLSelectScanErrorMessage, err.Error())
-			} else {
-				result = append(result, sourcingValue)
-			}
-		}
-	}
-	return result
+	return selectFromVocabularyByNames(ctx, txn, "SelectFromSourcingValue", "sourcingvalue", nameList)
 }
 
-func SelectFromIngredient(txn *sql.Tx, nameList []string) []*Property {
+func SelectFromIngredient(ctx context.Context, txn *sql.Tx, nameList []string) []*Property {
 	/*
 		To take list of names and select id, name from ingredient table
 	*/
-	funcName := "SelectFromIngredient"
+	return selectFromVocabularyByNames(ctx, txn, "SelectFromIngredient", "ingredient", nameList)
+}
+
+func SelectFromDietaryCertification(ctx context.Context, txn *sql.Tx, nameList []string) []*Property {
+	/*
+		To take list of names and select id, name from dietarycertification table
+	*/
+	return selectFromVocabularyByNames(ctx, txn, "SelectFromDietaryCertification", "dietarycertification", nameList)
+}
+
+func SelectFromTag(ctx context.Context, txn *sql.Tx, nameList []string) []*Property {
+	/*
+		To take list of names and select id, name from tag table
+	*/
+	return selectFromVocabularyByNames(ctx, txn, "SelectFromTag", "tag", nameList)
+}
+
+func selectFromVocabularyByNames(ctx context.Context, txn *sql.Tx, funcName string, table string,
+	nameList []string) []*Property {
+	/*
+		To take list of names and select id, name of the matching entries of a vocabulary table
+		Names are matched by their normalized key, e.g. "milk " and "MILK" both select "Milk"
+		If several entries share a key (till the dedupe command merges them), only the oldest one is selected
+	*/
 	result := make([]*Property, 0)
-	lenNameList := len(nameList)
-	query := "SELECT id, name FROM ingredient WHERE name In ("
-	for index := 0; index < lenNameList-1; index++ {
-		query += "'" + nameList[index] + "', "
+	keyList := normalizer.Keys(nameList)
+	if len(keyList) == 0 {
+		return result
+	}
+	query := "SELECT id, name FROM " + table + " WHERE id IN (SELECT MIN(id) FROM " + table + " WHERE name_key IN ("
+	for index, key := range keyList {
+		if index > 0 {
+			query += ", "
+		}
+		query += "'" + strings.Replace(key, "'", "''", -1) + "'"
 	}
-	query += "'" + nameList[lenNameList-1] + "')"
-	selectQ, err := txn.Query(query)
+	query += ") GROUP BY name_key)"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 	} else {
 		for selectQ.Next() {
-			ingredient := &Property{}
-			err := selectQ.Scan(&ingredient.Id, &ingredient.Name)
+			property := &Property{}
+			err := selectQ.Scan(&property.Id, &property.Name)
 			if err != nil {
-				logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 					constants.MySQLSelectScanErrorMessage, err.Error())
 			} else {
-				result = append(result, ingredient)
+				result = append(result, property)
 			}
 		}
 	}
 	return result
 }
 
-func SelectFromDietaryCertification(txn *sql.Tx, nameList []string) []*Property {
+func SelectDietaryCertificationNameByProductIdPK(ctx context.Context, productIdPK int) []string {
 	/*
-		To take list of names and select id, name from dietarycertification table
+		To take product_id (primary key of product table) and select dietarycertification name
 	*/
-	funcName := "SelectFromDietaryCertification"
-	result := make([]*Property, 0)
-	lenNameList := len(nameList)
-	query := "SELECT id, name FROM dietarycertification WHERE name In ("
-	for index := 0; index < lenNameList-1; index++ {
-		query += "'" + nameList[index] + "', "
-	}
-	query += "'" + nameList[lenNameList-1] + "')"
-	selectQ, err := txn.Query(query)
+	funcName := "SelectDietaryCertificationNameByProductIdPK"
+	result := make([]string, 0)
+	query := "SELECT dietarycertification.name FROM product_dietarycertification INNER JOIN dietarycertification" +
+		" ON product_dietarycertification.dietarycertification_id = dietarycertification.id" +
+		" WHERE product_dietarycertification.product_id = " + strconv.Itoa(productIdPK)
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 	} else {
+		var name string
 		for selectQ.Next() {
-			dietaryCertification := &Property{}
-			err := selectQ.Scan(&dietaryCertification.Id, &dietaryCertification.Name)
+			err := selectQ.Scan(&name)
 			if err != nil {
-				logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 					constants.MySQLSelectScanErrorMessage, err.Error())
 			} else {
-				result = append(result, dietaryCertification)
+				result = append(result, name)
 			}
 		}
 	}
 	return result
 }
 
-func SelectFromDietaryCertificationById(id int) *Property {
+func SelectFromProductDietaryCertificationByProductIdPK(ctx context.Context, txn *sql.Tx,
+	productIdPK int) ([]*ProductProperty, bool) {
 	/*
-		To take id (primary key) and select name from dietarycertification table
+		To take product_id (primary key of product table)
+		and select product id, dietarycertification id, dietarycertification name
 	*/
-	funcName := "SelectFromDietaryCertificationById"
-	query := "SELECT name FROM dietarycertification WHERE id = " + strconv.Itoa(id)
-	selectQ, err := mysqlc.MySqlDB.Query(query)
+	funcName := "SelectFromProductDietaryCertificationByProductIdPK"
+	result := make([]*ProductProperty, 0)
+	query := "SELECT product_dietarycertification.product_id, product_dietarycertification.dietarycertification_id," +
+		" dietarycertification.name FROM product_dietarycertification INNER JOIN dietarycertification" +
+		" ON product_dietarycertification.dietarycertification_id = dietarycertification.id" +
+		" WHERE product_dietarycertification.product_id = " + strconv.Itoa(productIdPK)
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
-	} else {
-		data := &Property{Id: id}
-		for selectQ.Next() {
-			data.Id = id
-			err := selectQ.Scan(&data.Name)
-			if err != nil {
-				logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
-					constants.MySQLSelectScanErrorMessage, err.Error())
-			}
-			return data
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	for selectQ.Next() {
+		productProperty := &ProductProperty{}
+		err := selectQ.Scan(&productProperty.ProductId, &productProperty.PropertyId, &productProperty.PropertyName)
+		if err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
 		}
+		result = append(result, productProperty)
 	}
-	return nil
+	return result, true
 }
 
-func SelectSourcingValueNameByProductIdPK(productIdPK int) []string {
+func SelectSourcingValueNameByProductIdPK(ctx context.Context, productIdPK int) []string {
 	/*
 		To take product_id (primary key of product table) and select sourcingvalue name
 	*/
@@ -188,16 +224,19 @@ func SelectSourcingValueNameByProductIdPK(productIdPK int) []string {
 	query := "SELECT sourcingvalue.name FROM product_sourcingvalue INNER JOIN sourcingvalue" +
 		" ON product_sourcingvalue.sourcingvalue_id = sourcingvalue.id" +
 		" WHERE product_sourcingvalue.product_id = " + strconv.Itoa(productIdPK)
-	selectQ, err := mysqlc.MySqlDB.Query(query)
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 	} else {
 		var name string
 		for selectQ.Next() {
 			err := selectQ.Scan(&name)
 			if err != nil {
-				logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 					constants.MySQLSelectScanErrorMessage, err.Error())
 			} else {
 				result = append(result, name)
@@ -207,7 +246,8 @@ func SelectSourcingValueNameByProductIdPK(productIdPK int) []string {
 	return result
 }
 
-func SelectFromProductSourcingValueByProductIdPK(txn *sql.Tx, productIdPK int) []*ProductProperty {
+func SelectFromProductSourcingValueByProductIdPK(ctx context.Context, txn *sql.Tx,
+	productIdPK int) ([]*ProductProperty, bool) {
 	/*
 		To take product_id (primary key of product table) and select product id, sourcingvalue id, sourcingvalue name
 	*/
@@ -217,77 +257,1588 @@ func SelectFromProductSourcingValueByProductIdPK(txn *sql.Tx, productIdPK int) [
 		" FROM product_sourcingvalue INNER JOIN sourcingvalue" +
 		" ON product_sourcingvalue.sourcingvalue_id = sourcingvalue.id" +
 		" WHERE product_sourcingvalue.product_id = " + strconv.Itoa(productIdPK)
-	selectQ, err := txn.Query(query)
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	for selectQ.Next() {
+		productProperty := &ProductProperty{}
+		err := selectQ.Scan(&productProperty.ProductId, &productProperty.PropertyId, &productProperty.PropertyName)
+		if err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, productProperty)
+	}
+	return result, true
+}
+
+func SelectTagNameByProductIdPK(ctx context.Context, productIdPK int) []string {
+	/*
+		To take product_id (primary key of product table) and select tag names, in order of name
+	*/
+	funcName := "SelectTagNameByProductIdPK"
+	result := make([]string, 0)
+	query := "SELECT tag.name FROM product_tag INNER JOIN tag ON product_tag.tag_id = tag.id" +
+		" WHERE product_tag.product_id = " + strconv.Itoa(productIdPK) + " ORDER BY tag.name"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 	} else {
+		var name string
 		for selectQ.Next() {
-			productProperty := &ProductProperty{}
-			err := selectQ.Scan(&productProperty.ProductId, &productProperty.PropertyId, &productProperty.PropertyName)
+			err := selectQ.Scan(&name)
 			if err != nil {
-				logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 					constants.MySQLSelectScanErrorMessage, err.Error())
 			} else {
-				result = append(result, productProperty)
+				result = append(result, name)
 			}
 		}
 	}
 	return result
 }
 
-func SelectIngredientNameFromProductIngredientByProductIdPK(productIdPK int) []string {
+func SelectFromProductTagByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int) ([]*ProductProperty, bool) {
 	/*
-		To take product_id (primary key of product table) and select ingredient name
+		To take product_id (primary key of product table) and select product id, tag id, tag name, in order of name
 	*/
-	funcName := "SelectIngredientNameFromProductIngredientByProductIdPK"
-	result := make([]string, 0)
-	query := "SELECT ingredient.name FROM product_ingredient INNER JOIN ingredient ON" +
-		" product_ingredient.ingredient_id = ingredient.id" +
-		" WHERE product_ingredient.product_id = " + strconv.Itoa(productIdPK)
-	selectQ, err := mysqlc.MySqlDB.Query(query)
+	funcName := "SelectFromProductTagByProductIdPK"
+	result := make([]*ProductProperty, 0)
+	query := "SELECT product_tag.product_id, product_tag.tag_id, tag.name FROM product_tag INNER JOIN tag" +
+		" ON product_tag.tag_id = tag.id WHERE product_tag.product_id = " + strconv.Itoa(productIdPK) +
+		" ORDER BY tag.name"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	for selectQ.Next() {
+		productProperty := &ProductProperty{}
+		err := selectQ.Scan(&productProperty.ProductId, &productProperty.PropertyId, &productProperty.PropertyName)
+		if err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, productProperty)
+	}
+	return result, true
+}
+
+func SelectIngredientFromProductIngredientByProductIdPK(ctx context.Context, productIdPK int) structs.IngredientList {
+	/*
+		To take product_id (primary key of product table) and select ingredient name and percentage, in order
+	*/
+	funcName := "SelectIngredientFromProductIngredientByProductIdPK"
+	result := make(structs.IngredientList, 0)
+	query := "SELECT ingredient.name, product_ingredient.percentage FROM product_ingredient INNER JOIN ingredient" +
+		" ON product_ingredient.ingredient_id = ingredient.id" +
+		" WHERE product_ingredient.product_id = " + strconv.Itoa(productIdPK) + " ORDER BY product_ingredient.position"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 	} else {
-		var name string
 		for selectQ.Next() {
-			err := selectQ.Scan(&name)
+			ingredient, err := scanIngredient(selectQ)
 			if err != nil {
-				logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 					constants.MySQLSelectScanErrorMessage, err.Error())
+			} else {
+				result = append(result, ingredient)
 			}
-			result = append(result, name)
 		}
 	}
 	return result
 }
 
-func SelectFromProductIngredientByProductIdPK(txn *sql.Tx, productIdPK int) []*ProductProperty {
+func SelectFromProductIngredientByProductIdPK(ctx context.Context, txn *sql.Tx,
+	productIdPK int) (structs.IngredientList, bool) {
 	/*
-		To take product_id (primary key of product table) and select product id, ingredient id, ingredient name
+		To take product_id (primary key of product table)
+		and select ingredient name and percentage, in order, inside a transaction
 	*/
 	funcName := "SelectFromProductIngredientByProductIdPK"
-	result := make([]*ProductProperty, 0)
-	query := "SELECT product_ingredient.product_id, product_ingredient.ingredient_id, ingredient.name" +
-		" FROM product_ingredient INNER JOIN ingredient" +
+	result := make(structs.IngredientList, 0)
+	query := "SELECT ingredient.name, product_ingredient.percentage FROM product_ingredient INNER JOIN ingredient" +
 		" ON product_ingredient.ingredient_id = ingredient.id" +
-		" WHERE product_ingredient.product_id = " + strconv.Itoa(productIdPK)
-	selectQ, err := txn.Query(query)
+		" WHERE product_ingredient.product_id = " + strconv.Itoa(productIdPK) + " ORDER BY product_ingredient.position"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	for selectQ.Next() {
+		ingredient, err := scanIngredient(selectQ)
+		if err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, ingredient)
+	}
+	return result, true
+}
+
+func scanIngredient(selectQ *sql.Rows) (*structs.IngredientStruct, error) {
+	/*
+		To scan a row of ingredient name and percentage, percentage is nil if it's not declared
+	*/
+	ingredient := &structs.IngredientStruct{}
+	var percentage sql.NullFloat64
+	if err := selectQ.Scan(&ingredient.Name, &percentage); err != nil {
+		return nil, err
+	}
+	if percentage.Valid {
+		ingredient.Percentage = &percentage.Float64
+	}
+	return ingredient, nil
+}
+
+func SelectFromAllergen(ctx context.Context) ([]*Allergen, bool) {
+	/*
+		To select id, code, name of all allergens of the taxonomy from allergen table
+	*/
+	funcName := "SelectFromAllergen"
+	query := "SELECT id, code, name FROM allergen ORDER BY code"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]*Allergen, 0)
+	for selectQ.Next() {
+		allergen := &Allergen{}
+		if err := selectQ.Scan(&allergen.Id, &allergen.Code, &allergen.Name); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, allergen)
+	}
+	return result, true
+}
+
+func SelectFromAllergenByCode(ctx context.Context, txn *sql.Tx, codeList []string) []*Allergen {
+	/*
+		To take list of codes and select id, code, name from allergen table
+	*/
+	funcName := "SelectFromAllergenByCode"
+	result := make([]*Allergen, 0)
+	lenCodeList := len(codeList)
+	query := "SELECT id, code, name FROM allergen WHERE code In ("
+	for index := 0; index < lenCodeList-1; index++ {
+		query += "'" + strings.Replace(codeList[index], "'", "''", -1) + "', "
+	}
+	query += "'" + strings.Replace(codeList[lenCodeList-1], "'", "''", -1) + "')"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+	} else {
+		for selectQ.Next() {
+			allergen := &Allergen{}
+			err := selectQ.Scan(&allergen.Id, &allergen.Code, &allergen.Name)
+			if err != nil {
+				logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+					constants.MySQLSelectScanErrorMessage, err.Error())
+			} else {
+				result = append(result, allergen)
+			}
+		}
+	}
+	return result
+}
+
+func SelectAllergenByProductIdPK(ctx context.Context, productIdPK int) []*structs.AllergenStruct {
+	/*
+		To take product_id (primary key of product table) and select allergen code and level
+	*/
+	funcName := "SelectAllergenByProductIdPK"
+	result := make([]*structs.AllergenStruct, 0)
+	query := "SELECT allergen.code, product_allergen.level FROM product_allergen INNER JOIN allergen" +
+		" ON product_allergen.allergen_id = allergen.id" +
+		" WHERE product_allergen.product_id = " + strconv.Itoa(productIdPK) + " ORDER BY allergen.code"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 	} else {
-		productProperty := &ProductProperty{}
 		for selectQ.Next() {
-			err := selectQ.Scan(&productProperty.ProductId, &productProperty.PropertyId, &productProperty.PropertyName)
+			allergen := &structs.AllergenStruct{}
+			err := selectQ.Scan(&allergen.Code, &allergen.Level)
 			if err != nil {
-				logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 					constants.MySQLSelectScanErrorMessage, err.Error())
 			} else {
-				result = append(result, productProperty)
+				result = append(result, allergen)
 			}
 		}
 	}
 	return result
 }
+
+func SelectFromProductAllergenByProductIdPK(ctx context.Context, txn *sql.Tx,
+	productIdPK int) ([]*structs.AllergenStruct, bool) {
+	/*
+		To take product_id (primary key of product table) and select allergen code and level inside a transaction
+	*/
+	funcName := "SelectFromProductAllergenByProductIdPK"
+	result := make([]*structs.AllergenStruct, 0)
+	query := "SELECT allergen.code, product_allergen.level FROM product_allergen INNER JOIN allergen" +
+		" ON product_allergen.allergen_id = allergen.id" +
+		" WHERE product_allergen.product_id = " + strconv.Itoa(productIdPK) + " ORDER BY allergen.code"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	for selectQ.Next() {
+		allergen := &structs.AllergenStruct{}
+		err := selectQ.Scan(&allergen.Code, &allergen.Level)
+		if err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, allergen)
+	}
+	return result, true
+}
+
+func SelectNutritionByProductIdPK(ctx context.Context, productIdPK int) *structs.NutritionStruct {
+	/*
+		To take product_id (primary key of product table) and select its nutrition facts
+		Return: nil, if the product hasn't declared them
+	*/
+	funcName := "SelectNutritionByProductIdPK"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, nutritionQuery(productIdPK))
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil
+	}
+	defer selectQ.Close()
+	nutrition, err := scanNutrition(selectQ)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLSelectScanErrorMessage, err.Error())
+	}
+	return nutrition
+}
+
+func SelectFromProductNutritionByProductIdPK(ctx context.Context, txn *sql.Tx,
+	productIdPK int) (*structs.NutritionStruct, bool) {
+	/*
+		To take product_id (primary key of product table) and select its nutrition facts inside a transaction
+		Return: nil with true, if the product hasn't declared them
+	*/
+	funcName := "SelectFromProductNutritionByProductIdPK"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, nutritionQuery(productIdPK))
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	nutrition, err := scanNutrition(selectQ)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLSelectScanErrorMessage, err.Error())
+		return nil, false
+	}
+	return nutrition, true
+}
+
+func nutritionQuery(productIdPK int) string {
+	/*
+		To build the query of serving size and amounts per 100 g of nutrition facts of a product,
+		a single row of NULL nutrient if it has none
+	*/
+	return "SELECT product_nutrition.serving_size, product_nutrient.nutrient, product_nutrient.amount" +
+		" FROM product_nutrition LEFT JOIN product_nutrient" +
+		" ON product_nutrient.product_id = product_nutrition.product_id" +
+		" WHERE product_nutrition.product_id = " + strconv.Itoa(productIdPK)
+}
+
+func scanNutrition(selectQ *sql.Rows) (*structs.NutritionStruct, error) {
+	/*
+		To scan rows of serving size, nutrient and amount per 100 g into nutrition facts,
+		with nutrients in the order of the label and amounts per serving computed
+	*/
+	var nutrition *structs.NutritionStruct
+	for selectQ.Next() {
+		var servingSize float64
+		var nutrient sql.NullString
+		var amount sql.NullFloat64
+		if err := selectQ.Scan(&servingSize, &nutrient, &amount); err != nil {
+			return nil, err
+		}
+		if nutrition == nil {
+			nutrition = &structs.NutritionStruct{ServingSize: servingSize, Nutrients: []*structs.NutrientStruct{}}
+		}
+		if nutrient.Valid {
+			per100g := amount.Float64
+			nutrition.Nutrients = append(nutrition.Nutrients, &structs.NutrientStruct{Nutrient: nutrient.String,
+				Per100g: &per100g})
+		}
+	}
+	if nutrition != nil {
+		completeNutrition(nutrition)
+	}
+	return nutrition, nil
+}
+
+func SelectFromProductFreeFromAllergen(ctx context.Context, code string, includeMayContain bool) ([]*Product, bool) {
+	/*
+		To take an allergen code and select live products which have declared their allergens without it
+		Products that may contain the allergen are selected only if includeMayContain is true
+		Products that haven't declared their allergens are never selected, as they can't be told free of it
+	*/
+	funcName := "SelectFromProductFreeFromAllergen"
+	query := "SELECT id, product_id, name FROM product WHERE " + liveProductCondition(time.Now()) +
+		" AND allergens_declared = 1" +
+		" AND NOT EXISTS (SELECT 1 FROM product_allergen INNER JOIN allergen" +
+		" ON product_allergen.allergen_id = allergen.id WHERE product_allergen.product_id = product.id" +
+		" AND allergen.code = '" + strings.Replace(code, "'", "''", -1) + "'"
+	if includeMayContain {
+		query += " AND product_allergen.level = '" + constants.AllergenLevelContains + "'"
+	}
+	query += ") ORDER BY product_id"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]*Product, 0)
+	for selectQ.Next() {
+		product := &Product{}
+		if err := selectQ.Scan(&product.Id, &product.ProductId, &product.Name); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, product)
+	}
+	return result, true
+}
+
+func SelectFromProductById(ctx context.Context, txn *sql.Tx, id int) (*Product, bool) {
+	/*
+		To take id (primary key) and select columns from product table inside a transaction
+		Inactive records are also selected
+	*/
+	funcName := "SelectFromProductById"
+	query := "SELECT id, product_id, name, description, story, image_closed, image_opened, allergy," +
+		" allergens_declared, is_inactive, publish_at, unpublish_at FROM product WHERE id = " + strconv.Itoa(id)
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	product := &Product{}
+	for selectQ.Next() {
+		var publishAt, unpublishAt sql.NullString
+		err := selectQ.Scan(&product.Id, &product.ProductId, &product.Name, &product.Description, &product.Story,
+			&product.ImageClosed, &product.ImageOpened, &product.Allergy, &product.AllergensDeclared, &product.IsInActive,
+			&publishAt, &unpublishAt)
+		if err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		product.PublishAt, product.UnpublishAt = publishAt.String, unpublishAt.String
+	}
+	return product, true
+}
+
+func SelectIdFromProductByIdForUpdate(ctx context.Context, txn *sql.Tx, productIdPK int) (int, bool) {
+	/*
+		To take product_id (primary key of product table) and select it inside a transaction,
+		locking the product row till the transaction ends, so that its revisions are numbered one at a time
+		Id will be 0, if the product doesn't exist
+	*/
+	funcName := "SelectIdFromProductByIdForUpdate"
+	query := "SELECT id FROM product WHERE id = " + strconv.Itoa(productIdPK) + " FOR UPDATE"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return 0, false
+	}
+	defer selectQ.Close()
+	var id int
+	for selectQ.Next() {
+		if err := selectQ.Scan(&id); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return 0, false
+		}
+	}
+	return id, true
+}
+
+func SelectMaxRevisionFromProductRevisionByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int) (int, bool) {
+	/*
+		To take product_id (primary key of product table) and select the latest revision number of that product
+		Revision will be 0, if product has no revisions yet
+	*/
+	funcName := "SelectMaxRevisionFromProductRevisionByProductIdPK"
+	query := "SELECT IFNULL(MAX(revision), 0) FROM product_revision WHERE product_id = " + strconv.Itoa(productIdPK)
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return 0, false
+	}
+	defer selectQ.Close()
+	var revision int
+	for selectQ.Next() {
+		err := selectQ.Scan(&revision)
+		if err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return 0, false
+		}
+	}
+	return revision, true
+}
+
+func SelectFromProductRevisionByProductIdPK(ctx context.Context, productIdPK int,
+	revision int) (*ProductRevision, bool) {
+	/*
+		To take product_id (primary key of product table) and revision number and select the stored snapshot
+		success: true, productRevision: nil, if the revision doesn't exist
+	*/
+	funcName := "SelectFromProductRevisionByProductIdPK"
+	query := "SELECT id, product_id, revision, snapshot, created_at FROM product_revision" +
+		" WHERE product_id = " + strconv.Itoa(productIdPK) + " AND revision = " + strconv.Itoa(revision)
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	var productRevision *ProductRevision
+	for selectQ.Next() {
+		productRevision = &ProductRevision{}
+		err := selectQ.Scan(&productRevision.Id, &productRevision.ProductId, &productRevision.Revision,
+			&productRevision.Snapshot, &productRevision.CreatedAt)
+		if err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+	}
+	return productRevision, true
+}
+
+func SelectVocabularyWithUsage(ctx context.Context, vocabulary *Vocabulary) ([]*PropertyUsage, bool) {
+	/*
+		To take a vocabulary and select id, name, name_key of all its entries with the number of products using them
+	*/
+	funcName := "SelectVocabularyWithUsage"
+	query := "SELECT " + vocabulary.Table + ".id, " + vocabulary.Table + ".name, " + vocabulary.Table + ".name_key" +
+		", COUNT(" + vocabulary.RelationTable + ".product_id) FROM " + vocabulary.Table +
+		" LEFT JOIN " + vocabulary.RelationTable +
+		" ON " + vocabulary.Table + ".id = " + vocabulary.RelationTable + "." + vocabulary.Column +
+		" GROUP BY " + vocabulary.Table + ".id, " + vocabulary.Table + ".name, " + vocabulary.Table + ".name_key" +
+		" ORDER BY " + vocabulary.Table + ".name"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]*PropertyUsage, 0)
+	for selectQ.Next() {
+		propertyUsage := &PropertyUsage{}
+		if err := selectQ.Scan(&propertyUsage.Id, &propertyUsage.Name, &propertyUsage.NameKey,
+			&propertyUsage.ProductCount); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, propertyUsage)
+	}
+	return result, true
+}
+
+func SelectFromVocabularyById(ctx context.Context, txn *sql.Tx, vocabulary *Vocabulary, id int) (*Property, bool) {
+	/*
+		To take a vocabulary and id and select id, name of the entry inside a transaction
+		success: true, property: nil, if the entry doesn't exist
+	*/
+	funcName := "SelectFromVocabularyById"
+	query := "SELECT id, name FROM " + vocabulary.Table + " WHERE id = " + strconv.Itoa(id) + " FOR UPDATE"
+	return selectProperty(ctx, txn, funcName, query)
+}
+
+func SelectFromVocabularyByName(ctx context.Context, txn *sql.Tx, vocabulary *Vocabulary,
+	name string) (*Property, bool) {
+	/*
+		To take a vocabulary and name and select id, name of the entry inside a transaction
+		Names are matched by their normalized key, e.g. ignoring case, the oldest entry is selected if several match
+		success: true, property: nil, if the entry doesn't exist
+	*/
+	funcName := "SelectFromVocabularyByName"
+	query := "SELECT id, name FROM " + vocabulary.Table +
+		" WHERE name_key = '" + strings.Replace(normalizer.Key(name), "'", "''", -1) + "'" +
+		" ORDER BY id LIMIT 1 FOR UPDATE"
+	return selectProperty(ctx, txn, funcName, query)
+}
+
+func selectProperty(ctx context.Context, txn *sql.Tx, funcName string, query string) (*Property, bool) {
+	/*
+		To run a select query of a single id, name row inside the transaction
+	*/
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	var property *Property
+	for selectQ.Next() {
+		property = &Property{}
+		if err := selectQ.Scan(&property.Id, &property.Name); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+	}
+	return property, true
+}
+
+func SelectProductIdPKByVocabularyId(ctx context.Context, txn *sql.Tx, vocabulary *Vocabulary, id int) ([]int, bool) {
+	/*
+		To take a vocabulary and id of an entry and select ids (primary key) of all products using it
+	*/
+	funcName := "SelectProductIdPKByVocabularyId"
+	query := "SELECT product_id FROM " + vocabulary.RelationTable +
+		" WHERE " + vocabulary.Column + " = " + strconv.Itoa(id) + " ORDER BY product_id"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]int, 0)
+	for selectQ.Next() {
+		var productIdPK int
+		if err := selectQ.Scan(&productIdPK); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, productIdPK)
+	}
+	return result, true
+}
+
+func SelectUnUsedSourcingValue(ctx context.Context, txn *sql.Tx) ([]string, bool) {
+	/*
+		To select names from sourcingvalue table that aren't used by any product
+	*/
+	funcName := "SelectUnUsedSourcingValue"
+	query := "SELECT sourcingvalue.name FROM sourcingvalue LEFT JOIN product_sourcingvalue" +
+		" ON sourcingvalue.id = product_sourcingvalue.sourcingvalue_id" +
+		" WHERE product_sourcingvalue.sourcingvalue_id is NULL ORDER BY sourcingvalue.name"
+	return selectNames(ctx, txn, funcName, query)
+}
+
+func SelectUnUsedIngredient(ctx context.Context, txn *sql.Tx) ([]string, bool) {
+	/*
+		To select names from ingredient table that aren't used by any product
+	*/
+	funcName := "SelectUnUsedIngredient"
+	query := "SELECT ingredient.name FROM ingredient LEFT JOIN product_ingredient" +
+		" ON ingredient.id = product_ingredient.ingredient_id" +
+		" WHERE product_ingredient.ingredient_id is NULL ORDER BY ingredient.name"
+	return selectNames(ctx, txn, funcName, query)
+}
+
+func SelectUnUsedDietaryCertification(ctx context.Context, txn *sql.Tx) ([]string, bool) {
+	/*
+		To select names from dietarycertification table that aren't used by any product
+	*/
+	funcName := "SelectUnUsedDietaryCertification"
+	query := "SELECT dietarycertification.name FROM dietarycertification LEFT JOIN product_dietarycertification" +
+		" ON dietarycertification.id = product_dietarycertification.dietarycertification_id" +
+		" WHERE product_dietarycertification.dietarycertification_id is NULL ORDER BY dietarycertification.name"
+	return selectNames(ctx, txn, funcName, query)
+}
+
+func SelectUnUsedTag(ctx context.Context, txn *sql.Tx) ([]string, bool) {
+	/*
+		To select names from tag table that aren't used by any product
+	*/
+	funcName := "SelectUnUsedTag"
+	query := "SELECT tag.name FROM tag LEFT JOIN product_tag ON tag.id = product_tag.tag_id" +
+		" WHERE product_tag.tag_id is NULL ORDER BY tag.name"
+	return selectNames(ctx, txn, funcName, query)
+}
+
+func selectNames(ctx context.Context, txn *sql.Tx, funcName string, query string) ([]string, bool) {
+	/*
+		To run a select query of a single name column inside the transaction
+	*/
+	result := make([]string, 0)
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	for selectQ.Next() {
+		var name string
+		if err := selectQ.Scan(&name); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, name)
+	}
+	return result, true
+}
+
+func SelectFromProductTranslationByProductIdPK(ctx context.Context,
+	productIdPK int) ([]*structs.TranslationStruct, bool) {
+	/*
+		To take product_id (primary key of product table) and select all its translations, in order of locale
+	*/
+	funcName := "SelectFromProductTranslationByProductIdPK"
+	query := "SELECT locale, COALESCE(name, ''), COALESCE(description, ''), COALESCE(story, '')" +
+		" FROM product_translation WHERE product_id = " + strconv.Itoa(productIdPK) + " ORDER BY locale"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]*structs.TranslationStruct, 0)
+	for selectQ.Next() {
+		translation := &structs.TranslationStruct{}
+		err := selectQ.Scan(&translation.Locale, &translation.Name, &translation.Description, &translation.Story)
+		if err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, translation)
+	}
+	return result, true
+}
+
+func SelectVocabularyTranslationByProductIdPK(ctx context.Context, vocabulary *Vocabulary, productIdPK int,
+	locales []string) map[string]map[string]string {
+	/*
+		To take a vocabulary, product_id (primary key of product table) and locales
+		and select translated names of the entries used by the product in those locales
+		Return: map {name: {locale: translated name}}
+	*/
+	funcName := "SelectVocabularyTranslationByProductIdPK"
+	result := make(map[string]map[string]string)
+	if len(locales) == 0 {
+		return result
+	}
+	query := "SELECT " + vocabulary.Table + ".name, " + vocabulary.TranslationTable + ".locale, " +
+		vocabulary.TranslationTable + ".name FROM " + vocabulary.RelationTable +
+		" INNER JOIN " + vocabulary.Table +
+		" ON " + vocabulary.RelationTable + "." + vocabulary.Column + " = " + vocabulary.Table + ".id" +
+		" INNER JOIN " + vocabulary.TranslationTable +
+		" ON " + vocabulary.TranslationTable + "." + vocabulary.Column + " = " + vocabulary.Table + ".id" +
+		" WHERE " + vocabulary.RelationTable + ".product_id = " + strconv.Itoa(productIdPK) +
+		" AND " + vocabulary.TranslationTable + ".locale IN ("
+	for index, locale := range locales {
+		if index > 0 {
+			query += ", "
+		}
+		query += "'" + strings.Replace(locale, "'", "''", -1) + "'"
+	}
+	query += ")"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return result
+	}
+	defer selectQ.Close()
+	for selectQ.Next() {
+		var name, locale, translatedName string
+		if err := selectQ.Scan(&name, &locale, &translatedName); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			continue
+		}
+		if result[name] == nil {
+			result[name] = make(map[string]string)
+		}
+		result[name][locale] = translatedName
+	}
+	return result
+}
+
+func SelectFromVocabularyTranslation(ctx context.Context, vocabulary *Vocabulary) (map[int]map[string]string, bool) {
+	/*
+		To take a vocabulary and select translated names of all its entries
+		Return: map {id: {locale: translated name}}
+	*/
+	funcName := "SelectFromVocabularyTranslation"
+	query := "SELECT " + vocabulary.Column + ", locale, name FROM " + vocabulary.TranslationTable
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make(map[int]map[string]string)
+	for selectQ.Next() {
+		var id int
+		var locale, translatedName string
+		if err := selectQ.Scan(&id, &locale, &translatedName); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		if result[id] == nil {
+			result[id] = make(map[string]string)
+		}
+		result[id][locale] = translatedName
+	}
+	return result, true
+}
+
+func SelectFromImageByKeys(ctx context.Context, keyList []string) ([]*Image, bool) {
+	/*
+		To take a list of image keys and select the uploaded images stored under them
+	*/
+	funcName := "SelectFromImageByKeys"
+	if len(keyList) == 0 {
+		return []*Image{}, true
+	}
+	query := "SELECT id, image_key, thumbnail_key, content_type, width, height, size FROM image WHERE image_key IN ("
+	for index, key := range keyList {
+		if index > 0 {
+			query += ", "
+		}
+		query += "'" + strings.Replace(key, "'", "''", -1) + "'"
+	}
+	query += ")"
+	return selectImages(ctx, funcName, query)
+}
+
+func SelectFromImageCreatedBefore(ctx context.Context, createdBefore time.Time) ([]*Image, bool) {
+	/*
+		To select the images uploaded before createdBefore (in time zone of the server)
+	*/
+	funcName := "SelectFromImageCreatedBefore"
+	query := "SELECT id, image_key, thumbnail_key, content_type, width, height, size FROM image" +
+		" WHERE created_at < '" + createdBefore.Format("2006-01-02 15:04:05") + "' ORDER BY id"
+	return selectImages(ctx, funcName, query)
+}
+
+func selectImages(ctx context.Context, funcName string, query string) ([]*Image, bool) {
+	/*
+		To run a query selecting id, image_key, thumbnail_key, content_type, width, height, size of images
+	*/
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]*Image, 0)
+	for selectQ.Next() {
+		image := &Image{}
+		if err := selectQ.Scan(&image.Id, &image.Key, &image.ThumbnailKey, &image.ContentType, &image.Width,
+			&image.Height, &image.Size); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, image)
+	}
+	return result, true
+}
+
+func SelectImageReferenceCount(ctx context.Context, url string) (int, bool) {
+	/*
+		To take the url of an image and count the products (active or inactive), variants and drafts referring to it
+	*/
+	funcName := "SelectImageReferenceCount"
+	escapedURL := strings.Replace(url, "'", "''", -1)
+	query := "SELECT (SELECT COUNT(*) FROM product WHERE image_closed = '" + escapedURL + "' OR image_opened = '" +
+		escapedURL + "') + (SELECT COUNT(*) FROM product_variant WHERE packaging_image = '" + escapedURL + "')" +
+		" + (SELECT COUNT(*) FROM product_draft WHERE " + draftImageCondition(escapedURL) + ")"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	count := 0
+	if err := mysqlc.MySqlDB.QueryRowContext(queryCtx, query).Scan(&count); err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return 0, false
+	}
+	return count, true
+}
+
+func draftImageCondition(escapedURL string) string {
+	/*
+		To build the condition of drafts whose edits refer to an image by its url, escaped for the query
+		Drafts keep the edited ice cream data as json, so their images are read by the json keys of the request
+	*/
+	return "JSON_UNQUOTE(JSON_EXTRACT(data, '$.image_closed')) = '" + escapedURL + "'" +
+		" OR JSON_UNQUOTE(JSON_EXTRACT(data, '$.image_open')) = '" + escapedURL + "'"
+}
+
+func SelectImageURLFromProductById(ctx context.Context, txn *sql.Tx, id int) ([]string, bool) {
+	/*
+		To take id (primary key of product table) and select urls of its closed and open images inside a transaction
+		Empty urls are left out
+	*/
+	funcName := "SelectImageURLFromProductById"
+	query := "SELECT image_closed, image_opened FROM product WHERE id = " + strconv.Itoa(id)
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	var imageClosed, imageOpened sql.NullString
+	err := txn.QueryRowContext(queryCtx, query).Scan(&imageClosed, &imageOpened)
+	if err == sql.ErrNoRows {
+		return []string{}, true
+	} else if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	urls := make([]string, 0, 2)
+	for _, url := range []sql.NullString{imageClosed, imageOpened} {
+		if url.Valid && url.String != "" {
+			urls = append(urls, url.String)
+		}
+	}
+	return urls, true
+}
+
+func SelectFromProductMarketByProductIdPK(ctx context.Context, productIdPK int,
+	market string) ([]*structs.MarketStruct, bool) {
+	/*
+		To take product_id (primary key of product table) and select its markets with all their prices,
+		in order of market, currency and effective_from
+		Only the market given is selected, unless it's empty
+	*/
+	funcName := "SelectFromProductMarketByProductIdPK"
+	query := "SELECT pm.market, pm.available, pp.currency, pp.amount, pp.effective_from, pp.effective_to" +
+		" FROM product_market pm LEFT JOIN product_price pp" +
+		" ON pp.product_id = pm.product_id AND pp.market = pm.market" +
+		" WHERE pm.product_id = " + strconv.Itoa(productIdPK)
+	if market != "" {
+		query += " AND pm.market = '" + strings.Replace(market, "'", "''", -1) + "'"
+	}
+	query += " ORDER BY pm.market, pp.currency, pp.effective_from"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]*structs.MarketStruct, 0)
+	for selectQ.Next() {
+		var code string
+		var available int8
+		var currency, effectiveFrom, effectiveTo sql.NullString
+		var amount sql.NullInt64
+		if err := selectQ.Scan(&code, &available, &currency, &amount, &effectiveFrom, &effectiveTo); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		// Rows of a market follow each other, a market without prices has a single row of NULL prices
+		if len(result) == 0 || result[len(result)-1].Market != code {
+			result = append(result, &structs.MarketStruct{Market: code, Available: available == 1,
+				Prices: make([]*structs.PriceStruct, 0)})
+		}
+		if currency.Valid {
+			current := result[len(result)-1]
+			current.Prices = append(current.Prices, &structs.PriceStruct{Currency: currency.String,
+				Amount: amount.Int64, EffectiveFrom: effectiveFrom.String, EffectiveTo: effectiveTo.String})
+		}
+	}
+	return result, true
+}
+
+func SelectFromProductVariantBySku(ctx context.Context, txn *sql.Tx, sku string) (*Variant, bool) {
+	/*
+		To take the sku of a variant and select id, product_id, sku and packaging image of it inside a transaction
+		success: true, variant: nil, if the variant doesn't exist
+	*/
+	funcName := "SelectFromProductVariantBySku"
+	query := "SELECT id, product_id, sku, packaging_image FROM product_variant" +
+		" WHERE sku = '" + strings.Replace(sku, "'", "''", -1) + "' FOR UPDATE"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	variant := &Variant{}
+	var packagingImage sql.NullString
+	err := txn.QueryRowContext(queryCtx, query).Scan(&variant.Id, &variant.ProductId, &variant.Sku, &packagingImage)
+	if err == sql.ErrNoRows {
+		return nil, true
+	} else if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	variant.PackagingImage = packagingImage.String
+	return variant, true
+}
+
+func SelectPackagingImageFromProductVariantByProductIdPK(ctx context.Context, txn *sql.Tx,
+	productIdPK int) ([]string, bool) {
+	/*
+		To take product_id (primary key of product table) and select urls of packaging images of its variants
+		inside a transaction
+	*/
+	funcName := "SelectPackagingImageFromProductVariantByProductIdPK"
+	query := "SELECT DISTINCT packaging_image FROM product_variant WHERE product_id = " + strconv.Itoa(productIdPK) +
+		" AND packaging_image IS NOT NULL"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	urls := make([]string, 0)
+	for selectQ.Next() {
+		var url string
+		if err := selectQ.Scan(&url); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		urls = append(urls, url)
+	}
+	return urls, true
+}
+
+func SelectFromProductVariantByProductIdPK(ctx context.Context, productIdPK int) ([]*structs.VariantStruct, bool) {
+	/*
+		To take product_id (primary key of product table) and select its variants with their nutrition values,
+		in the order they were created
+	*/
+	funcName := "SelectFromProductVariantByProductIdPK"
+	query := "SELECT pv.sku, pv.name, pv.size, pv.size_unit, pv.packaging_image, pvn.nutrient, pvn.amount" +
+		" FROM product_variant pv LEFT JOIN product_variant_nutrition pvn ON pvn.variant_id = pv.id" +
+		" WHERE pv.product_id = " + strconv.Itoa(productIdPK) + " ORDER BY pv.id, pvn.nutrient"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]*structs.VariantStruct, 0)
+	for selectQ.Next() {
+		variant := &structs.VariantStruct{}
+		var packagingImage, nutrient sql.NullString
+		var amount sql.NullFloat64
+		if err := selectQ.Scan(&variant.Sku, &variant.Name, &variant.Size, &variant.SizeUnit, &packagingImage,
+			&nutrient, &amount); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		// Rows of a variant follow each other, a variant without nutrition values has a single row of NULL values
+		if len(result) == 0 || result[len(result)-1].Sku != variant.Sku {
+			variant.PackagingImage = packagingImage.String
+			variant.Nutrition = make(map[string]float64)
+			result = append(result, variant)
+		}
+		if nutrient.Valid {
+			result[len(result)-1].Nutrition[nutrient.String] = amount.Float64
+		}
+	}
+	return result, true
+}
+
+func SelectCategories(ctx context.Context) ([]*Category, bool) {
+	/*
+		To select all categories with the number of products in each of them
+	*/
+	funcName := "SelectCategories"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, categoryQuery())
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result, err := scanCategories(selectQ)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLSelectScanErrorMessage, err.Error())
+		return nil, false
+	}
+	return result, true
+}
+
+func SelectFromCategory(ctx context.Context, txn *sql.Tx) ([]*Category, bool) {
+	/*
+		To select all categories with the number of products in each of them inside a transaction,
+		locking them till the transaction ends, as a change to one may depend on all of its parents and children
+	*/
+	return selectCategories(ctx, txn, "SelectFromCategory", categoryQuery()+" FOR UPDATE")
+}
+
+func selectCategories(ctx context.Context, txn *sql.Tx, funcName string, query string) ([]*Category, bool) {
+	/*
+		To run a select query of categories inside the transaction
+	*/
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result, err := scanCategories(selectQ)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLSelectScanErrorMessage, err.Error())
+		return nil, false
+	}
+	return result, true
+}
+
+func categoryQuery() string {
+	/*
+		To build the query of all categories with the number of products in each, in order of name
+	*/
+	return "SELECT id, parent_id, name, name_key," +
+		" (SELECT COUNT(*) FROM product_category WHERE product_category.category_id = category.id)" +
+		" FROM category ORDER BY name, id"
+}
+
+func scanCategories(selectQ *sql.Rows) ([]*Category, error) {
+	/*
+		To scan rows of categories, a root category has parent id 0
+	*/
+	result := make([]*Category, 0)
+	for selectQ.Next() {
+		category := &Category{}
+		var parentId sql.NullInt64
+		if err := selectQ.Scan(&category.Id, &parentId, &category.Name, &category.NameKey,
+			&category.ProductCount); err != nil {
+			return nil, err
+		}
+		category.ParentId = int(parentId.Int64)
+		result = append(result, category)
+	}
+	return result, nil
+}
+
+func SelectCategoryPathByProductIdPK(ctx context.Context, productIdPK int) []string {
+	/*
+		To take product_id (primary key of product table) and select paths of its categories, in order
+	*/
+	funcName := "SelectCategoryPathByProductIdPK"
+	categories, success := SelectCategories(ctx)
+	if !success {
+		return make([]string, 0)
+	}
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, productCategoryQuery(productIdPK))
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return make([]string, 0)
+	}
+	defer selectQ.Close()
+	categoryIds, err := scanIds(selectQ)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLSelectScanErrorMessage, err.Error())
+	}
+	return categoryPathList(categories, categoryIds)
+}
+
+func SelectFromProductCategoryByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int) ([]string, bool) {
+	/*
+		To take product_id (primary key of product table) and select paths of its categories inside a transaction
+	*/
+	funcName := "SelectFromProductCategoryByProductIdPK"
+	// Categories are read without locking them, as they aren't changed by the transaction
+	categories, success := selectCategories(ctx, txn, funcName, categoryQuery())
+	if !success {
+		return nil, false
+	}
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, productCategoryQuery(productIdPK))
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	categoryIds, err := scanIds(selectQ)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLSelectScanErrorMessage, err.Error())
+		return nil, false
+	}
+	return categoryPathList(categories, categoryIds), true
+}
+
+func productCategoryQuery(productIdPK int) string {
+	/*
+		To build the query of ids of the categories of a product
+	*/
+	return "SELECT category_id FROM product_category WHERE product_id = " + strconv.Itoa(productIdPK)
+}
+
+func scanIds(selectQ *sql.Rows) ([]int, error) {
+	/*
+		To scan rows of a single id column
+	*/
+	result := make([]int, 0)
+	for selectQ.Next() {
+		var id int
+		if err := selectQ.Scan(&id); err != nil {
+			return result, err
+		}
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+func SelectProductIdPKByCategoryIds(ctx context.Context, txn *sql.Tx, categoryIds []int) ([]int, bool) {
+	/*
+		To take ids of categories and select ids (primary key) of all products in any of them
+	*/
+	funcName := "SelectProductIdPKByCategoryIds"
+	if len(categoryIds) == 0 {
+		return make([]int, 0), true
+	}
+	query := "SELECT DISTINCT product_id FROM product_category WHERE category_id IN (" + joinIds(categoryIds) + ")" +
+		" ORDER BY product_id"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result, err := scanIds(selectQ)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLSelectScanErrorMessage, err.Error())
+		return nil, false
+	}
+	return result, true
+}
+
+func SelectFromProductByCategoryAndTag(ctx context.Context, categoryIds []int, tag string) ([]*Product, bool) {
+	/*
+		To take ids of categories and/or a tag name and select live products in any of the categories
+		and carrying the tag, nil categoryIds or an empty tag doesn't filter by it
+		Tags are matched by their normalized key, e.g. "VEGAN " selects products tagged "Vegan"
+	*/
+	funcName := "SelectFromProductByCategoryAndTag"
+	query := "SELECT id, product_id, name FROM product WHERE " + liveProductCondition(time.Now()) +
+		categoryAndTagCondition(categoryIds, tag) + " ORDER BY product_id"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]*Product, 0)
+	for selectQ.Next() {
+		product := &Product{}
+		if err := selectQ.Scan(&product.Id, &product.ProductId, &product.Name); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, product)
+	}
+	return result, true
+}
+
+func categoryAndTagCondition(categoryIds []int, tag string) string {
+	/*
+		To build the conditions, each starting with AND, of products in any of the categories and carrying the tag
+		nil categoryIds or an empty tag doesn't add a condition
+	*/
+	condition := ""
+	if categoryIds != nil {
+		condition += " AND EXISTS (SELECT 1 FROM product_category WHERE product_category.product_id = product.id" +
+			" AND product_category.category_id IN (" + joinIds(categoryIds) + "))"
+	}
+	if tag != "" {
+		condition += " AND EXISTS (SELECT 1 FROM product_tag INNER JOIN tag ON product_tag.tag_id = tag.id" +
+			" WHERE product_tag.product_id = product.id" +
+			" AND tag.name_key = '" + strings.Replace(normalizer.Key(tag), "'", "''", -1) + "')"
+	}
+	return condition
+}
+
+func SelectFacetCounts(ctx context.Context, vocabulary *Vocabulary, condition string) ([]*PropertyUsage, bool) {
+	/*
+		To take a vocabulary and a condition of products and select id, name, name_key of the entries used by
+		products meeting the condition with the number of those products, grouped in a single query
+		Entries not used by any of those products aren't selected
+	*/
+	funcName := "SelectFacetCounts"
+	query := "SELECT " + vocabulary.Table + ".id, " + vocabulary.Table + ".name, " + vocabulary.Table + ".name_key" +
+		", COUNT(DISTINCT product.id) FROM product" +
+		" INNER JOIN " + vocabulary.RelationTable + " ON " + vocabulary.RelationTable + ".product_id = product.id" +
+		" INNER JOIN " + vocabulary.Table +
+		" ON " + vocabulary.Table + ".id = " + vocabulary.RelationTable + "." + vocabulary.Column +
+		" WHERE " + condition +
+		" GROUP BY " + vocabulary.Table + ".id, " + vocabulary.Table + ".name, " + vocabulary.Table + ".name_key" +
+		" ORDER BY " + vocabulary.Table + ".name"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]*PropertyUsage, 0)
+	for selectQ.Next() {
+		propertyUsage := &PropertyUsage{}
+		if err := selectQ.Scan(&propertyUsage.Id, &propertyUsage.Name, &propertyUsage.NameKey,
+			&propertyUsage.ProductCount); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, propertyUsage)
+	}
+	return result, true
+}
+
+func SelectLiveProductVocabularyIds(ctx context.Context, vocabulary *Vocabulary) ([]*ProductProperty, bool) {
+	/*
+		To take a vocabulary and select ids (primary key) of live products with ids of the entries they use
+	*/
+	funcName := "SelectLiveProductVocabularyIds"
+	query := "SELECT " + vocabulary.RelationTable + ".product_id, " + vocabulary.RelationTable + "." +
+		vocabulary.Column + " FROM " + vocabulary.RelationTable +
+		" INNER JOIN product ON product.id = " + vocabulary.RelationTable + ".product_id" +
+		" WHERE " + liveProductCondition(time.Now())
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]*ProductProperty, 0)
+	for selectQ.Next() {
+		productProperty := &ProductProperty{}
+		if err := selectQ.Scan(&productProperty.ProductId, &productProperty.PropertyId); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, productProperty)
+	}
+	return result, true
+}
+
+func SelectProductCountByCondition(ctx context.Context, condition string) (int, bool) {
+	/*
+		To take a condition of products and select the number of products meeting it
+	*/
+	funcName := "SelectProductCountByCondition"
+	query := "SELECT COUNT(*) FROM product WHERE " + condition
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	count := 0
+	if err := mysqlc.MySqlDB.QueryRowContext(queryCtx, query).Scan(&count); err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return 0, false
+	}
+	return count, true
+}
+
+func joinIds(ids []int) string {
+	/*
+		To join ids for an IN clause, e.g. "1, 2, 3"
+	*/
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.Itoa(id))
+	}
+	return strings.Join(values, ", ")
+}
+
+func liveProductCondition(at time.Time) string {
+	/*
+		To build the condition of products live at a time: active, published at or before it (or since ever)
+		and not unpublished at or before it (or never)
+	*/
+	moment := "'" + at.UTC().Format(constants.ScheduleTimeLayout) + "'"
+	return "is_inactive = 0 AND (publish_at IS NULL OR publish_at <= " + moment + ")" +
+		" AND (unpublish_at IS NULL OR unpublish_at > " + moment + ")"
+}
+
+func SelectDueScheduleEvents(ctx context.Context, txn *sql.Tx, at time.Time) ([]*ScheduleEvent, bool) {
+	/*
+		To select events of active products that went live or expired at or before a time and haven't been emitted
+		for their scheduled time yet, locking the products till the transaction ends
+		Products locked by another transaction (e.g. another server emitting their events) are skipped
+		A product whose publish_at and unpublish_at both passed only expires, a product never live doesn't
+	*/
+	moment := "'" + at.UTC().Format(constants.ScheduleTimeLayout) + "'"
+	published, success := selectDueScheduleEvents(ctx, txn, constants.ScheduleEventProductPublished, "publish_at",
+		"publish_at <= "+moment+" AND (unpublish_at IS NULL OR unpublish_at > "+moment+")")
+	if !success {
+		return nil, false
+	}
+	unpublished, success := selectDueScheduleEvents(ctx, txn, constants.ScheduleEventProductUnpublished,
+		"unpublish_at", "unpublish_at <= "+moment+" AND (publish_at IS NULL OR publish_at < unpublish_at)")
+	if !success {
+		return nil, false
+	}
+	result := append(published, unpublished...)
+	// times formatted alike compare in order as strings
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ScheduledAt < result[j].ScheduledAt
+	})
+	return result, true
+}
+
+func selectDueScheduleEvents(ctx context.Context, txn *sql.Tx, event string, column string,
+	condition string) ([]*ScheduleEvent, bool) {
+	/*
+		To select due events of one kind, scheduled at the column of the product
+	*/
+	funcName := "SelectDueScheduleEvents"
+	query := "SELECT id, product_id, name, " + column + " FROM product WHERE is_inactive = 0 AND " + condition +
+		" AND NOT EXISTS (SELECT 1 FROM product_schedule_event WHERE product_schedule_event.product_id = product.id" +
+		" AND product_schedule_event.event = '" + event + "'" +
+		" AND product_schedule_event.scheduled_at = product." + column + ")" +
+		" ORDER BY " + column + ", id FOR UPDATE SKIP LOCKED"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]*ScheduleEvent, 0)
+	for selectQ.Next() {
+		scheduleEvent := &ScheduleEvent{Event: event}
+		if err := selectQ.Scan(&scheduleEvent.ProductIdPK, &scheduleEvent.ProductId, &scheduleEvent.Name,
+			&scheduleEvent.ScheduledAt); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, scheduleEvent)
+	}
+	return result, true
+}
+
+func SelectPendingScheduleEvents(ctx context.Context) ([]*ScheduleEvent, bool) {
+	/*
+		To select recorded events of scheduled products that haven't been emitted yet, in order of scheduled time
+	*/
+	funcName := "SelectPendingScheduleEvents"
+	query := "SELECT pse.product_id, product.product_id, product.name, pse.event, pse.scheduled_at" +
+		" FROM product_schedule_event pse INNER JOIN product ON pse.product_id = product.id" +
+		" WHERE pse.emitted_at IS NULL ORDER BY pse.scheduled_at, pse.product_id, pse.event"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := mysqlc.MySqlDB.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	result := make([]*ScheduleEvent, 0)
+	for selectQ.Next() {
+		scheduleEvent := &ScheduleEvent{}
+		if err := selectQ.Scan(&scheduleEvent.ProductIdPK, &scheduleEvent.ProductId, &scheduleEvent.Name,
+			&scheduleEvent.Event, &scheduleEvent.ScheduledAt); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+		result = append(result, scheduleEvent)
+	}
+	return result, true
+}
+
+func SelectFromProductDraftByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int) (*Draft, bool) {
+	/*
+		To take product_id (primary key of product table) and select its draft inside a transaction,
+		locking it till the transaction ends, as changes to a draft depend on its status
+		success: true, draft: nil, if the product has no draft
+	*/
+	funcName := "SelectFromProductDraftByProductIdPK"
+	query := "SELECT product_id, data, fields, status, updated_at FROM product_draft" +
+		" WHERE product_id = " + strconv.Itoa(productIdPK) + " FOR UPDATE"
+	queryCtx, cancel := queryContext(ctx)
+	defer cancel()
+	selectQ, err := txn.QueryContext(queryCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return nil, false
+	}
+	defer selectQ.Close()
+	var draft *Draft
+	for selectQ.Next() {
+		draft = &Draft{}
+		if err := selectQ.Scan(&draft.ProductId, &draft.Data, &draft.Fields, &draft.Status,
+			&draft.UpdatedAt); err != nil {
+			logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+				constants.MySQLSelectScanErrorMessage, err.Error())
+			return nil, false
+		}
+	}
+	return draft, true
+}
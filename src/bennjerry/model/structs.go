@@ -2,27 +2,179 @@ package model
 
 // Used to define schema of table product
 type Product struct {
-	Allergy                string
-	Description            string
-	ImageClosed            string
-	ImageOpened            string
-	Name                   string
-	ProductId              string
-	Story                  string
-	DietaryCertificationId int
-	Id                     int
-	IsInActive             int8
-}
-
-// Used to define schema of tables sourcingvalue, ingredient, dietarycertification
+	Allergy     string
+	Description string
+	ImageClosed string
+	ImageOpened string
+	Name        string
+	ProductId   string
+	Story       string
+	Id          int
+	IsInActive  int8
+	// 1, if allergens of the product have been declared in product_allergen (even as none)
+	AllergensDeclared int8
+	// empty, if the product is live since ever or for ever
+	PublishAt   string
+	UnpublishAt string
+}
+
+// Used to define schema of table product_schedule_event, with product_id and name of the product
+type ScheduleEvent struct {
+	Event       string
+	Name        string
+	ProductId   string
+	ScheduledAt string
+	ProductIdPK int
+}
+
+// Used to define schema of table product_draft
+type Draft struct {
+	// edited fields of the product as json of ice cream data, and their names separated by commas
+	Data      string
+	Fields    string
+	Status    string
+	UpdatedAt string
+	ProductId int
+}
+
+// Outcome of a change to the draft of a product
+type DraftResult int
+
+const (
+	DraftChanged DraftResult = iota
+	DraftNotFound
+	DraftInvalidStatus
+	DraftError
+)
+
+// Used to define schema of table allergen
+type Allergen struct {
+	Code string
+	Name string
+	Id   int
+}
+
+// Used to define schema of table image
+type Image struct {
+	ContentType  string
+	Key          string
+	ThumbnailKey string
+	Height       int
+	Id           int
+	Size         int
+	Width        int
+}
+
+// Used to define schema of table product_variant
+type Variant struct {
+	PackagingImage string
+	Sku            string
+	Id             int
+	ProductId      int
+}
+
+// Outcome of a change to a variant of a product
+type VariantResult int
+
+const (
+	VariantChanged VariantResult = iota
+	VariantNotFound
+	VariantSkuExists
+	VariantError
+)
+
+// Used to define schema of table category
+type Category struct {
+	Name    string
+	NameKey string
+	Id      int
+	// 0 for a root category
+	ParentId     int
+	ProductCount int
+}
+
+// Outcome of a change to a category
+type CategoryResult int
+
+const (
+	CategoryChanged CategoryResult = iota
+	CategoryNotFound
+	CategoryNameExists
+	CategoryInvalid
+	CategoryInUse
+	CategoryError
+)
+
+// Used to define schema of tables sourcingvalue, ingredient, dietarycertification, tag
 type Property struct {
 	Id   int
 	Name string
 }
 
+// Property with the number of products using it
+type PropertyUsage struct {
+	Id           int
+	Name         string
+	NameKey      string
+	ProductCount int
+}
+
+// Used to define a vocabulary of properties (e.g. ingredient) and its relation table with product
+type Vocabulary struct {
+	// column of the relation table referring to the property, e.g. ingredient_id
+	Column        string
+	RelationTable string
+	Table         string
+	// table of translated names, referring to the property by Column as well
+	TranslationTable string
+}
+
+// Filters of the products counted by facets, products must match every filter given
+type FacetFilter struct {
+	// id of a category, products in it or any of its subcategories match, 0 doesn't filter by it
+	CategoryId int
+	// codes of allergens products must be declared free from
+	FreeFrom []string
+	// names of entries products must use, by the name of the vocabulary's field, e.g. "ingredients"
+	Properties map[string][]string
+	Tag        string
+}
+
+// Outcome of a change to an entry of a vocabulary
+type VocabularyResult int
+
+const (
+	VocabularyChanged VocabularyResult = iota
+	VocabularyNotFound
+	VocabularyNameExists
+	VocabularyInUse
+	VocabularyError
+)
+
 // Used for relation table of product and its property (e.g. sourcingvalue, ingredient, dietarycertification)
 type ProductProperty struct {
 	ProductId    int
 	PropertyId   int
 	PropertyName string
 }
+
+// Used to define schema of table product_revision
+type ProductRevision struct {
+	CreatedAt string
+	Snapshot  string
+	Id        int
+	ProductId int
+	Revision  int
+}
+
+// Names of sourcing values, ingredients, dietary certifications and tags not used by any product
+type UnUsedProperties struct {
+	DietaryCertifications []string
+	Ingredients           []string
+	SourcingValues        []string
+	Tags                  []string
+	// urls of uploaded images not referenced by any product
+	Images []string
+	// Number of rows deleted from each vocabulary table, by table, none with dryRun
+	RowsDeleted map[string]int64
+}
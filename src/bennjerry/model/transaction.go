@@ -0,0 +1,191 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"constants"
+	"logger"
+	"metrics"
+	"mysqlc"
+	"utils"
+)
+
+var (
+	// Transactions begun and not yet committed or rolled back, waited for while shutting down
+	// Each one is mapped to the cancel function of its context
+	openTransactions      = make(map[*sql.Tx]context.CancelFunc)
+	openTransactionsMutex sync.Mutex
+	openTransactionsWg    sync.WaitGroup
+	// Set under openTransactionsMutex once shutting down, no transaction is begun after it
+	closingTransactions    bool
+	errTransactionsClosing = errors.New(constants.MySQLTransactionClosingMessage)
+	// Deadlines for a single query and for a whole transaction, 0 disables the deadline
+	queryTimeout       = utils.GetEnvSeconds(constants.MySQLQueryTimeoutEnvVarName, constants.MySQLQueryTimeout)
+	transactionTimeout = utils.GetEnvSeconds(constants.MySQLTransactionTimeoutEnvVarName,
+		constants.MySQLTransactionTimeout)
+	// Number of changes to the catalog committed by this server, for in-process caches to tell they're stale
+	catalogVersion uint64
+)
+
+func CatalogVersion() uint64 {
+	/*
+		To read the version of the catalog, which changes whenever this server commits a change to it
+		Changes committed by other servers or products going live as per their schedule don't change it
+	*/
+	return atomic.LoadUint64(&catalogVersion)
+}
+
+func catalogChanged() {
+	/*
+		To mark the catalog changed, after committing a change to it
+	*/
+	atomic.AddUint64(&catalogVersion, 1)
+}
+
+func queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	/*
+		To derive the context of a single query from ctx, bounded by the query timeout
+		Query is cancelled when ctx is done, e.g. the client has disconnected
+	*/
+	if queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
+func beginTransaction(ctx context.Context) (context.Context, *sql.Tx, error) {
+	/*
+		To begin a transaction which is tracked till it's committed or rolled back
+		Returns the context, bounded by the transaction timeout, to be used for all queries of the transaction
+		Transaction is rolled back by database/sql as soon as this context is done
+		Returns an error once WaitForTransactions has been called, as the server is shutting down
+	*/
+	var (
+		txnCtx context.Context
+		cancel context.CancelFunc
+	)
+	if transactionTimeout <= 0 {
+		txnCtx, cancel = context.WithCancel(ctx)
+	} else {
+		txnCtx, cancel = context.WithTimeout(ctx, transactionTimeout)
+	}
+	txn, err := mysqlc.MySqlDB.BeginTx(txnCtx, nil)
+	if err != nil {
+		cancel()
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+"beginTransaction",
+			constants.MySQLBeginErrorMessage, err.Error())
+		return ctx, nil, err
+	}
+	openTransactionsMutex.Lock()
+	if closingTransactions {
+		// Checked along with adding to openTransactionsWg, so that no transaction is added while it's waited for
+		// Cancelling its context rolls back the transaction just begun
+		openTransactionsMutex.Unlock()
+		cancel()
+		logger.FromContext(ctx).Warn(constants.MySQLLogBucketName, logIdentifier+"beginTransaction",
+			constants.MySQLTransactionClosingMessage)
+		return ctx, nil, errTransactionsClosing
+	}
+	openTransactions[txn] = cancel
+	openTransactionsWg.Add(1)
+	openTransactionsMutex.Unlock()
+	return txnCtx, txn, nil
+}
+
+func WaitForTransactions(ctx context.Context) bool {
+	/*
+		To wait till all open transactions are committed or rolled back, or ctx is done
+		Returns false, if ctx was done before all transactions finished, these are then cancelled
+		No transaction can be begun once it's called
+	*/
+	openTransactionsMutex.Lock()
+	closingTransactions = true
+	openTransactionsMutex.Unlock()
+	done := make(chan struct{})
+	go func() {
+		openTransactionsWg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		openTransactionsMutex.Lock()
+		for _, cancel := range openTransactions {
+			cancel()
+		}
+		openTransactionsMutex.Unlock()
+		return false
+	}
+}
+
+func finishTransaction(txn *sql.Tx) {
+	/*
+		To stop tracking a transaction and release its context
+		Calling it more than once for the same transaction has no effect
+	*/
+	openTransactionsMutex.Lock()
+	defer openTransactionsMutex.Unlock()
+	if cancel, isOpen := openTransactions[txn]; isOpen {
+		cancel()
+		delete(openTransactions, txn)
+		openTransactionsWg.Done()
+	}
+}
+
+func commitTransaction(ctx context.Context, txn *sql.Tx, operation string) bool {
+	/*
+		To commit a transaction and count it by operation (e.g. insert, update, drop)
+	*/
+	funcName := "commitTransaction"
+	defer finishTransaction(txn)
+	if err := txn.Commit(); err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLCommitErrorMessage, err.Error())
+		metrics.MySQLTransactions.WithLabels(operation, constants.MySQLTransactionCommitFailed).Inc()
+		return false
+	}
+	metrics.MySQLTransactions.WithLabels(operation, constants.MySQLTransactionCommitted).Inc()
+	catalogChanged()
+	return true
+}
+
+func rollbackTransaction(ctx context.Context, txn *sql.Tx, operation string) {
+	/*
+		To roll back a transaction and count it by operation (e.g. insert, update, drop)
+	*/
+	funcName := "rollbackTransaction"
+	defer finishTransaction(txn)
+	// Transaction is already rolled back by database/sql, if its context is done
+	if err := txn.Rollback(); err != nil && err != sql.ErrTxDone {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLRollbackErrorMessage, err.Error())
+	}
+	if ctx.Err() != nil {
+		logger.FromContext(ctx).Warn(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLTransactionCancelledMessage+": "+ctx.Err().Error())
+		metrics.MySQLTransactions.WithLabels(operation, constants.MySQLTransactionCancelled).Inc()
+		return
+	}
+	metrics.MySQLTransactions.WithLabels(operation, constants.MySQLTransactionRolledBack).Inc()
+}
+
+func rollbackOnPanic(ctx context.Context, txn *sql.Tx, operation string) {
+	/*
+		To be deferred right after beginning a transaction
+		Rolls the transaction back if a panic occurs before it's committed, and re-raises the panic
+	*/
+	if r := recover(); r != nil {
+		openTransactionsMutex.Lock()
+		_, isOpen := openTransactions[txn]
+		openTransactionsMutex.Unlock()
+		if isOpen {
+			rollbackTransaction(ctx, txn, operation)
+		}
+		panic(r)
+	}
+}
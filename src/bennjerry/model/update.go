@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"database/sql"
 	"strconv"
 	"strings"
@@ -10,10 +11,13 @@ import (
 	"bennjerry/structs"
 	"constants"
 	"logger"
+	"metrics"
 	"mysqlc"
+	"normalizer"
 )
 
-func UpdateProductById(txn *sql.Tx, id int, iceCreamData *structs.IceCreamDataStruct, fieldsMap map[string]bool) bool {
+func UpdateProductById(ctx context.Context, txn *sql.Tx, id int, iceCreamData *structs.IceCreamDataStruct,
+	fieldsMap map[string]bool) bool {
 	/*
 		To take ice cream data and update fields in product table based on map {fieldName: true}
 	*/
@@ -32,104 +36,363 @@ func UpdateProductById(txn *sql.Tx, id int, iceCreamData *structs.IceCreamDataSt
 		query += " image_closed = '" + strings.Replace(iceCreamData.ImageClosed, "'", "''", -1) + "',"
 	}
 	if _, exists := fieldsMap["image_open"]; exists {
-		query += " image_open = '" + strings.Replace(iceCreamData.ImageOpened, "'", "''", -1) + "',"
+		query += " image_opened = '" + strings.Replace(iceCreamData.ImageOpened, "'", "''", -1) + "',"
 	}
 	if _, exists := fieldsMap["allergy_info"]; exists {
-		query += " allergy_info = '" + strings.Replace(iceCreamData.AllergyInfo, "'", "''", -1) + "',"
+		query += " allergy = '" + strings.Replace(iceCreamData.AllergyInfo, "'", "''", -1) + "',"
 	}
-	if _, exists := fieldsMap["dietary_certifications"]; exists {
-		if iceCreamData.DietaryCertifications != "" {
-			nameMap := map[string]bool{
-				iceCreamData.DietaryCertifications: true,
-			}
-			success := InsertIntoDietaryCertification(txn, nameMap)
-			if !success {
-				return false
-			}
-			dietaryCertificationId := SelectFromDietaryCertification(txn, []string{iceCreamData.DietaryCertifications})
-			query += " dietary_certification_id = '" + strconv.Itoa(dietaryCertificationId[0].Id) + "',"
+	if _, exists := fieldsMap["allergens"]; exists {
+		if iceCreamData.Allergens != nil {
+			query += " allergens_declared = 1,"
 		} else {
-			query += " dietary_certification_id = NULL,"
+			query += " allergens_declared = 0,"
 		}
 	}
+	if _, exists := fieldsMap["publish_at"]; exists {
+		query += " publish_at = NULLIF('" + strings.Replace(iceCreamData.PublishAt, "'", "''", -1) + "', ''),"
+	}
+	if _, exists := fieldsMap["unpublish_at"]; exists {
+		query += " unpublish_at = NULLIF('" + strings.Replace(iceCreamData.UnpublishAt, "'", "''", -1) + "', ''),"
+	}
+	if query == "UPDATE product SET" {
+		// none of the requested fields are stored in product table
+		return true
+	}
 	query = strings.TrimSuffix(query, ",")
 	query += " WHERE id = " + strconv.Itoa(id)
-	_, err := txn.Exec(query)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
 	}
 	return true
 }
 
-func UpdateProductSourcingValueByProductIdPK(txn *sql.Tx, productIdPK int, nameMap map[string]bool) bool {
+func UpdateProductSourcingValueByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int,
+	nameMap map[string]bool) bool {
 	/*
 		Take product_id (primary key of product table) and map {name: true}
 		and update data in product_sourcingvalue table
+		Names are compared by the entries they select, so that e.g. "milk " keeps a product's "Milk"
 	*/
-	productPropertyData := SelectFromProductSourcingValueByProductIdPK(txn, productIdPK)
-	existingNameMap := make(map[string][]int)
-	for _, productProperty := range productPropertyData {
-		if exists := nameMap[productProperty.PropertyName]; !exists {
-			success := DeleteFromProductSourcingValueById(txn, productProperty.ProductId, productProperty.PropertyId)
+	nameList := make([]string, 0, len(nameMap))
+	for name := range nameMap {
+		nameList = append(nameList, name)
+	}
+	// Names are already inserted in the property table, fetching their ids to compare with the relation
+	newIdMap := make(map[int]bool)
+	for _, property := range SelectFromSourcingValue(ctx, txn, nameList) {
+		newIdMap[property.Id] = true
+	}
+	productProperties, success := SelectFromProductSourcingValueByProductIdPK(ctx, txn, productIdPK)
+	if !success {
+		return false
+	}
+	for _, productProperty := range productProperties {
+		if !newIdMap[productProperty.PropertyId] {
+			success := DeleteFromProductSourcingValueById(ctx, txn, productProperty.ProductId, productProperty.PropertyId)
 			if !success {
 				return false
 			}
 		} else {
-			existingNameMap[productProperty.PropertyName] = []int{productProperty.ProductId, productProperty.PropertyId}
+			delete(newIdMap, productProperty.PropertyId)
 		}
 	}
-	for name := range nameMap {
-		if idList, exists := existingNameMap[name]; !exists {
-			success := InsertToProductSourcingValueById(txn, idList[0], idList[1])
-			if !success {
-				return false
-			}
+	for id := range newIdMap {
+		success := InsertToProductSourcingValueById(ctx, txn, productIdPK, id)
+		if !success {
+			return false
 		}
 	}
 	return true
 }
 
-func UpdateProductIngredientByProductIdPK(txn *sql.Tx, productIdPK int, nameMap map[string]bool) bool {
+func UpdateProductIngredientByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int,
+	ingredients structs.IngredientList) bool {
+	/*
+		Take product_id (primary key of product table) and ordered list of ingredients
+		and replace data of the product in product_ingredient table, as order and percentages may have changed too
+	*/
+	success := DeleteFromProductIngredientByProductIdPK(ctx, txn, productIdPK)
+	if !success {
+		return false
+	}
+	if len(ingredients) > 0 {
+		// Names are already inserted in the property table, fetching their ids to create the relation
+		return InsertIntoProductIngredient(ctx, txn, productIdPK, ingredients)
+	}
+	return true
+}
+
+func UpdateProductDietaryCertificationByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int,
+	nameMap map[string]bool) bool {
 	/*
 		Take product_id (primary key of product table) and map {name: true}
-		and update data in product_ingredient table
+		and update data in product_dietarycertification table
+		Names are compared by the entries they select, so that e.g. "milk " keeps a product's "Milk"
 	*/
-	productPropertyData := SelectFromProductIngredientByProductIdPK(txn, productIdPK)
-	existingNameMap := make(map[string][]int)
-	for _, productProperty := range productPropertyData {
-		if _, exists := nameMap[productProperty.PropertyName]; !exists {
-			success := DeleteFromProductIngredientById(txn, productProperty.ProductId, productProperty.PropertyId)
+	nameList := make([]string, 0, len(nameMap))
+	for name := range nameMap {
+		nameList = append(nameList, name)
+	}
+	// Names are already inserted in the property table, fetching their ids to compare with the relation
+	newIdMap := make(map[int]bool)
+	for _, property := range SelectFromDietaryCertification(ctx, txn, nameList) {
+		newIdMap[property.Id] = true
+	}
+	productProperties, success := SelectFromProductDietaryCertificationByProductIdPK(ctx, txn, productIdPK)
+	if !success {
+		return false
+	}
+	for _, productProperty := range productProperties {
+		if !newIdMap[productProperty.PropertyId] {
+			success := DeleteFromProductDietaryCertificationById(ctx, txn, productProperty.ProductId,
+				productProperty.PropertyId)
 			if !success {
 				return false
 			}
 		} else {
-			existingNameMap[productProperty.PropertyName] = []int{productProperty.ProductId, productProperty.PropertyId}
+			delete(newIdMap, productProperty.PropertyId)
+		}
+	}
+	for id := range newIdMap {
+		success := InsertToProductDietaryCertificationById(ctx, txn, productIdPK, id)
+		if !success {
+			return false
 		}
 	}
+	return true
+}
+
+func UpdateProductTagByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int, nameMap map[string]bool) bool {
+	/*
+		Take product_id (primary key of product table) and map {name: true}
+		and update data in product_tag table
+		Names are compared by the entries they select, so that e.g. "vegan " keeps a product's "Vegan"
+	*/
+	nameList := make([]string, 0, len(nameMap))
 	for name := range nameMap {
-		if idList, exists := existingNameMap[name]; !exists {
-			success := InsertToProductIngredientById(txn, idList[0], idList[1])
+		nameList = append(nameList, name)
+	}
+	// Names are already inserted in the property table, fetching their ids to compare with the relation
+	newIdMap := make(map[int]bool)
+	for _, property := range SelectFromTag(ctx, txn, nameList) {
+		newIdMap[property.Id] = true
+	}
+	productProperties, success := SelectFromProductTagByProductIdPK(ctx, txn, productIdPK)
+	if !success {
+		return false
+	}
+	for _, productProperty := range productProperties {
+		if !newIdMap[productProperty.PropertyId] {
+			success := DeleteFromProductTagById(ctx, txn, productProperty.ProductId, productProperty.PropertyId)
 			if !success {
 				return false
 			}
+		} else {
+			delete(newIdMap, productProperty.PropertyId)
+		}
+	}
+	for id := range newIdMap {
+		success := InsertToProductTagById(ctx, txn, productIdPK, id)
+		if !success {
+			return false
 		}
 	}
 	return true
 }
 
-func UpdateProductIsInActiveById(id int) (int, bool) {
+func UpdateProductCategoryByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int, categoryIds []int) bool {
+	/*
+		Take product_id (primary key of product table) and ids of its categories
+		and replace data of the product in product_category table
+	*/
+	success := DeleteFromProductCategoryByProductIdPK(ctx, txn, productIdPK)
+	if !success {
+		return false
+	}
+	return InsertIntoProductCategory(ctx, txn, productIdPK, categoryIds)
+}
+
+func UpdateProductIsInActiveById(ctx context.Context, id int) (int, bool) {
 	/*
 		Take product_id and update is_inactive = 1 in product table
 	*/
 	funcName := "UpdateProductIsInActiveById"
 	query := "UPDATE product SET is_inactive = 1 WHERE id = " + strconv.Itoa(id)
-	_, err := mysqlc.MySqlDB.Exec(query)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := mysqlc.MySqlDB.ExecContext(queryCtx, query)
+	cancel()
 	if err != nil {
-		logger.ZaloraStatsLogger.Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
 			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
 	} else {
+		catalogChanged()
 		return id, true
 	}
 	return 0, false
 }
+
+func UpdateProductAllergenByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int,
+	allergens []*structs.AllergenStruct) bool {
+	/*
+		Take product_id (primary key of product table) and list of allergens
+		and replace data of the product in product_allergen table, as a level may have changed too
+	*/
+	success := DeleteFromProductAllergenByProductIdPK(ctx, txn, productIdPK)
+	if !success {
+		return false
+	}
+	if len(allergens) > 0 {
+		return InsertIntoProductAllergen(ctx, txn, productIdPK, allergens)
+	}
+	return true
+}
+
+func UpdateProductNutritionByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int,
+	nutrition *structs.NutritionStruct) bool {
+	/*
+		Take product_id (primary key of product table) and its nutrition facts (nil, if not declared)
+		and replace data of the product in product_nutrition and product_nutrient tables
+	*/
+	success := DeleteFromProductNutritionByProductIdPK(ctx, txn, productIdPK)
+	if !success {
+		return false
+	}
+	if nutrition != nil {
+		return InsertIntoProductNutrition(ctx, txn, productIdPK, nutrition)
+	}
+	return true
+}
+
+func UpdateVocabularyNameById(ctx context.Context, txn *sql.Tx, vocabulary *Vocabulary, id int, name string) bool {
+	/*
+		Take a vocabulary, id and name and update name of the entry, normalized, along with its key
+	*/
+	funcName := "UpdateVocabularyNameById"
+	query := "UPDATE " + vocabulary.Table +
+		" SET name = '" + strings.Replace(normalizer.Name(name), "'", "''", -1) + "'" +
+		", name_key = '" + strings.Replace(normalizer.Key(name), "'", "''", -1) + "'" +
+		" WHERE id = " + strconv.Itoa(id)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func UpdateProductVocabularyIdByVocabularyId(ctx context.Context, txn *sql.Tx, vocabulary *Vocabulary, id int,
+	newId int) bool {
+	/*
+		Take a vocabulary and ids of two entries and move products from the first entry to the second
+		in the relation table, rows of products already using the second entry are left unchanged
+	*/
+	funcName := "UpdateProductVocabularyIdByVocabularyId"
+	query := "UPDATE IGNORE " + vocabulary.RelationTable + " SET " + vocabulary.Column + " = " + strconv.Itoa(newId) +
+		" WHERE " + vocabulary.Column + " = " + strconv.Itoa(id)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func UpdateProductVariantById(ctx context.Context, txn *sql.Tx, id int, variant *structs.VariantStruct) bool {
+	/*
+		Take id of a variant and a (validated) variant and update its name, size and packaging image
+	*/
+	funcName := "UpdateProductVariantById"
+	query := "UPDATE product_variant" +
+		" SET name = '" + strings.Replace(variant.Name, "'", "''", -1) + "'" +
+		", size = " + strconv.FormatFloat(variant.Size, 'f', 2, 64) +
+		", size_unit = '" + strings.Replace(variant.SizeUnit, "'", "''", -1) + "'" +
+		", packaging_image = NULLIF('" + strings.Replace(variant.PackagingImage, "'", "''", -1) + "', '')" +
+		" WHERE id = " + strconv.Itoa(id)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func UpdateCategoryById(ctx context.Context, txn *sql.Tx, id int, parentId int, name string) bool {
+	/*
+		Take id of a category, id of its parent (0 for a root category) and a name
+		and update its parent and name, normalized, along with its key
+	*/
+	funcName := "UpdateCategoryById"
+	query := "UPDATE category SET parent_id = " + categoryParentValue(parentId) +
+		", name = '" + strings.Replace(normalizer.Name(name), "'", "''", -1) + "'" +
+		", name_key = '" + strings.Replace(normalizer.Key(name), "'", "''", -1) + "'" +
+		" WHERE id = " + strconv.Itoa(id)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func UpdateProductScheduleEventEmitted(ctx context.Context, scheduleEvent *ScheduleEvent, emittedAt string) bool {
+	/*
+		To take a recorded event of a scheduled product and mark it emitted at a time
+	*/
+	funcName := "UpdateProductScheduleEventEmitted"
+	query := "UPDATE product_schedule_event SET emitted_at = '" + strings.Replace(emittedAt, "'", "''", -1) + "'" +
+		" WHERE product_id = " + strconv.Itoa(scheduleEvent.ProductIdPK) +
+		" AND event = '" + strings.Replace(scheduleEvent.Event, "'", "''", -1) + "'" +
+		" AND scheduled_at = '" + strings.Replace(scheduleEvent.ScheduledAt, "'", "''", -1) + "'"
+	queryCtx, cancel := queryContext(ctx)
+	_, err := mysqlc.MySqlDB.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
+
+func UpdateProductDraftStatusByProductIdPK(ctx context.Context, txn *sql.Tx, productIdPK int, status string) bool {
+	/*
+		To take product_id (primary key of product table) and a status and change the status of its draft
+	*/
+	funcName := "UpdateProductDraftStatusByProductIdPK"
+	query := "UPDATE product_draft SET status = '" + strings.Replace(status, "'", "''", -1) + "'," +
+		" updated_at = CURRENT_TIMESTAMP WHERE product_id = " + strconv.Itoa(productIdPK)
+	queryCtx, cancel := queryContext(ctx)
+	_, err := txn.ExecContext(queryCtx, query)
+	cancel()
+	if err != nil {
+		logger.FromContext(ctx).Error(constants.MySQLLogBucketName, logIdentifier+funcName,
+			constants.MySQLQueryRunErrorMessage, err.Error())
+		metrics.MySQLQueryErrors.WithLabels(funcName).Inc()
+		return false
+	}
+	return true
+}
@@ -18,4 +18,113 @@ func RoutesBenNJerry(group *gin.RouterGroup) {
 
 	// to soft/permanent delete ice cream data for a specific product id
 	group.DELETE("/:product_id/", authenticator.IsAuthorized, DeleteData)
+
+	// to read a specific revision (snapshot) of ice cream data for a specific product id
+	group.GET("/:product_id/revisions/:revision/", authenticator.IsAuthorized, ReadRevision)
+
+	// to roll back ice cream data for a specific product id to a specific revision
+	group.POST("/:product_id/revisions/:revision/rollback/", authenticator.IsAuthorized, RollbackRevision)
+
+	// to submit the draft (edits saved by the update api) of ice cream data for a specific product id for review
+	group.POST("/:product_id/draft/submit/", authenticator.IsAuthorized, SubmitDraft)
+
+	// to approve the draft of ice cream data submitted for review for a specific product id, by reviewers only
+	group.POST("/:product_id/draft/approve/", authenticator.IsAuthorized, ApproveDraft)
+
+	// to send the draft of ice cream data submitted for review back to draft for a specific product id,
+	// by reviewers only
+	group.POST("/:product_id/draft/reject/", authenticator.IsAuthorized, RejectDraft)
+
+	// to publish the approved draft of ice cream data for a specific product id, i.e. apply its edits
+	group.POST("/:product_id/draft/publish/", authenticator.IsAuthorized, PublishDraft)
+
+	// to discard the draft of ice cream data for a specific product id
+	group.DELETE("/:product_id/draft/", authenticator.IsAuthorized, DeleteDraft)
+
+	// to read live ice cream products most similar to a specific product id, by their ingredients and dietary
+	// certifications
+	group.GET("/:product_id/similar/", authenticator.IsAuthorized, ReadSimilar)
+
+	// to read all markets of ice cream data for a specific product id, with its availability and prices there
+	group.GET("/:product_id/markets/", authenticator.IsAuthorized, ReadMarkets)
+
+	// to save availability and prices of ice cream data in a specific market for a specific product id
+	group.PUT("/:product_id/markets/:market/", authenticator.IsAuthorized, UpdateMarket)
+
+	// to remove ice cream data from a specific market for a specific product id
+	group.DELETE("/:product_id/markets/:market/", authenticator.IsAuthorized, DeleteMarket)
+
+	// to read all variants (formats) of ice cream data for a specific product id
+	group.GET("/:product_id/variants/", authenticator.IsAuthorized, ReadVariants)
+
+	// to add a variant (format) to ice cream data for a specific product id
+	group.POST("/:product_id/variants/", authenticator.IsAuthorized, CreateVariant)
+
+	// to change a specific variant (by sku) of ice cream data for a specific product id
+	group.PUT("/:product_id/variants/:sku/", authenticator.IsAuthorized, UpdateVariant)
+
+	// to delete a specific variant (by sku) of ice cream data for a specific product id
+	group.DELETE("/:product_id/variants/:sku/", authenticator.IsAuthorized, DeleteVariant)
+
+	// to read all translations of ice cream data for a specific product id
+	group.GET("/:product_id/translations/", authenticator.IsAuthorized, ReadTranslations)
+
+	// to save the translation of ice cream data to a specific locale for a specific product id
+	group.PUT("/:product_id/translations/:locale/", authenticator.IsAuthorized, UpdateTranslation)
+
+	// to delete the translation of ice cream data to a specific locale for a specific product id
+	group.DELETE("/:product_id/translations/:locale/", authenticator.IsAuthorized, DeleteTranslation)
+}
+
+func RoutesCatalog(group *gin.RouterGroup) {
+	// to upload an image, to be referred by ice cream products by its url
+	group.POST("/images/", authenticator.IsAuthorized, UploadImage)
+
+	// to read the allergen taxonomy
+	group.GET("/allergens/", authenticator.IsAuthorized, ReadAllergens)
+
+	// to read ice cream products free of a specific allergen
+	group.GET("/free-from/:allergen/", authenticator.IsAuthorized, ReadFreeFromAllergen)
+
+	// to read the tree of categories with the number of products in each
+	group.GET("/categories/", authenticator.IsAuthorized, ReadCategories)
+
+	// to create a new category, at the root or in a parent category
+	group.POST("/categories/", authenticator.IsAuthorized, CreateCategory)
+
+	// to rename a category and/or move it to another parent category
+	group.PUT("/categories/:id/", authenticator.IsAuthorized, UpdateCategory)
+
+	// to delete a category, which has neither subcategories nor products
+	group.DELETE("/categories/:id/", authenticator.IsAuthorized, DeleteCategory)
+
+	// to read ice cream products in a category (or its subcategories) and/or with a tag
+	group.GET("/products/", authenticator.IsAuthorized, ReadProducts)
+
+	// to read the number of live ice cream products, matching filters, per ingredient, sourcing value and
+	// dietary certification
+	group.GET("/facets/", authenticator.IsAuthorized, ReadFacets)
+
+	// to read entries of a vocabulary (ingredients, sourcing_values, dietary_certifications, tags) with their usage
+	group.GET("/vocabularies/:vocabulary/", authenticator.IsAuthorized, ReadVocabulary)
+
+	// to create a new entry of a vocabulary
+	group.POST("/vocabularies/:vocabulary/", authenticator.IsAuthorized, CreateVocabularyEntry)
+
+	// to rename an entry of a vocabulary, for all products using it
+	group.PUT("/vocabularies/:vocabulary/:id/", authenticator.IsAuthorized, RenameVocabularyEntry)
+
+	// to delete an entry of a vocabulary, which isn't used by any product
+	group.DELETE("/vocabularies/:vocabulary/:id/", authenticator.IsAuthorized, DeleteVocabularyEntry)
+
+	// to merge an entry of a vocabulary into another entry, e.g. a duplicate
+	group.POST("/vocabularies/:vocabulary/:id/merge/", authenticator.IsAuthorized, MergeVocabularyEntry)
+
+	// to save the translated name of an entry of a vocabulary in a specific locale
+	group.PUT("/vocabularies/:vocabulary/:id/translations/:locale/", authenticator.IsAuthorized,
+		UpdateVocabularyTranslation)
+
+	// to delete the translated name of an entry of a vocabulary in a specific locale
+	group.DELETE("/vocabularies/:vocabulary/:id/translations/:locale/", authenticator.IsAuthorized,
+		DeleteVocabularyTranslation)
 }
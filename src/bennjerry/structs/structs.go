@@ -1,18 +1,285 @@
 package structs
 
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
 // Information of an ice cream product: used to parse create/upload request data and also to send read response
 type IceCreamDataStruct struct {
-	AllergyInfo           string   `json:"allergy_info"`
-	Description           string   `json:"description"`
-	DietaryCertifications string   `json:"dietary_certifications"`
-	ImageClosed           string   `json:"image_closed"`
-	ImageOpened           string   `json:"image_open"`
-	Name                  string   `json:"name"`
-	ProductId             string   `json:"productId"`
-	Story                 string   `json:"story"`
-	Id                    int      `json:"id"`
-	SourcingValues        []string `json:"sourcing_values"`
-	Ingredients           []string `json:"ingredients"`
+	AllergyInfo           string         `json:"allergy_info"`
+	Description           string         `json:"description"`
+	ImageClosed           string         `json:"image_closed"`
+	ImageOpened           string         `json:"image_open"`
+	Name                  string         `json:"name"`
+	ProductId             string         `json:"productId"`
+	Story                 string         `json:"story"`
+	Id                    int            `json:"id"`
+	DietaryCertifications StringList     `json:"dietary_certifications"`
+	SourcingValues        []string       `json:"sourcing_values"`
+	Ingredients           IngredientList `json:"ingredients"`
+	// paths of the categories of the product, e.g. ["Non-Dairy > Frozen Dessert"]
+	Categories []string `json:"categories"`
+	Tags       []string `json:"tags"`
+	// nil (null in json) if allergens of the product have not been declared, empty if it has none
+	Allergens []*AllergenStruct `json:"allergens"`
+	// nil (null in json) if nutrition facts of the product have not been declared
+	Nutrition *NutritionStruct `json:"nutrition"`
+	// times (UTC) the product goes live and expires, empty if it's live since ever or for ever
+	PublishAt   string `json:"publish_at"`
+	UnpublishAt string `json:"unpublish_at"`
+	// availability and current prices in the market asked for by the read api, not part of create/update
+	Market *MarketStruct `json:"market,omitempty"`
+	// formats the product is sold in, not part of create/update
+	Variants []*VariantStruct `json:"variants,omitempty"`
+}
+
+// Ice cream data as encoded by the json package, without the methods of IceCreamDataStruct
+type iceCreamDataJSON IceCreamDataStruct
+
+// Ingredients are sent as a list of names, like clients have always read them, with their percentages in a separate
+// list in the same order, e.g. "ingredients": ["Cream", "Sugar"], "ingredient_percentages": [40.5, null]
+func (d IceCreamDataStruct) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		iceCreamDataJSON
+		IngredientPercentages []*float64 `json:"ingredient_percentages"`
+	}{iceCreamDataJSON(d), d.Ingredients.Percentages()})
+}
+
+// Percentages of ingredients are taken from ingredient_percentages, if it's sent, which must then have one
+// (or null) for each ingredient
+func (d *IceCreamDataStruct) UnmarshalJSON(data []byte) error {
+	decoded := struct {
+		*iceCreamDataJSON
+		IngredientPercentages []*float64 `json:"ingredient_percentages"`
+	}{iceCreamDataJSON: (*iceCreamDataJSON)(d)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.IngredientPercentages == nil {
+		return nil
+	}
+	if len(decoded.IngredientPercentages) != len(d.Ingredients) {
+		return errors.New("ingredient_percentages must have a percentage (or null) for each ingredient")
+	}
+	for index, percentage := range decoded.IngredientPercentages {
+		d.Ingredients[index].Percentage = percentage
+	}
+	return nil
+}
+
+// Ingredient of an ice cream product, with its percentage (null, if not declared)
+type IngredientStruct struct {
+	Name       string   `json:"name"`
+	Percentage *float64 `json:"percentage"`
+}
+
+// Ingredients in the order they are listed on the label, encoded as their names, e.g. ["Cream", "Sugar"]
+// An ingredient can also be parsed from an object with its percentage, as sent by clients (and stored in revisions)
+// before percentages were a separate list, e.g. [{"name": "Cream", "percentage": 40.5}, "Sugar"]
+type IngredientList []*IngredientStruct
+
+func (l IngredientList) MarshalJSON() ([]byte, error) {
+	if l == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(l.Names())
+}
+
+func (l *IngredientList) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	list := make(IngredientList, 0, len(items))
+	for _, item := range items {
+		ingredient := &IngredientStruct{}
+		if err := json.Unmarshal(item, &ingredient.Name); err != nil {
+			if err := json.Unmarshal(item, ingredient); err != nil {
+				return err
+			}
+		}
+		list = append(list, ingredient)
+	}
+	*l = list
+	return nil
+}
+
+// Names of the ingredients, in order
+func (l IngredientList) Names() []string {
+	names := make([]string, 0, len(l))
+	for _, ingredient := range l {
+		names = append(names, ingredient.Name)
+	}
+	return names
+}
+
+// Percentages of the ingredients, in order, nil (null in json) for an ingredient without one
+func (l IngredientList) Percentages() []*float64 {
+	if l == nil {
+		return nil
+	}
+	percentages := make([]*float64, 0, len(l))
+	for _, ingredient := range l {
+		percentages = append(percentages, ingredient.Percentage)
+	}
+	return percentages
+}
+
+// Allergen of an ice cream product, by code of the allergen taxonomy and level: "contains" or "may_contain"
+type AllergenStruct struct {
+	Code  string `json:"code"`
+	Level string `json:"level"`
+}
+
+// Nutrition facts of an ice cream product, amounts of its nutrients per 100 g and per serving
+type NutritionStruct struct {
+	// grams per serving
+	ServingSize float64           `json:"serving_size_g"`
+	Nutrients   []*NutrientStruct `json:"nutrients"`
+}
+
+// Amount of a nutrient (e.g. sugar) in a unit (e.g. g), sent per 100 g or, if per_100g is null, per serving
+// Amounts are stored per 100 g in the unit of the nutrient, read responses carry both in that unit
+type NutrientStruct struct {
+	Nutrient   string   `json:"nutrient"`
+	Unit       string   `json:"unit"`
+	Per100g    *float64 `json:"per_100g"`
+	PerServing *float64 `json:"per_serving"`
+}
+
+// Allergen of the taxonomy, as listed by the allergens api
+type AllergenTaxonomyStruct struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// Entry of a vocabulary (ingredients, sourcing values, dietary certifications) with the number of products using it
+type VocabularyEntryStruct struct {
+	Id           int    `json:"id"`
+	Name         string `json:"name"`
+	ProductCount int    `json:"product_count"`
+	// translated names by locale, e.g. {"id": "Susu"}
+	Translations map[string]string `json:"translations"`
+}
+
+// Ice cream product similar to another, with its score, higher is more similar
+type SimilarProductStruct struct {
+	ProductId string  `json:"productId"`
+	Name      string  `json:"name"`
+	Score     float64 `json:"score"`
+}
+
+// Entry of a vocabulary with the number of products matching the filters of the facets api that use it
+type FacetEntryStruct struct {
+	Id           int    `json:"id"`
+	Name         string `json:"name"`
+	ProductCount int    `json:"product_count"`
+}
+
+// Facets of the products matching the filters of the facets api, entries not used by any of them are left out
+type FacetStruct struct {
+	ProductCount          int                 `json:"product_count"`
+	DietaryCertifications []*FacetEntryStruct `json:"dietary_certifications"`
+	Ingredients           []*FacetEntryStruct `json:"ingredients"`
+	SourcingValues        []*FacetEntryStruct `json:"sourcing_values"`
+}
+
+// Translation of the texts of an ice cream product to a locale, an empty text isn't translated and falls back
+type TranslationStruct struct {
+	Locale      string `json:"locale"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Story       string `json:"story"`
+}
+
+// Image uploaded to the image storage, products refer to it by url in image_closed/image_open
+type ImageStruct struct {
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	ContentType  string `json:"content_type"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Size         int    `json:"size"`
+}
+
+// Market (country code) an ice cream product is listed in, with its availability and prices there
+type MarketStruct struct {
+	Market    string         `json:"market"`
+	Available bool           `json:"available"`
+	Prices    []*PriceStruct `json:"prices"`
+}
+
+// Price of an ice cream product in a currency, in minor units (e.g. cents), effective from a date (UTC)
+// till another one (exclusive), or without end if effective_to is empty
+type PriceStruct struct {
+	Currency      string `json:"currency"`
+	Amount        int64  `json:"amount"`
+	EffectiveFrom string `json:"effective_from"`
+	EffectiveTo   string `json:"effective_to"`
+}
+
+// Variant (format) an ice cream product is sold in, e.g. a pint, with its own sku, size and packaging image
+type VariantStruct struct {
+	Sku            string  `json:"sku"`
+	Name           string  `json:"name"`
+	Size           float64 `json:"size"`
+	SizeUnit       string  `json:"size_unit"`
+	PackagingImage string  `json:"packaging_image"`
+	// amounts per 100 g overriding those of the nutrition facts of the product, by nutrient, e.g. {"sugar": 21.5}
+	Nutrition map[string]float64 `json:"nutrition"`
+}
+
+// Category of ice cream products with its subcategories, as listed by the catalog apis
+type CategoryStruct struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	// names of the category and its parents, as products refer to it, e.g. "Non-Dairy > Frozen Dessert"
+	Path string `json:"path"`
+	// number of products in the category itself, not in its subcategories
+	ProductCount int               `json:"product_count"`
+	Children     []*CategoryStruct `json:"children"`
+}
+
+// Event emitted by the scheduler when an ice cream product goes live or expires
+type ScheduleEventStruct struct {
+	Event     string `json:"event"`
+	ProductId string `json:"productId"`
+	Name      string `json:"name"`
+	// publish_at or unpublish_at of the product the event is emitted for and the time it's emitted (UTC)
+	ScheduledAt string `json:"scheduled_at"`
+	EmittedAt   string `json:"emitted_at"`
+}
+
+// Short information of an ice cream product, as listed by the catalog apis
+type ProductSummaryStruct struct {
+	ProductId string `json:"productId"`
+	Name      string `json:"name"`
+}
+
+// List of names which can also be parsed from a single string, for clients (and stored revisions) from the time
+// a field held only one name, e.g. "dietary_certifications": "Kosher" is parsed as ["Kosher"]
+type StringList []string
+
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*l = StringList{}
+		if strings.TrimSpace(name) != "" {
+			*l = StringList{name}
+		}
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+	*l = names
+	return nil
 }
 
 // Response structure of create/update/delete
@@ -24,7 +291,88 @@ type CreateUpdateDeleteResponse struct {
 
 // Response structure of read
 type ReadResponse struct {
-	Message string              `json:"message"`
-	Success bool                `json:"success"`
-	Data    *IceCreamDataStruct `json:"data"`
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	// status of the draft of the product, only when it's read in preview and has a draft
+	DraftStatus string              `json:"draft_status,omitempty"`
+	Data        *IceCreamDataStruct `json:"data"`
+}
+
+// Response structure of read revision
+type RevisionResponse struct {
+	Message   string              `json:"message"`
+	CreatedAt string              `json:"created_at"`
+	Revision  int                 `json:"revision"`
+	Success   bool                `json:"success"`
+	Data      *IceCreamDataStruct `json:"data"`
+}
+
+// Response structure of the allergen taxonomy
+type AllergenListResponse struct {
+	Message string                    `json:"message"`
+	Success bool                      `json:"success"`
+	Data    []*AllergenTaxonomyStruct `json:"data"`
+}
+
+// Response structure of apis listing products
+type ProductListResponse struct {
+	Message string                  `json:"message"`
+	Success bool                    `json:"success"`
+	Data    []*ProductSummaryStruct `json:"data"`
+}
+
+// Response structure of reading products similar to a product
+type SimilarProductListResponse struct {
+	Message string                  `json:"message"`
+	Success bool                    `json:"success"`
+	Data    []*SimilarProductStruct `json:"data"`
+}
+
+// Response structure of reading facets of products
+type FacetResponse struct {
+	Message string       `json:"message"`
+	Success bool         `json:"success"`
+	Data    *FacetStruct `json:"data"`
+}
+
+// Response structure of reading the category tree
+type CategoryListResponse struct {
+	Message string            `json:"message"`
+	Success bool              `json:"success"`
+	Data    []*CategoryStruct `json:"data"`
+}
+
+// Response structure of uploading an image
+type ImageUploadResponse struct {
+	Message string       `json:"message"`
+	Success bool         `json:"success"`
+	Data    *ImageStruct `json:"data"`
+}
+
+// Response structure of reading markets of a product
+type MarketListResponse struct {
+	Message string          `json:"message"`
+	Success bool            `json:"success"`
+	Data    []*MarketStruct `json:"data"`
+}
+
+// Response structure of reading variants of a product
+type VariantListResponse struct {
+	Message string           `json:"message"`
+	Success bool             `json:"success"`
+	Data    []*VariantStruct `json:"data"`
+}
+
+// Response structure of reading translations of a product
+type TranslationListResponse struct {
+	Message string               `json:"message"`
+	Success bool                 `json:"success"`
+	Data    []*TranslationStruct `json:"data"`
+}
+
+// Response structure of apis listing entries of a vocabulary
+type VocabularyListResponse struct {
+	Message string                   `json:"message"`
+	Success bool                     `json:"success"`
+	Data    []*VocabularyEntryStruct `json:"data"`
 }
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"authenticator"
+	"bennjerry"
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"logger"
+	"mysqlc"
+)
+
+func TestValidateAllergens(t *testing.T) {
+	/*
+		Testing Scenario: Validating allergens with an unknown code, an unknown level and a repeated code
+		Expectation: All of them are invalid, while codes of the taxonomy with known levels are valid
+	*/
+	mysqlc.Init()
+	logger.Init()
+	defer mysqlc.DBClosing()
+	ctx := context.Background()
+
+	invalidAllergens := [][]*structs.AllergenStruct{
+		{{Code: "chocolate", Level: constants.AllergenLevelContains}},
+		{{Code: "milk", Level: "traces"}},
+		{{Code: "milk", Level: constants.AllergenLevelContains}, {Code: "milk", Level: constants.AllergenLevelMayContain}},
+	}
+	for _, allergens := range invalidAllergens {
+		if valid, success := model.ValidateAllergens(ctx, allergens); !success || valid {
+			t.Fatalf("Expected allergens %v to be invalid\n", allergens)
+		}
+	}
+	valid, success := model.ValidateAllergens(ctx, []*structs.AllergenStruct{
+		{Code: "milk", Level: constants.AllergenLevelContains}, {Code: "nuts", Level: constants.AllergenLevelMayContain},
+	})
+	if !success || !valid {
+		t.Fatalf("Expected allergens milk and nuts to be valid\n")
+	}
+}
+
+func TestReadFreeFromAllergen(t *testing.T) {
+	/*
+		Testing Scenario: Listing products free of nuts, with products containing nuts, maybe containing nuts,
+		declaring no allergens and not declaring allergens at all
+		Expectation: Only the product declaring no allergens is listed, and also the one that may contain nuts
+		with include_may_contain=1
+		** products created in this scenario are permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+	route := gin.Default()
+	route.GET("/catalog/free-from/:allergen/", authenticator.IsAuthorized, bennjerry.ReadFreeFromAllergen)
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{
+		{ProductId: "testallergen1", Name: "Contains nuts",
+			Allergens: []*structs.AllergenStruct{{Code: "nuts", Level: constants.AllergenLevelContains}}},
+		{ProductId: "testallergen2", Name: "May contain nuts",
+			Allergens: []*structs.AllergenStruct{{Code: "nuts", Level: constants.AllergenLevelMayContain}}},
+		{ProductId: "testallergen3", Name: "Declares no allergens", Allergens: []*structs.AllergenStruct{}},
+		{ProductId: "testallergen4", Name: "Doesn't declare allergens"},
+	})
+	if !success || len(idList) != 4 {
+		t.Fatalf("Couldn't create products with allergens\n")
+	}
+	defer func() {
+		// Cleaning up the products created for this scenario
+		for _, id := range idList {
+			model.DropRecord(ctx, id)
+		}
+		mysqlc.DBClosing()
+	}()
+
+	expectations := map[string]map[string]bool{
+		"/catalog/free-from/nuts/":                       {"testallergen3": true},
+		"/catalog/free-from/nuts/?include_may_contain=1": {"testallergen2": true, "testallergen3": true},
+	}
+	for path, expectedProductIds := range expectations {
+		req, reqErr := http.NewRequest(http.MethodGet, path, nil)
+		if reqErr != nil {
+			t.Fatalf("Couldn't create request: %v\n", reqErr)
+		}
+		// Generating token for authorization
+		jwtToken, tokenErr := authenticator.GenerateJWT()
+		if tokenErr != nil {
+			t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+		}
+		req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+
+		// Creating a response recorder to inspect the response
+		recorder := httptest.NewRecorder()
+
+		// Performing the request
+		route.ServeHTTP(recorder, req)
+
+		// Checking to see if the response was what you expected
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d but got %d\n", http.StatusOK, recorder.Code)
+		}
+		respBytes, respErr := ioutil.ReadAll(recorder.Body)
+		if respErr != nil {
+			t.Fatalf("Error while reading response %s\n", respErr.Error())
+		}
+		resp := &structs.ProductListResponse{}
+		if unMarshallErr := json.Unmarshal(respBytes, resp); unMarshallErr != nil {
+			t.Fatalf("Error while parsing response %s\n", unMarshallErr.Error())
+		}
+		if !resp.Success {
+			t.Fatalf("Expected success response for %s but got message %s\n", path, resp.Message)
+		}
+		// Other products may exist in DB, only checking products of this scenario
+		foundProductIds := make(map[string]bool)
+		for _, product := range resp.Data {
+			if product.ProductId >= "testallergen1" && product.ProductId <= "testallergen4" {
+				foundProductIds[product.ProductId] = true
+			}
+		}
+		if len(foundProductIds) != len(expectedProductIds) {
+			t.Fatalf("Expected products %v for %s but got %v\n", expectedProductIds, path, foundProductIds)
+		}
+		for productId := range expectedProductIds {
+			if !foundProductIds[productId] {
+				t.Fatalf("Expected products %v for %s but got %v\n", expectedProductIds, path, foundProductIds)
+			}
+		}
+	}
+}
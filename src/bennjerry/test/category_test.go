@@ -0,0 +1,137 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"bennjerry/model"
+	"bennjerry/structs"
+	"logger"
+	"mysqlc"
+	"utils"
+)
+
+func TestSplitCategoryPath(t *testing.T) {
+	/*
+		Testing Scenario: Splitting paths of categories, with irregular whitespace, empty names and too many levels
+		Expectation: Names are normalized, paths with an empty name or deeper than 8 levels are invalid
+	*/
+	testCases := []struct {
+		name     string
+		path     string
+		expected []string
+		valid    bool
+	}{
+		{"single name", "Pints", []string{"Pints"}, true},
+		{"nested names", " Non-Dairy >  Frozen  Dessert", []string{"Non-Dairy", "Frozen Dessert"}, true},
+		{"empty path", "", nil, false},
+		{"empty name", "Pints > > Cores", nil, false},
+		{"too deep", "a > b > c > d > e > f > g > h > i", nil, false},
+	}
+	for _, testCase := range testCases {
+		names, valid := model.SplitCategoryPath(testCase.path)
+		if valid != testCase.valid || !utils.ListOfStringCompare(names, testCase.expected) {
+			t.Errorf("%s: expected %v (valid: %v) but got %v (valid: %v)\n", testCase.name, testCase.expected,
+				testCase.valid, names, valid)
+		}
+	}
+	if model.ValidCategoryName("Pints > Cores") || model.ValidCategoryName("  ") || !model.ValidCategoryName("Pints") {
+		t.Errorf("Expected names with > or without letters to be invalid\n")
+	}
+}
+
+func TestCategoriesAndTags(t *testing.T) {
+	/*
+		Testing Scenario: Creating a category with a subcategory and a product in the subcategory with a tag,
+		finding products by category and tag, then deleting, renaming and moving the categories
+		Expectation: Products are found in parents of their categories and by tags matched by normalized key,
+		categories in use aren't deleted, renaming changes paths of products and moving into a subcategory is refused
+		** product, categories and tag created in this scenario are permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+
+	rootId, result := model.CreateCategory(ctx, 0, "testcategory Pints")
+	if result != model.CategoryChanged {
+		t.Fatalf("Couldn't create category, result %d\n", result)
+	}
+	childId, result := model.CreateCategory(ctx, rootId, "testcategory Cores")
+	if result != model.CategoryChanged {
+		model.DeleteCategory(ctx, rootId)
+		t.Fatalf("Couldn't create subcategory, result %d\n", result)
+	}
+	idList := make([]int, 0)
+	defer func() {
+		// Cleaning up the product, categories and tag created for this scenario
+		for _, id := range idList {
+			model.DropRecord(ctx, id)
+		}
+		model.DeleteCategory(ctx, childId)
+		model.DeleteCategory(ctx, rootId)
+		model.CleanUpUnUsed(ctx, false)
+		mysqlc.DBClosing()
+	}()
+	if _, result = model.CreateCategory(ctx, rootId, "TESTCATEGORY  cores"); result != model.CategoryNameExists {
+		t.Fatalf("Expected a sibling of the same name to be refused but got result %d\n", result)
+	}
+
+	if valid, success := model.ValidateCategories(ctx, []string{"testcategory Pints > testcategory Cups"}); !success ||
+		valid {
+		t.Fatalf("Expected an unknown category to be invalid\n")
+	}
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{{
+		ProductId:  "testcategory1",
+		Name:       "Name of Ice Cream",
+		Categories: []string{"testcategory pints > testcategory cores"},
+		Tags:       []string{"testcategory Vegan"},
+	}})
+	if !success || len(idList) != 1 {
+		t.Fatalf("Couldn't create product in category\n")
+	}
+	categories := model.SelectCategoryPathByProductIdPK(ctx, idList[0])
+	expected := []string{"testcategory Pints > testcategory Cores"}
+	if !utils.ListOfStringCompare(categories, expected) {
+		t.Fatalf("Expected categories %v but got %v\n", expected, categories)
+	}
+
+	// Products of subcategories are found in their parent categories as well
+	for _, filter := range []struct {
+		categoryId int
+		tag        string
+	}{{rootId, ""}, {childId, ""}, {0, "TESTCATEGORY vegan"}, {rootId, "testcategory Vegan"}} {
+		products, found, success := model.FindProducts(ctx, filter.categoryId, filter.tag)
+		if !success || !found || len(products) != 1 || products[0].ProductId != "testcategory1" {
+			t.Fatalf("Expected product to be found by %v but got %v\n", filter, products)
+		}
+	}
+	if products, found, success := model.FindProducts(ctx, childId, "testcategory Other"); !success || !found ||
+		len(products) != 0 {
+		t.Fatalf("Expected no product with another tag but got %v\n", products)
+	}
+
+	if result = model.DeleteCategory(ctx, rootId); result != model.CategoryInUse {
+		t.Fatalf("Expected category with subcategories not to be deleted but got result %d\n", result)
+	}
+	if result = model.DeleteCategory(ctx, childId); result != model.CategoryInUse {
+		t.Fatalf("Expected category with products not to be deleted but got result %d\n", result)
+	}
+	if result = model.UpdateCategory(ctx, rootId, childId, "testcategory Pints"); result != model.CategoryInvalid {
+		t.Fatalf("Expected moving a category into its subcategory to be refused but got result %d\n", result)
+	}
+	result = model.UpdateCategory(ctx, childId, rootId, "testcategory Core Pints")
+	categories = model.SelectCategoryPathByProductIdPK(ctx, idList[0])
+	expected = []string{"testcategory Pints > testcategory Core Pints"}
+	if result != model.CategoryChanged || !utils.ListOfStringCompare(categories, expected) {
+		t.Fatalf("Expected categories %v after rename but got %v (result %d)\n", expected, categories, result)
+	}
+
+	success = model.UpdateRecord(ctx, idList[0], &structs.IceCreamDataStruct{Categories: []string{}},
+		map[string]bool{"categories": true})
+	if !success || len(model.SelectCategoryPathByProductIdPK(ctx, idList[0])) != 0 {
+		t.Fatalf("Expected product to be removed from its categories\n")
+	}
+	if result = model.DeleteCategory(ctx, childId); result != model.CategoryChanged {
+		t.Fatalf("Expected unused category to be deleted but got result %d\n", result)
+	}
+}
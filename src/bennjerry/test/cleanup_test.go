@@ -0,0 +1,65 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"bennjerry/model"
+	"bennjerry/structs"
+	"logger"
+	"mysqlc"
+)
+
+func TestCleanUpUnUsed(t *testing.T) {
+	/*
+		Testing Scenario: Deleting a product with an ingredient no other product uses, then cleaning up
+		first as dry run and then for real
+		Expectation: Dry run lists the ingredient without deleting any row, the real run deletes it and counts its row
+		** product created in this scenario is permanently deleted
+	*/
+	mysqlc.Init()
+	logger.Init()
+	defer mysqlc.DBClosing()
+	ctx := context.Background()
+	ingredient := "testcleanup ingredient 123"
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{{
+		ProductId:   "testcleanup123",
+		Name:        "Name of Ice Cream",
+		Ingredients: structs.IngredientList{{Name: ingredient}},
+	}})
+	if !success || len(idList) == 0 {
+		t.Fatalf("Couldn't create product to clean up after\n")
+	}
+	if !model.DropRecord(ctx, idList[0]) {
+		t.Fatalf("Couldn't delete product\n")
+	}
+
+	unUsed, success := model.CleanUpUnUsed(ctx, true)
+	if !success || !contains(unUsed.Ingredients, ingredient) || unUsed.RowsDeleted["ingredient"] != 0 {
+		t.Fatalf("Expected dry run to list %s as unused but got %v\n", ingredient, unUsed)
+	}
+	unUsed, success = model.CleanUpUnUsed(ctx, true)
+	if !success || !contains(unUsed.Ingredients, ingredient) {
+		t.Fatalf("Expected dry run not to delete %s but got %v\n", ingredient, unUsed)
+	}
+
+	unUsed, success = model.CleanUpUnUsed(ctx, false)
+	if !success || !contains(unUsed.Ingredients, ingredient) ||
+		unUsed.RowsDeleted["ingredient"] < int64(len(unUsed.Ingredients)) {
+		t.Fatalf("Expected %s to be deleted but got %v\n", ingredient, unUsed)
+	}
+	unUsed, success = model.CleanUpUnUsed(ctx, true)
+	if !success || contains(unUsed.Ingredients, ingredient) {
+		t.Fatalf("Expected %s not to be found after clean up but got %v\n", ingredient, unUsed)
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, value := range names {
+		if value == name {
+			return true
+		}
+	}
+	return false
+}
@@ -2,11 +2,13 @@ package test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sort"
 	"strconv"
 	"testing"
 
@@ -14,10 +16,12 @@ import (
 
 	"authenticator"
 	"bennjerry"
+	"bennjerry/model"
 	"bennjerry/structs"
 	"constants"
 	"logger"
 	"mysqlc"
+	"utils"
 )
 
 func TestCreateDataUnAuthorized(t *testing.T) {
@@ -298,3 +302,70 @@ func TestCreateDataDuplicateRequest(t *testing.T) {
 	}
 	mysqlc.DBClosing()
 }
+
+func TestCreateRecordCancelledContext(t *testing.T) {
+	/*
+		Testing Scenario: Creating a product with a context that is already cancelled, e.g. client has disconnected
+		Expectation: Transaction is not committed and product doesn't exist in DB
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{{
+		ProductId: "testcancelled123",
+		Name:      "Name of Ice Cream",
+	}})
+	if success || len(idList) != 0 {
+		t.Fatalf("Expected create to fail with cancelled context but got ids %v\n", idList)
+	}
+	id, _ := model.SelectIdFromProductByProductId(context.Background(), "testcancelled123")
+	if id != 0 {
+		model.DropRecord(context.Background(), id)
+		t.Fatalf("Expected product not to be created but found it with id %d\n", id)
+	}
+	mysqlc.DBClosing()
+}
+
+func TestCreateRecordMultipleDietaryCertifications(t *testing.T) {
+	/*
+		Testing Scenario: Creating a product with several dietary certifications, then replacing one of them
+		Expectation: All certifications of the product are stored and read back
+		** product created in this scenario is permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{{
+		ProductId:             "testcertifications123",
+		Name:                  "Name of Ice Cream",
+		DietaryCertifications: []string{"Kosher", "Halal", "Vegan"},
+	}})
+	if !success || len(idList) == 0 {
+		t.Fatalf("Couldn't create product with dietary certifications\n")
+	}
+	defer func() {
+		// Cleaning up the product created for this scenario
+		model.DropRecord(ctx, idList[0])
+		mysqlc.DBClosing()
+	}()
+	// Certifications are not ordered, comparing them sorted
+	dietaryCertifications := model.SelectDietaryCertificationNameByProductIdPK(ctx, idList[0])
+	sort.Strings(dietaryCertifications)
+	if !utils.ListOfStringCompare(dietaryCertifications, []string{"Halal", "Kosher", "Vegan"}) {
+		t.Fatalf("Expected dietary certifications %v but got %v\n", []string{"Halal", "Kosher", "Vegan"},
+			dietaryCertifications)
+	}
+
+	success = model.UpdateRecord(ctx, idList[0], &structs.IceCreamDataStruct{
+		DietaryCertifications: []string{"Kosher", "Organic"},
+	}, map[string]bool{"dietary_certifications": true})
+	dietaryCertifications = model.SelectDietaryCertificationNameByProductIdPK(ctx, idList[0])
+	sort.Strings(dietaryCertifications)
+	if !success || !utils.ListOfStringCompare(dietaryCertifications, []string{"Kosher", "Organic"}) {
+		t.Fatalf("Expected dietary certifications %v but got %v\n", []string{"Kosher", "Organic"},
+			dietaryCertifications)
+	}
+}
@@ -0,0 +1,171 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"authenticator"
+	"bennjerry"
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"logger"
+	"mysqlc"
+)
+
+func TestValidateFields(t *testing.T) {
+	/*
+		Testing Scenario: Validating fields of update requests with known, unknown and no fields
+		Expectation: Fields are valid, if at least one of them is a field of a product
+	*/
+	testCases := []struct {
+		fields []string
+		valid  bool
+	}{
+		{[]string{"name"}, true},
+		{[]string{"name", "unknown"}, true},
+		{[]string{"unknown"}, false},
+		{[]string{""}, false},
+	}
+	for _, testCase := range testCases {
+		fieldMap := make(map[string]bool)
+		for _, field := range testCase.fields {
+			fieldMap[field] = true
+		}
+		if valid := model.ValidateFields(fieldMap); valid != testCase.valid {
+			t.Errorf("%v: expected valid: %v but got %v\n", testCase.fields, testCase.valid, valid)
+		}
+	}
+}
+
+func TestDraftWorkflow(t *testing.T) {
+	/*
+		Testing Scenario: Creating a product, editing it twice, reading it and its preview, then submitting,
+		approving (as an editor and as a reviewer) and publishing the draft, publishing being tried too early
+		Expectation: Edits are shown only in preview till the draft is published, only reviewers approve drafts,
+		drafts are published only once approved and then become the next revision of the product
+		** product created in this scenario is permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+	route := gin.Default()
+	route.GET("/bennjerry/:product_id/", authenticator.IsAuthorized, bennjerry.ReadData)
+	route.PUT("/bennjerry/:product_id/", authenticator.IsAuthorized, bennjerry.UpdateData)
+	route.POST("/bennjerry/:product_id/draft/submit/", authenticator.IsAuthorized, bennjerry.SubmitDraft)
+	route.POST("/bennjerry/:product_id/draft/approve/", authenticator.IsAuthorized, bennjerry.ApproveDraft)
+	route.POST("/bennjerry/:product_id/draft/publish/", authenticator.IsAuthorized, bennjerry.PublishDraft)
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{{
+		ProductId: "testdraft1",
+		Name:      "Name of Ice Cream",
+		Story:     "Story of Ice Cream",
+	}})
+	if !success || len(idList) != 1 {
+		t.Fatalf("Couldn't create product to edit\n")
+	}
+	defer func() {
+		// Cleaning up the product created for this scenario, its draft is deleted along
+		model.DropRecord(ctx, idList[0])
+		mysqlc.DBClosing()
+	}()
+	editorToken, tokenErr := authenticator.GenerateJWT()
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	reviewerToken, tokenErr := authenticator.GenerateJWTWithRole(constants.RoleReviewer)
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	serve := func(method string, path string, token string, form url.Values, resp interface{}) int {
+		req, reqErr := http.NewRequest(method, path, bytes.NewBufferString(form.Encode()))
+		if reqErr != nil {
+			t.Fatalf("Couldn't create request: %v\n", reqErr)
+		}
+		req.Header.Add(constants.JWTTokenKeyNameInHeader, token)
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Add("Content-Length", strconv.Itoa(len(form.Encode())))
+		recorder := httptest.NewRecorder()
+		route.ServeHTTP(recorder, req)
+		if recorder.Code == http.StatusOK {
+			if unMarshallErr := json.Unmarshal(recorder.Body.Bytes(), resp); unMarshallErr != nil {
+				t.Fatalf("Expected json response but got %s\n", recorder.Body.String())
+			}
+		}
+		return recorder.Code
+	}
+	read := func(preview bool) *structs.ReadResponse {
+		resp := &structs.ReadResponse{}
+		path := "/bennjerry/testdraft1/"
+		if preview {
+			path += "?preview=1"
+		}
+		serve(http.MethodGet, path, editorToken, url.Values{}, resp)
+		if !resp.Success {
+			t.Fatalf("Expected product to be read but got %s\n", resp.Message)
+		}
+		return resp
+	}
+	change := func(action string, token string) *structs.CreateUpdateDeleteResponse {
+		resp := &structs.CreateUpdateDeleteResponse{}
+		serve(http.MethodPost, "/bennjerry/testdraft1/draft/"+action+"/", token, url.Values{}, resp)
+		return resp
+	}
+
+	// Edits of both requests are kept in the draft
+	for _, edit := range []url.Values{
+		{"fields": {"name"}, "data": {`{"name": "New Name of Ice Cream"}`}},
+		{"fields": {"story"}, "data": {`{"story": "New Story of Ice Cream"}`}},
+	} {
+		saved := &structs.CreateUpdateDeleteResponse{}
+		if serve(http.MethodPut, "/bennjerry/testdraft1/", editorToken, edit, saved); !saved.Success ||
+			saved.Message != constants.DraftSaveSuccessMessage {
+			t.Fatalf("Expected edits to be saved as draft but got %v\n", *saved)
+		}
+	}
+	if published := read(false); published.Data.Name != "Name of Ice Cream" || published.DraftStatus != "" {
+		t.Fatalf("Expected published name %s but got %v\n", "Name of Ice Cream", *published.Data)
+	}
+	if preview := read(true); preview.Data.Name != "New Name of Ice Cream" ||
+		preview.Data.Story != "New Story of Ice Cream" || preview.DraftStatus != constants.DraftStatusDraft {
+		t.Fatalf("Expected edits of the draft in preview but got %v (%s)\n", *preview.Data, preview.DraftStatus)
+	}
+
+	if resp := change("publish", editorToken); resp.Success || resp.Message != constants.DraftStatusErrorMessage {
+		t.Fatalf("Expected draft not to be published before approval but got %v\n", *resp)
+	}
+	if resp := change("submit", editorToken); !resp.Success {
+		t.Fatalf("Expected draft to be submitted but got %v\n", *resp)
+	}
+	approved := &structs.CreateUpdateDeleteResponse{}
+	if code := serve(http.MethodPost, "/bennjerry/testdraft1/draft/approve/", editorToken, url.Values{},
+		approved); code != http.StatusForbidden {
+		t.Fatalf("Expected status code %d for an editor approving but got %d\n", http.StatusForbidden, code)
+	}
+	if resp := change("approve", reviewerToken); !resp.Success || resp.Message != constants.ApproveSuccessMessage {
+		t.Fatalf("Expected draft to be approved by a reviewer but got %v\n", *resp)
+	}
+	if resp := change("publish", editorToken); !resp.Success || resp.Message != constants.PublishSuccessMessage {
+		t.Fatalf("Expected approved draft to be published but got %v\n", *resp)
+	}
+
+	if published := read(false); published.Data.Name != "New Name of Ice Cream" ||
+		published.Data.Story != "New Story of Ice Cream" {
+		t.Fatalf("Expected edits to be published but got %v\n", *published.Data)
+	}
+	if preview := read(true); preview.DraftStatus != "" {
+		t.Fatalf("Expected draft to be deleted once published but got status %s\n", preview.DraftStatus)
+	}
+	if productRevision, success := model.SelectFromProductRevisionByProductIdPK(ctx, idList[0], 2); !success ||
+		productRevision == nil {
+		t.Fatalf("Expected published draft to be stored as revision 2\n")
+	}
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"logger"
+	"mysqlc"
+)
+
+func TestFacets(t *testing.T) {
+	/*
+		Testing Scenario: Creating two live products sharing an ingredient and one not published yet, then counting
+		facets without filters, by an ingredient and by an ingredient with a sourcing value no product uses
+		Expectation: Products not live aren't counted, filters match names by normalized key, every filter must
+		match and entries not used by any matching product are left out
+		** products created in this scenario are permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{{
+		ProductId:             "testfacet1",
+		Name:                  "Name of Ice Cream",
+		Ingredients:           structs.IngredientList{{Name: "testfacet Cocoa"}, {Name: "testfacet Cream"}},
+		SourcingValues:        []string{"testfacet Fairtrade"},
+		DietaryCertifications: []string{"testfacet Kosher"},
+	}, {
+		ProductId:   "testfacet2",
+		Name:        "Name of Ice Cream",
+		Ingredients: structs.IngredientList{{Name: "testfacet Cream"}},
+	}, {
+		ProductId:   "testfacet3",
+		Name:        "Name of Ice Cream",
+		Ingredients: structs.IngredientList{{Name: "testfacet Cream"}},
+		PublishAt:   time.Now().UTC().Add(time.Hour).Format(constants.ScheduleTimeLayout),
+	}})
+	if !success || len(idList) != 3 {
+		t.Fatalf("Couldn't create products\n")
+	}
+	defer func() {
+		// Cleaning up the products and vocabulary entries created for this scenario
+		for _, id := range idList {
+			model.DropRecord(ctx, id)
+		}
+		model.CleanUpUnUsed(ctx, false)
+		mysqlc.DBClosing()
+	}()
+
+	// Number of matching products using an entry, 0 if the entry is left out
+	count := func(facets map[string][]*model.PropertyUsage, field string, name string) int {
+		for _, entry := range facets[field] {
+			if entry.Name == name {
+				return entry.ProductCount
+			}
+		}
+		return 0
+	}
+
+	_, facets, found, success := model.FindFacets(ctx, &model.FacetFilter{})
+	if !success || !found || count(facets, "ingredients", "testfacet Cream") != 2 ||
+		count(facets, "ingredients", "testfacet Cocoa") != 1 {
+		t.Fatalf("Expected live products only to be counted but got %v\n", facets["ingredients"])
+	}
+
+	total, facets, found, success := model.FindFacets(ctx, &model.FacetFilter{
+		Properties: map[string][]string{"ingredients": {"TESTFACET  cocoa"}},
+	})
+	if !success || !found || total != 1 || count(facets, "ingredients", "testfacet Cream") != 1 ||
+		count(facets, "sourcing_values", "testfacet Fairtrade") != 1 ||
+		count(facets, "dietary_certifications", "testfacet Kosher") != 1 {
+		t.Fatalf("Expected facets of the product with the ingredient but got %d products\n", total)
+	}
+
+	total, facets, found, success = model.FindFacets(ctx, &model.FacetFilter{
+		Properties: map[string][]string{
+			"ingredients":     {"testfacet Cocoa"},
+			"sourcing_values": {"testfacet Other"},
+		},
+	})
+	if !success || !found || total != 0 || len(facets["ingredients"]) != 0 {
+		t.Fatalf("Expected no product to match every filter but got %d products\n", total)
+	}
+
+	if _, _, found, success = model.FindFacets(ctx, &model.FacetFilter{CategoryId: -1}); !success || found {
+		t.Fatalf("Expected an unknown category not to be found\n")
+	}
+}
@@ -0,0 +1,198 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"authenticator"
+	"bennjerry"
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"imagestore"
+	"logger"
+	"mysqlc"
+)
+
+func TestImageUpload(t *testing.T) {
+	/*
+		Testing Scenario: Uploading an image and a file that isn't one, creating two products referring to the image,
+		creating a product referring to an image of the storage never uploaded, saving a draft of a third product
+		referring to the image, then deleting the products permanently and discarding the draft
+		Expectation: The image is stored with its thumbnail and the other file is refused, the product referring to
+		a missing image is refused, the image is deleted only along with the last product referring to it and is
+		kept while a draft refers to it
+		** products and image created in this scenario are permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+	directory, err := ioutil.TempDir("", "images")
+	if err != nil {
+		t.Fatalf("Couldn't create directory %s\n", err.Error())
+	}
+	storage, err := imagestore.NewLocalStorage(directory, "http://testimage/images/")
+	if err != nil {
+		t.Fatalf("Couldn't create storage %s\n", err.Error())
+	}
+	imagestore.Store = storage
+	route := gin.Default()
+	route.POST("/catalog/images/", authenticator.IsAuthorized, bennjerry.UploadImage)
+	route.POST("/bennjerry/", authenticator.IsAuthorized, bennjerry.CreateData)
+	route.DELETE("/bennjerry/:product_id/", authenticator.IsAuthorized, bennjerry.DeleteData)
+	defer func() {
+		// Cleaning up products and image created for this scenario, if not deleted already
+		for _, productId := range []string{"testimage1", "testimage2", "testimage4"} {
+			if id, success := model.SelectIdFromProductByProductId(ctx, productId); success && id != 0 {
+				model.DropRecord(ctx, id)
+			}
+		}
+		imagestore.Store = nil
+		os.RemoveAll(directory)
+		mysqlc.DBClosing()
+	}()
+	jwtToken, tokenErr := authenticator.GenerateJWT()
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	upload := func(content []byte) *structs.ImageUploadResponse {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, partErr := writer.CreateFormFile(constants.ImageFormKeyName, "upload.png")
+		if partErr != nil {
+			t.Fatalf("Couldn't create form file: %v\n", partErr)
+		}
+		part.Write(content)
+		writer.Close()
+		req, reqErr := http.NewRequest(http.MethodPost, "/catalog/images/", body)
+		if reqErr != nil {
+			t.Fatalf("Couldn't create request: %v\n", reqErr)
+		}
+		req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+		req.Header.Add("Content-Type", writer.FormDataContentType())
+		recorder := httptest.NewRecorder()
+		route.ServeHTTP(recorder, req)
+		resp := &structs.ImageUploadResponse{}
+		if unMarshallErr := json.Unmarshal(recorder.Body.Bytes(), resp); unMarshallErr != nil {
+			t.Fatalf("Expected json response but got %s\n", recorder.Body.String())
+		}
+		return resp
+	}
+	serve := func(method string, path string, form url.Values) *structs.CreateUpdateDeleteResponse {
+		req, reqErr := http.NewRequest(method, path, bytes.NewBufferString(form.Encode()))
+		if reqErr != nil {
+			t.Fatalf("Couldn't create request: %v\n", reqErr)
+		}
+		req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Add("Content-Length", strconv.Itoa(len(form.Encode())))
+		recorder := httptest.NewRecorder()
+		route.ServeHTTP(recorder, req)
+		resp := &structs.CreateUpdateDeleteResponse{}
+		if unMarshallErr := json.Unmarshal(recorder.Body.Bytes(), resp); unMarshallErr != nil {
+			t.Fatalf("Expected json response but got %s\n", recorder.Body.String())
+		}
+		return resp
+	}
+	create := func(productId string, imageURL string) *structs.CreateUpdateDeleteResponse {
+		data, _ := json.Marshal(&structs.IceCreamDataStruct{ProductId: productId, Name: "Name of Ice Cream",
+			ImageClosed: imageURL, ImageOpened: "https://example.com/open.png"})
+		return serve(http.MethodPost, "/bennjerry/", url.Values{"data": {string(data)}})
+	}
+
+	if resp := upload([]byte("not an image")); resp.Success || resp.Message != constants.ImageTypeErrorMessage {
+		t.Fatalf("Expected file of another type to be refused but got %v\n", *resp)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	for x := 0; x < 640; x++ {
+		img.Set(x, x%480, color.RGBA{G: 255, A: 255})
+	}
+	encoded := &bytes.Buffer{}
+	if encodeErr := png.Encode(encoded, img); encodeErr != nil {
+		t.Fatalf("Couldn't encode image %s\n", encodeErr.Error())
+	}
+	resp := upload(encoded.Bytes())
+	if !resp.Success || resp.Data == nil || resp.Data.Width != 640 || resp.Data.Height != 480 ||
+		resp.Data.ContentType != "image/png" {
+		t.Fatalf("Expected png of 640x480 to be uploaded but got %v\n", *resp)
+	}
+	uploaded := resp.Data
+	key, _ := storage.KeyFromURL(uploaded.URL)
+	thumbnailKey, _ := storage.KeyFromURL(uploaded.ThumbnailURL)
+	for _, fileKey := range []string{key, thumbnailKey} {
+		if _, statErr := os.Stat(filepath.Join(directory, fileKey)); statErr != nil {
+			t.Fatalf("Expected file %s to be stored but got %s\n", fileKey, statErr.Error())
+		}
+	}
+
+	if resp := create("testimage1", uploaded.URL); !resp.Success {
+		t.Fatalf("Expected product referring to the image to be created but got %v\n", *resp)
+	}
+	if resp := create("testimage2", uploaded.URL); !resp.Success {
+		t.Fatalf("Expected second product referring to the image to be created but got %v\n", *resp)
+	}
+	if resp := create("testimage3", "http://testimage/images/"+thumbnailKey[:64]+".gif"); resp.Success ||
+		resp.Message != constants.InvalidImageErrorMessage {
+		t.Fatalf("Expected product referring to an image never uploaded to be refused but got %v\n", *resp)
+	}
+
+	expectImage := func(expected bool) {
+		images, success := model.SelectFromImageByKeys(ctx, []string{key})
+		_, statErr := os.Stat(filepath.Join(directory, thumbnailKey))
+		if !success || (len(images) == 1) != expected || (statErr == nil) != expected {
+			t.Fatalf("Expected image to exist: %v, but found %d rows and thumbnail error %v\n", expected,
+				len(images), statErr)
+		}
+	}
+	if resp := serve(http.MethodDelete, "/bennjerry/testimage1/?permanent=1", url.Values{}); !resp.Success {
+		t.Fatalf("Expected first product to be deleted but got %v\n", *resp)
+	}
+	expectImage(true)
+	if resp := serve(http.MethodDelete, "/bennjerry/testimage2/?permanent=1", url.Values{}); !resp.Success {
+		t.Fatalf("Expected second product to be deleted but got %v\n", *resp)
+	}
+	expectImage(false)
+
+	// An image only a draft refers to is kept, as the draft can still be published
+	resp = upload(encoded.Bytes())
+	if !resp.Success {
+		t.Fatalf("Expected image to be uploaded again but got %v\n", *resp)
+	}
+	uploaded = resp.Data
+	key, _ = storage.KeyFromURL(uploaded.URL)
+	if resp := create("testimage4", "https://example.com/closed.png"); !resp.Success {
+		t.Fatalf("Expected product without the image to be created but got %v\n", *resp)
+	}
+	id, _ := model.SelectIdFromProductByProductId(ctx, "testimage4")
+	if !model.SaveDraft(ctx, id, &structs.IceCreamDataStruct{ImageClosed: uploaded.URL},
+		map[string]bool{"image_closed": true}) {
+		t.Fatalf("Couldn't save draft referring to the image\n")
+	}
+	if count, success := model.SelectImageReferenceCount(ctx, uploaded.URL); !success || count != 1 {
+		t.Fatalf("Expected the draft to refer to the image but got %d references\n", count)
+	}
+	if deleted, success := model.DeleteUnReferencedImageByKey(ctx, key, uploaded.URL); !success || deleted {
+		t.Fatalf("Expected image referred by a draft not to be deleted\n")
+	}
+	if result := model.DiscardDraft(ctx, id); result != model.DraftChanged {
+		t.Fatalf("Expected draft to be discarded but got result %d\n", result)
+	}
+	if deleted, success := model.DeleteUnReferencedImageByKey(ctx, key, uploaded.URL); !success || !deleted {
+		t.Fatalf("Expected image to be deleted once the draft is discarded\n")
+	}
+}
@@ -0,0 +1,204 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"authenticator"
+	"bennjerry"
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"logger"
+	"mysqlc"
+)
+
+func TestIngredientListUnMarshal(t *testing.T) {
+	/*
+		Testing Scenario: Parsing ingredients given as names, as objects with percentage and mixed
+		Expectation: Ingredients in the same order, with percentage only where it's given
+	*/
+	var iceCreamData *structs.IceCreamDataStruct
+	postData := `{"ingredients": ["Cream", {"name": "Sugar", "percentage": 12.5}, {"name": "Cocoa"}]}`
+	if err := json.Unmarshal([]byte(postData), &iceCreamData); err != nil {
+		t.Fatalf("Error while parsing ingredients %s\n", err.Error())
+	}
+	ingredients := iceCreamData.Ingredients
+	if len(ingredients) != 3 || ingredients[0].Name != "Cream" || ingredients[0].Percentage != nil ||
+		ingredients[1].Name != "Sugar" || ingredients[1].Percentage == nil || *ingredients[1].Percentage != 12.5 ||
+		ingredients[2].Name != "Cocoa" || ingredients[2].Percentage != nil {
+		t.Fatalf("Expected ingredients Cream, Sugar (12.5%%), Cocoa but got %s\n", postData)
+	}
+}
+
+func TestIngredientPercentages(t *testing.T) {
+	/*
+		Testing Scenario: Parsing ingredients sent as names with their percentages in ingredient_percentages,
+		with fewer percentages than ingredients, then encoding ice cream data with ingredients
+		Expectation: Percentages are set in order, a missing one is refused, and ingredients are encoded as names
+		with their percentages in ingredient_percentages
+	*/
+	var iceCreamData *structs.IceCreamDataStruct
+	postData := `{"ingredients": ["Cream", "Sugar", "Cocoa"], "ingredient_percentages": [40.5, null, 2]}`
+	if err := json.Unmarshal([]byte(postData), &iceCreamData); err != nil {
+		t.Fatalf("Error while parsing ingredients %s\n", err.Error())
+	}
+	ingredients := iceCreamData.Ingredients
+	if len(ingredients) != 3 || ingredients[0].Percentage == nil || *ingredients[0].Percentage != 40.5 ||
+		ingredients[1].Percentage != nil || ingredients[2].Percentage == nil || *ingredients[2].Percentage != 2 {
+		t.Fatalf("Expected ingredients Cream (40.5%%), Sugar, Cocoa (2%%) but got %s\n", postData)
+	}
+
+	postData = `{"ingredients": ["Cream", "Sugar"], "ingredient_percentages": [40.5]}`
+	if err := json.Unmarshal([]byte(postData), &iceCreamData); err == nil {
+		t.Fatalf("Expected ingredient_percentages without a percentage for each ingredient to be refused\n")
+	}
+
+	encoded, marshalErr := json.Marshal(&structs.IceCreamDataStruct{Ingredients: ingredients})
+	if marshalErr != nil {
+		t.Fatalf("Error while encoding ingredients %s\n", marshalErr.Error())
+	}
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &decoded); err != nil ||
+		string(decoded["ingredients"]) != `["Cream","Sugar","Cocoa"]` ||
+		string(decoded["ingredient_percentages"]) != `[40.5,null,2]` {
+		t.Fatalf("Expected ingredients as names with their percentages but got %s\n", string(encoded))
+	}
+}
+
+func TestValidateIngredients(t *testing.T) {
+	/*
+		Testing Scenario: Validating ingredients without name, with a percentage out of range and with percentages
+		adding up to more than 100
+		Expectation: All of them are invalid, while named ingredients with some percentages are valid
+	*/
+	percentage := func(value float64) *float64 {
+		return &value
+	}
+	invalidIngredients := []structs.IngredientList{
+		{{Name: " "}},
+		{{Name: "Cream", Percentage: percentage(-1)}},
+		{{Name: "Cream", Percentage: percentage(60)}, {Name: "Sugar", Percentage: percentage(40.01)}},
+	}
+	for _, ingredients := range invalidIngredients {
+		if model.ValidateIngredients(ingredients) {
+			t.Fatalf("Expected ingredients %v to be invalid\n", ingredients.Names())
+		}
+	}
+	if !model.ValidateIngredients(structs.IngredientList{{Name: "Cream", Percentage: percentage(60)},
+		{Name: "Sugar", Percentage: percentage(40)}, {Name: "Cocoa"}}) {
+		t.Fatalf("Expected ingredients Cream (60%%), Sugar (40%%), Cocoa to be valid\n")
+	}
+}
+
+func TestIngredientOrder(t *testing.T) {
+	/*
+		Testing Scenario: Creating a product with ingredients not in alphabetical order, reading it, then updating
+		it with the ingredients reordered and reading it again in preview
+		Expectation: Ingredients and their percentages are read back in the exact order they were sent
+		** product created in this scenario is permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+	route := gin.Default()
+	route.POST("/bennjerry/", authenticator.IsAuthorized, bennjerry.CreateData)
+	route.GET("/bennjerry/:product_id/", authenticator.IsAuthorized, bennjerry.ReadData)
+	route.PUT("/bennjerry/:product_id/", authenticator.IsAuthorized, bennjerry.UpdateData)
+	defer func() {
+		// Cleaning up the product created for this scenario
+		if id, success := model.SelectIdFromProductByProductId(ctx, "testingredientorder123"); success && id != 0 {
+			model.DropRecord(ctx, id)
+		}
+		mysqlc.DBClosing()
+	}()
+
+	// Generating token for authorization
+	jwtToken, tokenErr := authenticator.GenerateJWT()
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	requests := []struct {
+		method      string
+		path        string
+		data        string
+		fields      string
+		expected    string
+		percentages string
+	}{
+		{
+			method: http.MethodPost,
+			path:   "/bennjerry/",
+			data: `{"productId": "testingredientorder123", "name": "Name of Ice Cream",` +
+				` "ingredients": ["Water", {"name": "Sugar", "percentage": 21.5}, "Milk", "Almonds"]}`,
+			expected:    `["Water","Sugar","Milk","Almonds"]`,
+			percentages: `[null,21.5,null,null]`,
+		},
+		{
+			method: http.MethodPut,
+			path:   "/bennjerry/testingredientorder123/",
+			data: `{"ingredients": ["Milk", "Almonds", "Sugar", "Cocoa"],` +
+				` "ingredient_percentages": [40, null, 20.25, null]}`,
+			fields:      "ingredients",
+			expected:    `["Milk","Almonds","Sugar","Cocoa"]`,
+			percentages: `[40,null,20.25,null]`,
+		},
+	}
+	for _, request := range requests {
+		// Creating mock request for create/update functionality
+		data := url.Values{}
+		data.Set("data", request.data)
+		if request.fields != "" {
+			data.Set("fields", request.fields)
+		}
+		req, reqErr := http.NewRequest(request.method, request.path, bytes.NewBufferString(data.Encode()))
+		if reqErr != nil {
+			t.Fatalf("Couldn't create request: %v\n", reqErr)
+		}
+		req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Add("Content-Length", strconv.Itoa(len(data.Encode())))
+		recorder := httptest.NewRecorder()
+		route.ServeHTTP(recorder, req)
+		resp := &structs.CreateUpdateDeleteResponse{}
+		if unMarshallErr := json.Unmarshal(recorder.Body.Bytes(), resp); unMarshallErr != nil || !resp.Success {
+			t.Fatalf("Expected success response for %s %s but got %s\n", request.method, request.path,
+				recorder.Body.String())
+		}
+
+		// Creating mock request for read functionality, in preview to read the edits saved in the draft as well
+		req, reqErr = http.NewRequest(http.MethodGet, "/bennjerry/testingredientorder123/?preview=1", nil)
+		if reqErr != nil {
+			t.Fatalf("Couldn't create request: %v\n", reqErr)
+		}
+		req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+		recorder = httptest.NewRecorder()
+		route.ServeHTTP(recorder, req)
+		respBytes, respErr := ioutil.ReadAll(recorder.Body)
+		if respErr != nil {
+			t.Fatalf("Error while reading response %s\n", respErr.Error())
+		}
+		readResp := &structs.ReadResponse{}
+		if unMarshallErr := json.Unmarshal(respBytes, readResp); unMarshallErr != nil || readResp.Data == nil {
+			t.Fatalf("Expected product to be read but got %s\n", string(respBytes))
+		}
+		// Comparing ingredients and their percentages as json, so that both order and percentages are checked
+		ingredients, marshalErr := json.Marshal(readResp.Data.Ingredients)
+		if marshalErr != nil || string(ingredients) != request.expected {
+			t.Fatalf("Expected ingredients %s but got %s\n", request.expected, string(ingredients))
+		}
+		percentages, marshalErr := json.Marshal(readResp.Data.Ingredients.Percentages())
+		if marshalErr != nil || string(percentages) != request.percentages {
+			t.Fatalf("Expected ingredient percentages %s but got %s\n", request.percentages, string(percentages))
+		}
+	}
+}
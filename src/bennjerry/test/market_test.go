@@ -0,0 +1,191 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"authenticator"
+	"bennjerry"
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"logger"
+	"mysqlc"
+)
+
+func TestValidateMarket(t *testing.T) {
+	/*
+		Testing Scenario: Validating market codes, and prices with invalid currencies, amounts and dates,
+		overlapping dates and several prices without end in a currency, and valid prices in other formats
+		Expectation: Invalid markets and prices are refused, valid ones are stored in upper case and dates formatted
+	*/
+	for market, expected := range map[string]string{"sg": "SG", " MY ": "MY"} {
+		if code, valid := model.CanonicalMarket(market); !valid || code != expected {
+			t.Fatalf("Expected market %q to be %q but got %q (valid %v)\n", market, expected, code, valid)
+		}
+	}
+	for _, market := range []string{"", "SGP", "S1", "*"} {
+		if code, valid := model.CanonicalMarket(market); valid {
+			t.Fatalf("Expected market %q to be invalid but got %q\n", market, code)
+		}
+	}
+	price := func(currency string, amount int64, from string, to string) *structs.PriceStruct {
+		return &structs.PriceStruct{Currency: currency, Amount: amount, EffectiveFrom: from, EffectiveTo: to}
+	}
+	invalidPrices := [][]*structs.PriceStruct{
+		{price("SG", 100, "2019-01-01 00:00:00", "")},
+		{price("S$D", 100, "2019-01-01 00:00:00", "")},
+		{price("SGD", -1, "2019-01-01 00:00:00", "")},
+		{price("SGD", 100, "2019-01-01", "")},
+		{price("SGD", 100, "2019-01-01 00:00:00", "2018-01-01 00:00:00")},
+		{price("SGD", 100, "2019-01-01 00:00:00", "2019-01-01 00:00:00")},
+		{price("SGD", 100, "2019-01-01 00:00:00", "2020-01-01 00:00:00"),
+			price("SGD", 200, "2019-06-01 00:00:00", "")},
+		{price("SGD", 100, "2019-01-01 00:00:00", ""), price("SGD", 200, "2020-01-01 00:00:00", "")},
+		{nil},
+	}
+	for _, prices := range invalidPrices {
+		if model.ValidateMarket(&structs.MarketStruct{Prices: prices}) {
+			t.Fatalf("Expected prices %v to be invalid\n", prices)
+		}
+	}
+	market := &structs.MarketStruct{Prices: []*structs.PriceStruct{
+		price("sgd", 1390, "2020-01-01 00:00:00", " "),
+		price(" usd", 990, "2019-06-01 00:00:00", ""),
+		price("SGD", 1290, "2019-01-01 00:00:00", "2020-01-01 00:00:00"),
+	}}
+	if !model.ValidateMarket(market) {
+		t.Fatalf("Expected contiguous prices in two currencies to be valid\n")
+	}
+	if market.Prices[0].Currency != "SGD" || market.Prices[0].EffectiveTo != "" || market.Prices[1].Currency != "USD" {
+		t.Fatalf("Expected currencies in upper case and no end as empty but got %v, %v\n", *market.Prices[0],
+			*market.Prices[1])
+	}
+}
+
+func TestProductMarket(t *testing.T) {
+	/*
+		Testing Scenario: Listing a product in a market with past, current and future prices, reading it in that
+		market, in a market it isn't listed in and in an invalid market, then removing it from the market
+		Expectation: Only prices effective now are read along with availability, a market the product isn't listed in
+		is unavailable without prices, all prices are listed by the markets api, overlapping prices are refused
+		** product created in this scenario is permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+	route := gin.Default()
+	route.GET("/bennjerry/:product_id/", authenticator.IsAuthorized, bennjerry.ReadData)
+	route.GET("/bennjerry/:product_id/markets/", authenticator.IsAuthorized, bennjerry.ReadMarkets)
+	route.PUT("/bennjerry/:product_id/markets/:market/", authenticator.IsAuthorized, bennjerry.UpdateMarket)
+	route.DELETE("/bennjerry/:product_id/markets/:market/", authenticator.IsAuthorized, bennjerry.DeleteMarket)
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{
+		{ProductId: "testmarket1", Name: "Name of Ice Cream"},
+	})
+	if !success || len(idList) != 1 {
+		t.Fatalf("Couldn't create product\n")
+	}
+	defer func() {
+		// Cleaning up the product created for this scenario, its markets and prices are deleted along
+		model.DropRecord(ctx, idList[0])
+		mysqlc.DBClosing()
+	}()
+	jwtToken, tokenErr := authenticator.GenerateJWT()
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	serve := func(method string, path string, form url.Values, resp interface{}) {
+		req, reqErr := http.NewRequest(method, path, bytes.NewBufferString(form.Encode()))
+		if reqErr != nil {
+			t.Fatalf("Couldn't create request: %v\n", reqErr)
+		}
+		req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Add("Content-Length", strconv.Itoa(len(form.Encode())))
+		recorder := httptest.NewRecorder()
+		route.ServeHTTP(recorder, req)
+		if unMarshallErr := json.Unmarshal(recorder.Body.Bytes(), resp); unMarshallErr != nil {
+			t.Fatalf("Expected json response but got %s\n", recorder.Body.String())
+		}
+	}
+
+	now := time.Now().UTC()
+	format := func(at time.Time) string {
+		return at.Format(constants.PriceTimeLayout)
+	}
+	market := &structs.MarketStruct{Available: true, Prices: []*structs.PriceStruct{
+		{Currency: "SGD", Amount: 1190, EffectiveFrom: format(now.AddDate(-1, 0, 0)),
+			EffectiveTo: format(now.AddDate(0, -1, 0))},
+		{Currency: "SGD", Amount: 1290, EffectiveFrom: format(now.AddDate(0, -1, 0)),
+			EffectiveTo: format(now.AddDate(0, 1, 0))},
+		{Currency: "SGD", Amount: 1390, EffectiveFrom: format(now.AddDate(0, 1, 0))},
+		{Currency: "USD", Amount: 950, EffectiveFrom: format(now.AddDate(-1, 0, 0))},
+	}}
+	data, _ := json.Marshal(market)
+	changed := &structs.CreateUpdateDeleteResponse{}
+	serve(http.MethodPut, "/bennjerry/testmarket1/markets/sg/", url.Values{"data": {string(data)}}, changed)
+	if !changed.Success {
+		t.Fatalf("Expected product to be listed in SG but got %v\n", *changed)
+	}
+	market.Prices[3].EffectiveFrom = format(now.AddDate(1, 0, 0))
+	market.Prices = append(market.Prices, &structs.PriceStruct{Currency: "USD", Amount: 900,
+		EffectiveFrom: format(now.AddDate(2, 0, 0))})
+	data, _ = json.Marshal(market)
+	changed = &structs.CreateUpdateDeleteResponse{}
+	serve(http.MethodPut, "/bennjerry/testmarket1/markets/SG/", url.Values{"data": {string(data)}}, changed)
+	if changed.Success || changed.Message != constants.InvalidPriceErrorMessage {
+		t.Fatalf("Expected two prices without end in USD to be refused but got %v\n", *changed)
+	}
+
+	read := &structs.ReadResponse{}
+	serve(http.MethodGet, "/bennjerry/testmarket1/?market=SG", url.Values{}, read)
+	if !read.Success || read.Data.Market == nil || read.Data.Market.Market != "SG" || !read.Data.Market.Available ||
+		len(read.Data.Market.Prices) != 2 || read.Data.Market.Prices[0].Amount != 1290 ||
+		read.Data.Market.Prices[1].Currency != "USD" || read.Data.Market.Prices[1].Amount != 950 {
+		t.Fatalf("Expected current prices in SGD and USD but got %v\n", read.Data.Market)
+	}
+	read = &structs.ReadResponse{}
+	serve(http.MethodGet, "/bennjerry/testmarket1/?market=my", url.Values{}, read)
+	if !read.Success || read.Data.Market == nil || read.Data.Market.Market != "MY" || read.Data.Market.Available ||
+		len(read.Data.Market.Prices) != 0 {
+		t.Fatalf("Expected product not to be available in MY but got %v\n", read.Data.Market)
+	}
+	read = &structs.ReadResponse{}
+	serve(http.MethodGet, "/bennjerry/testmarket1/?market=SGP", url.Values{}, read)
+	if read.Success || read.Message != constants.InvalidMarketErrorMessage {
+		t.Fatalf("Expected invalid market to be refused but got %v\n", read.Message)
+	}
+
+	markets := &structs.MarketListResponse{}
+	serve(http.MethodGet, "/bennjerry/testmarket1/markets/", url.Values{}, markets)
+	if !markets.Success || len(markets.Data) != 1 || len(markets.Data[0].Prices) != 4 ||
+		markets.Data[0].Prices[2].EffectiveTo != "" {
+		t.Fatalf("Expected all 4 prices in SG but got %v\n", markets.Data)
+	}
+
+	changed = &structs.CreateUpdateDeleteResponse{}
+	serve(http.MethodDelete, "/bennjerry/testmarket1/markets/SG/", url.Values{}, changed)
+	if !changed.Success {
+		t.Fatalf("Expected product to be removed from SG but got %v\n", *changed)
+	}
+	changed = &structs.CreateUpdateDeleteResponse{}
+	serve(http.MethodDelete, "/bennjerry/testmarket1/markets/SG/", url.Values{}, changed)
+	if changed.Success || changed.Message != constants.NoRecordsFoundMessage {
+		t.Fatalf("Expected product not to be listed in SG anymore but got %v\n", *changed)
+	}
+	read = &structs.ReadResponse{}
+	serve(http.MethodGet, "/bennjerry/testmarket1/", url.Values{}, read)
+	if !read.Success || read.Data.Market != nil {
+		t.Fatalf("Expected no market without asking for one but got %v\n", read.Data.Market)
+	}
+}
@@ -0,0 +1,194 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"authenticator"
+	"bennjerry"
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"logger"
+	"mysqlc"
+)
+
+func amount(value float64) *float64 {
+	return &value
+}
+
+func TestValidateNutrition(t *testing.T) {
+	/*
+		Testing Scenario: Validating nutrition facts without serving size, with unknown, repeated and negative
+		nutrients, units a nutrient can't be given in, more than 100 g can hold and no amount, and valid facts
+		given per serving and in other units
+		Expectation: Invalid facts are refused, valid ones are stored per 100 g in the unit of the nutrient,
+		in the order of the label, with amounts per serving
+	*/
+	nutrient := func(code string, unit string, per100g *float64, perServing *float64) *structs.NutrientStruct {
+		return &structs.NutrientStruct{Nutrient: code, Unit: unit, Per100g: per100g, PerServing: perServing}
+	}
+	facts := func(servingSize float64, nutrients ...*structs.NutrientStruct) *structs.NutritionStruct {
+		return &structs.NutritionStruct{ServingSize: servingSize, Nutrients: nutrients}
+	}
+	invalidFacts := map[string]*structs.NutritionStruct{
+		"no serving size":   facts(0, nutrient("sugar", "g", amount(20), nil)),
+		"huge serving size": facts(1001, nutrient("sugar", "g", amount(20), nil)),
+		"unknown nutrient":  facts(100, nutrient("sugar_g", "g", amount(20), nil)),
+		"repeated nutrient": facts(100, nutrient("sugar", "g", amount(20), nil), nutrient("Sugar", "mg", amount(20), nil)),
+		"negative amount":   facts(100, nutrient("fat", "g", amount(-1), nil)),
+		"invalid unit":      facts(100, nutrient("fat", "kcal", amount(1), nil)),
+		"over 100 g":        facts(50, nutrient("fat", "g", nil, amount(51))),
+		"no amount":         facts(100, nutrient("fat", "g", nil, nil)),
+		"nil nutrient":      facts(100, nil),
+	}
+	for name, invalid := range invalidFacts {
+		if model.ValidateNutrition(invalid) {
+			t.Fatalf("Expected nutrition with %s to be invalid\n", name)
+		}
+	}
+	if !model.ValidateNutrition(nil) {
+		t.Fatalf("Expected undeclared nutrition to be valid\n")
+	}
+	valid := facts(143, nutrient("sugar", "", nil, amount(28)), nutrient("sodium", "g", amount(0.1), nil),
+		nutrient(" ENERGY ", "kJ", amount(1046), nil))
+	if !model.ValidateNutrition(valid) {
+		t.Fatalf("Expected nutrition to be valid\n")
+	}
+	expected := []struct {
+		code       string
+		unit       string
+		per100g    float64
+		perServing float64
+	}{
+		{"energy", "kcal", 250, 357.5},
+		{"sodium", "mg", 100, 143},
+		{"sugar", "g", 19.58, 27.999},
+	}
+	for index, nutrient := range valid.Nutrients {
+		if nutrient.Nutrient != expected[index].code || nutrient.Unit != expected[index].unit ||
+			*nutrient.Per100g != expected[index].per100g || *nutrient.PerServing != expected[index].perServing {
+			t.Fatalf("Expected %v at %d but got %s %s %v %v\n", expected[index], index, nutrient.Nutrient,
+				nutrient.Unit, *nutrient.Per100g, *nutrient.PerServing)
+		}
+	}
+}
+
+func TestProductNutrition(t *testing.T) {
+	/*
+		Testing Scenario: Creating a product with nutrition facts per serving, reading it, updating the facts
+		with invalid ones and removing them, then publishing the removal and rolling back to the first revision
+		Expectation: Facts are read per 100 g and per serving, invalid facts are refused, removed facts are read as
+		null in preview and once published, and restored by the rollback
+		** product created in this scenario is permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+	route := gin.Default()
+	route.POST("/bennjerry/", authenticator.IsAuthorized, bennjerry.CreateData)
+	route.GET("/bennjerry/:product_id/", authenticator.IsAuthorized, bennjerry.ReadData)
+	route.PUT("/bennjerry/:product_id/", authenticator.IsAuthorized, bennjerry.UpdateData)
+	route.POST("/bennjerry/:product_id/revisions/:revision/rollback/", authenticator.IsAuthorized,
+		bennjerry.RollbackRevision)
+	defer func() {
+		// Cleaning up the product created for this scenario, its nutrition facts are deleted along
+		if id, success := model.SelectIdFromProductByProductId(ctx, "testnutrition1"); success && id != 0 {
+			model.DropRecord(ctx, id)
+		}
+		mysqlc.DBClosing()
+	}()
+	jwtToken, tokenErr := authenticator.GenerateJWT()
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	serve := func(method string, path string, form url.Values, resp interface{}) {
+		req, reqErr := http.NewRequest(method, path, bytes.NewBufferString(form.Encode()))
+		if reqErr != nil {
+			t.Fatalf("Couldn't create request: %v\n", reqErr)
+		}
+		req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Add("Content-Length", strconv.Itoa(len(form.Encode())))
+		recorder := httptest.NewRecorder()
+		route.ServeHTTP(recorder, req)
+		if unMarshallErr := json.Unmarshal(recorder.Body.Bytes(), resp); unMarshallErr != nil {
+			t.Fatalf("Expected json response but got %s\n", recorder.Body.String())
+		}
+	}
+	read := func(preview bool) *structs.NutritionStruct {
+		resp := &structs.ReadResponse{}
+		path := "/bennjerry/testnutrition1/"
+		if preview {
+			path += "?preview=1"
+		}
+		serve(http.MethodGet, path, url.Values{}, resp)
+		if !resp.Success {
+			t.Fatalf("Expected product to be read but got %s\n", resp.Message)
+		}
+		return resp.Data.Nutrition
+	}
+
+	data, _ := json.Marshal(&structs.IceCreamDataStruct{ProductId: "testnutrition1", Name: "Name of Ice Cream",
+		Nutrition: &structs.NutritionStruct{ServingSize: 143, Nutrients: []*structs.NutrientStruct{
+			{Nutrient: "sugar", Unit: "g", PerServing: amount(28)},
+			{Nutrient: "energy", Unit: "kcal", Per100g: amount(250)},
+		}}})
+	created := &structs.CreateUpdateDeleteResponse{}
+	serve(http.MethodPost, "/bennjerry/", url.Values{"data": {string(data)}}, created)
+	if !created.Success {
+		t.Fatalf("Expected product with nutrition to be created but got %v\n", *created)
+	}
+	facts := read(false)
+	if facts == nil || facts.ServingSize != 143 || len(facts.Nutrients) != 2 || facts.Nutrients[0].Nutrient != "energy" ||
+		*facts.Nutrients[0].PerServing != 357.5 || facts.Nutrients[1].Nutrient != "sugar" ||
+		*facts.Nutrients[1].Per100g != 19.58 {
+		t.Fatalf("Expected energy and sugar per 100 g and per serving but got %v\n", facts)
+	}
+
+	updated := &structs.CreateUpdateDeleteResponse{}
+	serve(http.MethodPut, "/bennjerry/testnutrition1/", url.Values{"fields": {"nutrition"},
+		"data": {`{"nutrition": {"serving_size_g": 0, "nutrients": []}}`}}, updated)
+	if updated.Success || updated.Message != constants.InvalidNutritionErrorMessage {
+		t.Fatalf("Expected nutrition without serving size to be refused but got %v\n", *updated)
+	}
+	updated = &structs.CreateUpdateDeleteResponse{}
+	serve(http.MethodPut, "/bennjerry/testnutrition1/", url.Values{"fields": {"nutrition"},
+		"data": {`{"nutrition": null}`}}, updated)
+	if !updated.Success {
+		t.Fatalf("Expected nutrition to be removed but got %v\n", *updated)
+	}
+	if facts := read(true); facts != nil {
+		t.Fatalf("Expected no nutrition in preview but got %v\n", facts)
+	}
+	if facts := read(false); facts == nil {
+		t.Fatalf("Expected nutrition not to be removed before the draft is published\n")
+	}
+	// Publishing the draft, i.e. submitting it, approving it and applying it, this will be stored as revision 2
+	id, _ := model.SelectIdFromProductByProductId(ctx, "testnutrition1")
+	model.ChangeDraftStatus(ctx, id, constants.DraftStatusDraft, constants.DraftStatusInReview)
+	model.ChangeDraftStatus(ctx, id, constants.DraftStatusInReview, constants.DraftStatusApproved)
+	if result := model.PublishDraft(ctx, id); result != model.DraftChanged {
+		t.Fatalf("Expected removal of nutrition to be published but got result %d\n", result)
+	}
+	if facts := read(false); facts != nil {
+		t.Fatalf("Expected no nutrition once published but got %v\n", facts)
+	}
+
+	rolledBack := &structs.CreateUpdateDeleteResponse{}
+	serve(http.MethodPost, "/bennjerry/testnutrition1/revisions/1/rollback/", url.Values{}, rolledBack)
+	if !rolledBack.Success {
+		t.Fatalf("Expected rollback to the first revision but got %v\n", *rolledBack)
+	}
+	if facts := read(false); facts == nil || len(facts.Nutrients) != 2 || *facts.Nutrients[1].Per100g != 19.58 {
+		t.Fatalf("Expected nutrition of the first revision but got %v\n", facts)
+	}
+}
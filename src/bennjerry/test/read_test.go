@@ -143,16 +143,17 @@ func TestReadData(t *testing.T) {
 
 		// Comparing received response with expected response
 		expectedIceCreamData := &structs.IceCreamDataStruct{
-			ProductId:             "test123",
-			Name:                  "Name of Ice Cream",
-			ImageClosed:           "Link of closed image",
-			ImageOpened:           "Link of open image",
-			Description:           "Description of Ice Cream",
-			Story:                 "Story of Ice Cream",
-			SourcingValues:        []string{"List", "of", "sourcing", "values"},
-			Ingredients:           []string{"List", "of", "ingredients"},
-			AllergyInfo:           "Allergy related information",
-			DietaryCertifications: "Name of dietary certifications",
+			ProductId:      "test123",
+			Name:           "Name of Ice Cream",
+			ImageClosed:    "Link of closed image",
+			ImageOpened:    "Link of open image",
+			Description:    "Description of Ice Cream",
+			Story:          "Story of Ice Cream",
+			SourcingValues: []string{"List", "of", "sourcing", "values"},
+			Ingredients:    structs.IngredientList{{Name: "List"}, {Name: "of"}, {Name: "ingredients"}},
+			AllergyInfo:    "Allergy related information",
+			// created by create test, which still sends a single name as string
+			DietaryCertifications: []string{"Name of dietary certifications"},
 		}
 		isDataMatching := resp.Data != nil && resp.Data.ProductId == expectedIceCreamData.ProductId &&
 			resp.Data.Name == expectedIceCreamData.Name && resp.Data.ImageClosed == expectedIceCreamData.ImageClosed &&
@@ -160,9 +161,9 @@ func TestReadData(t *testing.T) {
 			resp.Data.Description == expectedIceCreamData.Description &&
 			resp.Data.Story == expectedIceCreamData.Story &&
 			utils.ListOfStringCompare(resp.Data.SourcingValues, expectedIceCreamData.SourcingValues) &&
-			utils.ListOfStringCompare(resp.Data.Ingredients, expectedIceCreamData.Ingredients) &&
+			utils.ListOfStringCompare(resp.Data.Ingredients.Names(), expectedIceCreamData.Ingredients.Names()) &&
 			resp.Data.AllergyInfo == expectedIceCreamData.AllergyInfo &&
-			resp.Data.DietaryCertifications == expectedIceCreamData.DietaryCertifications
+			utils.ListOfStringCompare(resp.Data.DietaryCertifications, expectedIceCreamData.DietaryCertifications)
 
 		if !resp.Success || !isDataMatching || resp.Message != constants.ReadSuccessMessage {
 			t.Fatalf("Expected response {success: true, data: %v, message: %s} but got"+
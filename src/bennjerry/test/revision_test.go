@@ -0,0 +1,214 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"authenticator"
+	"bennjerry"
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"logger"
+	"mysqlc"
+	"utils"
+)
+
+func TestReadRevisionUnAuthorized(t *testing.T) {
+	/*
+		Test Scenario: Calling read revision api without auth token in header
+		Expectation: Response with status code 401
+	*/
+	mysqlc.Init()
+	logger.Init()
+	route := gin.Default()
+	route.GET("/bennjerry/:product_id/revisions/:revision/", authenticator.IsAuthorized, bennjerry.ReadRevision)
+
+	// Creating mock request for read revision functionality
+	req, reqErr := http.NewRequest(http.MethodGet, "/bennjerry/test123/revisions/1/", nil)
+	if reqErr != nil {
+		t.Fatalf("Couldn't create request: %v\n", reqErr)
+	}
+
+	// Creating a response recorder to inspect the response
+	recorder := httptest.NewRecorder()
+
+	// Performing the request
+	route.ServeHTTP(recorder, req)
+
+	// Checking to see if the response was what you expected
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected status code %d but got %d\n", http.StatusUnauthorized, recorder.Code)
+	}
+	mysqlc.DBClosing()
+}
+
+func TestReadRevisionNoRecordFound(t *testing.T) {
+	/*
+		Testing Scenario: Calling read revision api with product_id that doesn't exist in DB
+		Expectation: Appropriate error response
+	*/
+	mysqlc.Init()
+	logger.Init()
+	route := gin.Default()
+	route.GET("/bennjerry/:product_id/revisions/:revision/", authenticator.IsAuthorized, bennjerry.ReadRevision)
+
+	// Creating mock request for read revision functionality
+	req, reqErr := http.NewRequest(http.MethodGet, "/bennjerry/test456/revisions/1/", nil)
+	if reqErr != nil {
+		t.Fatalf("Couldn't create request: %v\n", reqErr)
+	}
+	// Generating token for authorization
+	jwtToken, tokenErr := authenticator.GenerateJWT()
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+
+	// Creating a response recorder to inspect the response
+	recorder := httptest.NewRecorder()
+
+	// Performing the request
+	route.ServeHTTP(recorder, req)
+
+	// Checking to see if the response was what you expected
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d\n", http.StatusOK, recorder.Code)
+	} else {
+		respBytes, respErr := ioutil.ReadAll(recorder.Body)
+		if respErr != nil {
+			t.Fatalf("Error while reading response %s\n", respErr.Error())
+		}
+		resp := &structs.RevisionResponse{}
+		unMarshallErr := json.Unmarshal(respBytes, resp)
+		if unMarshallErr != nil {
+			t.Fatalf("Error while parsing response %s\n", unMarshallErr.Error())
+		}
+		if resp.Success || resp.Data != nil || resp.Message != constants.NoRecordsFoundMessage {
+			t.Fatalf("Expected response {success: false, data: nil, message: %s} but got {success: %v,"+
+				" data: %v, message: %s}\n", constants.NoRecordsFoundMessage, resp.Success, resp.Data, resp.Message)
+		}
+	}
+	mysqlc.DBClosing()
+}
+
+func TestRollbackRevision(t *testing.T) {
+	/*
+		Testing Scenario: Creating a product, updating it and then rolling it back to its first revision
+		Expectation: Success response and product data in DB same as it was when created
+		** product created in this scenario is permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	route := gin.Default()
+	route.PUT("/bennjerry/:product_id/", authenticator.IsAuthorized, bennjerry.UpdateData)
+	route.POST("/bennjerry/:product_id/revisions/:revision/rollback/", authenticator.IsAuthorized,
+		bennjerry.RollbackRevision)
+	ctx := context.Background()
+
+	// Creating the product that will be rolled back
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{{
+		ProductId:      "testrevision123",
+		Name:           "Name of Ice Cream",
+		Story:          "Story of Ice Cream",
+		SourcingValues: []string{"List", "of", "sourcing", "values"},
+		Ingredients:    structs.IngredientList{{Name: "List"}, {Name: "of"}, {Name: "ingredients"}},
+	}})
+	if !success || len(idList) == 0 {
+		t.Fatalf("Couldn't create product to roll back\n")
+	}
+	defer func() {
+		// Cleaning up the product created for this scenario
+		model.DropRecord(ctx, idList[0])
+		mysqlc.DBClosing()
+	}()
+
+	// Generating token for authorization
+	jwtToken, tokenErr := authenticator.GenerateJWT()
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+
+	// Creating mock request for update functionality, saving the edits in the draft of the product
+	postData := []byte(`{
+			"name": "New Name of Ice Cream",
+			"ingredients": ["New", "ingredients"]
+	}`)
+	data := url.Values{}
+	data.Set("data", string(postData))
+	data.Set("fields", "name,ingredients")
+	req, reqErr := http.NewRequest(http.MethodPut, "/bennjerry/testrevision123/",
+		bytes.NewBufferString(data.Encode()))
+	if reqErr != nil {
+		t.Fatalf("Couldn't create request: %v\n", reqErr)
+	}
+	req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Content-Length", strconv.Itoa(len(data.Encode())))
+	recorder := httptest.NewRecorder()
+	route.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d\n", http.StatusOK, recorder.Code)
+	}
+	// Publishing the draft, i.e. submitting it, approving it and applying it, this will be stored as revision 2
+	model.ChangeDraftStatus(ctx, idList[0], constants.DraftStatusDraft, constants.DraftStatusInReview)
+	model.ChangeDraftStatus(ctx, idList[0], constants.DraftStatusInReview, constants.DraftStatusApproved)
+	if result := model.PublishDraft(ctx, idList[0]); result != model.DraftChanged {
+		t.Fatalf("Expected draft to be published but got result %d\n", result)
+	}
+	if productData, _ := model.SelectFromProductByProductId(ctx, "testrevision123"); productData == nil ||
+		productData.Name != "New Name of Ice Cream" {
+		t.Fatalf("Expected name %s once published but got %v\n", "New Name of Ice Cream", productData)
+	}
+
+	// Creating mock request for rollback functionality
+	req, reqErr = http.NewRequest(http.MethodPost, "/bennjerry/testrevision123/revisions/1/rollback/", nil)
+	if reqErr != nil {
+		t.Fatalf("Couldn't create request: %v\n", reqErr)
+	}
+	req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+
+	// Creating a response recorder to inspect the response
+	recorder = httptest.NewRecorder()
+
+	// Performing the request
+	route.ServeHTTP(recorder, req)
+
+	// Checking to see if the response was what you expected
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d\n", http.StatusOK, recorder.Code)
+	} else {
+		respBytes, respErr := ioutil.ReadAll(recorder.Body)
+		if respErr != nil {
+			t.Fatalf("Error while reading response %s\n", respErr.Error())
+		}
+		resp := &structs.CreateUpdateDeleteResponse{}
+		unMarshallErr := json.Unmarshal(respBytes, resp)
+		if unMarshallErr != nil {
+			t.Fatalf("Error while parsing response %s\n", unMarshallErr.Error())
+		}
+		if !resp.Success || resp.Id != idList[0] || resp.Message != constants.RollbackSuccessMessage {
+			t.Fatalf("Expected response {success: true, id: %d, message: %s} but got"+
+				" {success: %v, id: %d, message: %s}\n", idList[0], constants.RollbackSuccessMessage, resp.Success,
+				resp.Id, resp.Message)
+		}
+		productData, success := model.SelectFromProductByProductId(ctx, "testrevision123")
+		if !success || productData.Name != "Name of Ice Cream" {
+			t.Fatalf("Expected name to be rolled back to %s but got %v\n", "Name of Ice Cream", productData)
+		}
+		ingredients := model.SelectIngredientFromProductIngredientByProductIdPK(ctx, idList[0]).Names()
+		if !utils.ListOfStringCompare(ingredients, []string{"List", "of", "ingredients"}) {
+			t.Fatalf("Expected ingredients to be rolled back to %v but got %v\n",
+				[]string{"List", "of", "ingredients"}, ingredients)
+		}
+	}
+}
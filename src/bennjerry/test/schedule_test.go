@@ -0,0 +1,119 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"logger"
+	"mysqlc"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	/*
+		Testing Scenario: Validating schedules with empty, invalid and reversed times
+		Expectation: Empty times and unpublish_at after publish_at are valid, times are stored trimmed
+	*/
+	testCases := []struct {
+		name        string
+		publishAt   string
+		unpublishAt string
+		valid       bool
+	}{
+		{"not scheduled", "", "", true},
+		{"publish only", " 2019-10-01 00:00:00 ", "", true},
+		{"unpublish only", "", "2019-12-31 23:59:59", true},
+		{"publish and unpublish", "2019-10-01 00:00:00", "2019-12-31 23:59:59", true},
+		{"invalid time", "2019-10-01", "", false},
+		{"invalid date", "2019-13-01 00:00:00", "", false},
+		{"unpublish before publish", "2019-12-31 23:59:59", "2019-10-01 00:00:00", false},
+		{"unpublish at publish", "2019-10-01 00:00:00", "2019-10-01 00:00:00", false},
+	}
+	for _, testCase := range testCases {
+		iceCreamData := &structs.IceCreamDataStruct{PublishAt: testCase.publishAt, UnpublishAt: testCase.unpublishAt}
+		if valid := model.ValidateSchedule(iceCreamData); valid != testCase.valid {
+			t.Errorf("%s: expected valid: %v but got %v\n", testCase.name, testCase.valid, valid)
+		}
+	}
+	iceCreamData := &structs.IceCreamDataStruct{PublishAt: " 2019-10-01 00:00:00 "}
+	if model.ValidateSchedule(iceCreamData); iceCreamData.PublishAt != "2019-10-01 00:00:00" {
+		t.Errorf("Expected publish_at to be trimmed but got %q\n", iceCreamData.PublishAt)
+	}
+}
+
+func TestScheduledPublishing(t *testing.T) {
+	/*
+		Testing Scenario: Creating a product published in an hour, publishing it a minute ago instead,
+		emitting events with a failing receiver, then twice with a working one, and once more after it expired
+		Expectation: Product isn't shown before publish_at, is shown after it, its published event is emitted
+		again after the failure, exactly once afterwards, and its unpublished event once it expired
+		** product created in this scenario is permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{{
+		ProductId:   "testschedule1",
+		Name:        "Name of Ice Cream",
+		PublishAt:   now.Add(time.Hour).Format(constants.ScheduleTimeLayout),
+		UnpublishAt: now.Add(2 * time.Hour).Format(constants.ScheduleTimeLayout),
+	}})
+	if !success || len(idList) != 1 {
+		t.Fatalf("Couldn't create scheduled product\n")
+	}
+	defer func() {
+		// Cleaning up the product created for this scenario, its events are deleted along
+		model.DropRecord(ctx, idList[0])
+		mysqlc.DBClosing()
+	}()
+
+	if product, success := model.SelectFromProductByProductId(ctx, "testschedule1"); !success ||
+		product.ProductId != "" {
+		t.Fatalf("Expected product not to be shown before publish_at but got %v\n", product)
+	}
+	publishAt := now.Add(-time.Minute).Format(constants.ScheduleTimeLayout)
+	success = model.UpdateRecord(ctx, idList[0], &structs.IceCreamDataStruct{PublishAt: publishAt},
+		map[string]bool{"publish_at": true})
+	if product, _ := model.SelectFromProductByProductId(ctx, "testschedule1"); !success ||
+		product.ProductId != "testschedule1" || product.PublishAt != publishAt {
+		t.Fatalf("Expected product to be shown after publish_at but got %v\n", product)
+	}
+
+	// Events of the product emitted by a call, events of other products are let through
+	emitted := func(at time.Time, fail bool) ([]string, bool) {
+		events := make([]string, 0)
+		_, success := model.EmitScheduleEvents(ctx, at, func(event *structs.ScheduleEventStruct) error {
+			if event.ProductId != "testschedule1" {
+				return nil
+			}
+			if fail {
+				return errors.New("receiver unavailable")
+			}
+			events = append(events, event.Event+" "+event.ScheduledAt)
+			return nil
+		})
+		return events, success
+	}
+	if events, success := emitted(now, true); success || len(events) != 0 {
+		t.Fatalf("Expected emitting to fail but got %v\n", events)
+	}
+	expected := constants.ScheduleEventProductPublished + " " + publishAt
+	if events, success := emitted(now, false); !success || len(events) != 1 || events[0] != expected {
+		t.Fatalf("Expected event %s to be emitted again but got %v\n", expected, events)
+	}
+	if events, success := emitted(now, false); !success || len(events) != 0 {
+		t.Fatalf("Expected no event to be emitted twice but got %v\n", events)
+	}
+	expected = constants.ScheduleEventProductUnpublished + " " +
+		now.Add(2*time.Hour).Format(constants.ScheduleTimeLayout)
+	if events, success := emitted(now.Add(3*time.Hour), false); !success || len(events) != 1 ||
+		events[0] != expected {
+		t.Fatalf("Expected event %s after expiry but got %v\n", expected, events)
+	}
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"bennjerry/model"
+	"bennjerry/structs"
+	"logger"
+	"mysqlc"
+	"similarity"
+)
+
+func TestSimilarProducts(t *testing.T) {
+	/*
+		Testing Scenario: Creating three products, two sharing ingredients and a certification, ranking products
+		similar to the first, then changing ingredients of the third and ranking them again
+		Expectation: Products sharing more are ranked first, the change is seen right away as it refreshes the cache,
+		and a product that doesn't exist isn't found
+		** products created in this scenario are permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{{
+		ProductId:             "testsimilar1",
+		Name:                  "Name of Ice Cream",
+		Ingredients:           structs.IngredientList{{Name: "testsimilar Cocoa"}, {Name: "testsimilar Cream"}},
+		DietaryCertifications: []string{"testsimilar Kosher"},
+	}, {
+		ProductId:             "testsimilar2",
+		Name:                  "Name of Ice Cream",
+		Ingredients:           structs.IngredientList{{Name: "testsimilar Cocoa"}, {Name: "testsimilar Cream"}},
+		DietaryCertifications: []string{"testsimilar Kosher"},
+	}, {
+		ProductId:   "testsimilar3",
+		Name:        "Name of Ice Cream",
+		Ingredients: structs.IngredientList{{Name: "testsimilar Vanilla"}},
+	}})
+	if !success || len(idList) != 3 {
+		t.Fatalf("Couldn't create products\n")
+	}
+	defer func() {
+		// Cleaning up the products and vocabulary entries created for this scenario
+		for _, id := range idList {
+			model.DropRecord(ctx, id)
+		}
+		model.CleanUpUnUsed(ctx, false)
+		mysqlc.DBClosing()
+	}()
+
+	// Product ids of the test products similar to the first one, in order of rank
+	similar := func() []string {
+		matches, found, success := similarity.Similar(ctx, "testsimilar1", 50)
+		if !success || !found {
+			t.Fatalf("Expected products similar to testsimilar1 to be found\n")
+		}
+		productIds := make([]string, 0)
+		for _, match := range matches {
+			if match.Item.ProductId == "testsimilar2" || match.Item.ProductId == "testsimilar3" {
+				productIds = append(productIds, match.Item.ProductId)
+			}
+		}
+		return productIds
+	}
+	if productIds := similar(); len(productIds) != 1 || productIds[0] != "testsimilar2" {
+		t.Fatalf("Expected only testsimilar2 to be similar but got %v\n", productIds)
+	}
+
+	success = model.UpdateRecord(ctx, idList[2], &structs.IceCreamDataStruct{
+		Ingredients: structs.IngredientList{{Name: "testsimilar Cocoa"}},
+	}, map[string]bool{"ingredients": true})
+	if productIds := similar(); !success || len(productIds) != 2 || productIds[0] != "testsimilar2" ||
+		productIds[1] != "testsimilar3" {
+		t.Fatalf("Expected testsimilar2, testsimilar3 to be similar after the update but got %v\n", productIds)
+	}
+
+	if _, found, success := similarity.Similar(ctx, "testsimilar0", 10); !success || found {
+		t.Fatalf("Expected a product that doesn't exist not to be found\n")
+	}
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"authenticator"
+	"bennjerry"
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"logger"
+	"mysqlc"
+)
+
+func TestProductTranslation(t *testing.T) {
+	/*
+		Testing Scenario: Translating a product to ms and one of its ingredients to id, reading it with and without
+		Accept-Language, then deleting the translation of the product and reading it again
+		Expectation: Translated texts where they exist along the chain (ms falls back to id), stored texts for
+		the rest, Content-Language listing the locales used, and stored texts only once the translation is deleted
+		** product and ingredients created in this scenario are permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+	route := gin.Default()
+	route.GET("/bennjerry/:product_id/", authenticator.IsAuthorized, bennjerry.ReadData)
+	route.GET("/bennjerry/:product_id/translations/", authenticator.IsAuthorized, bennjerry.ReadTranslations)
+	route.PUT("/bennjerry/:product_id/translations/:locale/", authenticator.IsAuthorized, bennjerry.UpdateTranslation)
+	route.DELETE("/bennjerry/:product_id/translations/:locale/", authenticator.IsAuthorized,
+		bennjerry.DeleteTranslation)
+	route.PUT("/catalog/vocabularies/:vocabulary/:id/translations/:locale/", authenticator.IsAuthorized,
+		bennjerry.UpdateVocabularyTranslation)
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{
+		{ProductId: "testtranslation1", Name: "Name of Ice Cream", Description: "Description of Ice Cream",
+			Story: "Story of Ice Cream", Ingredients: structs.IngredientList{{Name: "testtranslation Milk"},
+				{Name: "testtranslation Sugar"}}},
+	})
+	if !success || len(idList) != 1 {
+		t.Fatalf("Couldn't create product with ingredients\n")
+	}
+	defer func() {
+		// Cleaning up the product and ingredients created for this scenario
+		model.DropRecord(ctx, idList[0])
+		model.CleanUpUnUsed(ctx, false)
+		mysqlc.DBClosing()
+	}()
+	jwtToken, tokenErr := authenticator.GenerateJWT()
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	serve := func(method string, path string, form url.Values, acceptLanguage string) *httptest.ResponseRecorder {
+		req, reqErr := http.NewRequest(method, path, bytes.NewBufferString(form.Encode()))
+		if reqErr != nil {
+			t.Fatalf("Couldn't create request: %v\n", reqErr)
+		}
+		req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Add("Content-Length", strconv.Itoa(len(form.Encode())))
+		if acceptLanguage != "" {
+			req.Header.Add(constants.AcceptLanguageHeaderName, acceptLanguage)
+		}
+		recorder := httptest.NewRecorder()
+		route.ServeHTTP(recorder, req)
+		return recorder
+	}
+	expectChange := func(method string, path string, form url.Values) {
+		recorder := serve(method, path, form, "")
+		resp := &structs.CreateUpdateDeleteResponse{}
+		if unMarshallErr := json.Unmarshal(recorder.Body.Bytes(), resp); unMarshallErr != nil || !resp.Success {
+			t.Fatalf("Expected success response for %s %s but got %s\n", method, path, recorder.Body.String())
+		}
+	}
+	read := func(acceptLanguage string) (*structs.IceCreamDataStruct, string) {
+		recorder := serve(http.MethodGet, "/bennjerry/testtranslation1/", url.Values{}, acceptLanguage)
+		resp := &structs.ReadResponse{}
+		if unMarshallErr := json.Unmarshal(recorder.Body.Bytes(), resp); unMarshallErr != nil || resp.Data == nil {
+			t.Fatalf("Expected product to be read but got %s\n", recorder.Body.String())
+		}
+		return resp.Data, recorder.Header().Get("Content-Language")
+	}
+
+	// Refusing translations to the default locale, whose texts are stored in the product itself
+	recorder := serve(http.MethodPut, "/bennjerry/testtranslation1/translations/en/",
+		url.Values{"data": {`{"name": "Name"}`}}, "")
+	resp := &structs.CreateUpdateDeleteResponse{}
+	if unMarshallErr := json.Unmarshal(recorder.Body.Bytes(), resp); unMarshallErr != nil || resp.Success ||
+		resp.Message != constants.InvalidLocaleErrorMessage {
+		t.Fatalf("Expected translation to the default locale to be refused but got %s\n", recorder.Body.String())
+	}
+	expectChange(http.MethodPut, "/bennjerry/testtranslation1/translations/ms/",
+		url.Values{"data": {`{"name": "Nama Ais Krim", "story": "Kisah Ais Krim"}`}})
+	milkId := 0
+	entries, success := model.SelectVocabularyWithUsage(ctx, model.Vocabularies["ingredients"])
+	for _, entry := range entries {
+		if entry.Name == "testtranslation Milk" {
+			milkId = entry.Id
+		}
+	}
+	if !success || milkId == 0 {
+		t.Fatalf("Couldn't find the ingredient created\n")
+	}
+	expectChange(http.MethodPut, "/catalog/vocabularies/ingredients/"+strconv.Itoa(milkId)+"/translations/id/",
+		url.Values{"name": {"testtranslation Susu"}})
+
+	translations := &structs.TranslationListResponse{}
+	recorder = serve(http.MethodGet, "/bennjerry/testtranslation1/translations/", url.Values{}, "")
+	if unMarshallErr := json.Unmarshal(recorder.Body.Bytes(), translations); unMarshallErr != nil ||
+		len(translations.Data) != 1 || translations.Data[0].Locale != "ms" ||
+		translations.Data[0].Name != "Nama Ais Krim" || translations.Data[0].Description != "" {
+		t.Fatalf("Expected translation to ms only but got %s\n", recorder.Body.String())
+	}
+
+	data, contentLanguage := read("ms-MY, en;q=0.5")
+	if data.Name != "Nama Ais Krim" || data.Story != "Kisah Ais Krim" || data.Description != "Description of Ice Cream" ||
+		data.Ingredients.Names()[0] != "testtranslation Susu" || data.Ingredients.Names()[1] != "testtranslation Sugar" ||
+		contentLanguage != "ms, id, en" {
+		t.Fatalf("Expected texts in ms, id and en but got %v, %v (Content-Language %q)\n", *data,
+			data.Ingredients.Names(), contentLanguage)
+	}
+	data, contentLanguage = read("")
+	if data.Name != "Name of Ice Cream" || data.Ingredients.Names()[0] != "testtranslation Milk" ||
+		contentLanguage != constants.DefaultLocale {
+		t.Fatalf("Expected stored texts but got %v, %v (Content-Language %q)\n", *data, data.Ingredients.Names(),
+			contentLanguage)
+	}
+
+	expectChange(http.MethodDelete, "/bennjerry/testtranslation1/translations/ms/", url.Values{})
+	data, _ = read("ms")
+	if data.Name != "Name of Ice Cream" || data.Ingredients.Names()[0] != "testtranslation Susu" {
+		t.Fatalf("Expected stored name and ingredient in id after deleting translation but got %v, %v\n", *data,
+			data.Ingredients.Names())
+	}
+}
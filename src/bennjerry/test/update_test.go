@@ -2,11 +2,13 @@ package test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sort"
 	"strconv"
 	"testing"
 
@@ -14,10 +16,12 @@ import (
 
 	"authenticator"
 	"bennjerry"
+	"bennjerry/model"
 	"bennjerry/structs"
 	"constants"
 	"logger"
 	"mysqlc"
+	"utils"
 )
 
 func TestUpdateDataUnAuthorized(t *testing.T) {
@@ -230,8 +234,8 @@ func TestUpdateDataNoRecordFound(t *testing.T) {
 
 func TestUpdateData(t *testing.T) {
 	/*
-		Testing Scenario: Calling create api with correct request data and headers
-		Expectation: Success response and new data in DB
+		Testing Scenario: Calling update api with correct request data and headers
+		Expectation: Success response and new data saved in the draft of the product
 	*/
 	mysqlc.Init()
 	logger.Init()
@@ -279,11 +283,83 @@ func TestUpdateData(t *testing.T) {
 		if unMarshallErr != nil {
 			t.Fatalf("Error while parsing response %s\n", unMarshallErr.Error())
 		}
-		if !resp.Success || resp.Id == 0 || resp.Message != constants.UpdateSuccessMessage {
+		if !resp.Success || resp.Id == 0 || resp.Message != constants.DraftSaveSuccessMessage {
 			t.Fatalf("Expected response {success: true, id: non-zero, message: %s} but got"+
-				" {sucess: %v, id: %d, message: %s}\n", constants.UpdateSuccessMessage, resp.Success, resp.Id,
+				" {sucess: %v, id: %d, message: %s}\n", constants.DraftSaveSuccessMessage, resp.Success, resp.Id,
 				resp.Message)
 		}
 	}
 	mysqlc.DBClosing()
 }
+
+func TestUpdateRecordImageOpenedAndAllergyInfo(t *testing.T) {
+	/*
+		Testing Scenario: Creating a product, then updating its opened image and allergy info
+		Expectation: Both fields are stored in their columns (image_opened and allergy) of product table
+		** product created in this scenario is permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{{
+		ProductId: "testupdatecolumns123",
+		Name:      "Name of Ice Cream",
+	}})
+	if !success || len(idList) == 0 {
+		t.Fatalf("Couldn't create product to update\n")
+	}
+	defer func() {
+		// Cleaning up the product created for this scenario
+		model.DropRecord(ctx, idList[0])
+		mysqlc.DBClosing()
+	}()
+
+	success = model.UpdateRecord(ctx, idList[0], &structs.IceCreamDataStruct{
+		ImageOpened: "new-open.png",
+		AllergyInfo: "may contain wheat",
+	}, map[string]bool{"image_open": true, "allergy_info": true})
+	if !success {
+		t.Fatalf("Expected opened image and allergy info to be updated\n")
+	}
+	productData, success := model.SelectFromProductByProductId(ctx, "testupdatecolumns123")
+	if !success || productData == nil || productData.ImageOpened != "new-open.png" ||
+		productData.Allergy != "may contain wheat" {
+		t.Fatalf("Expected opened image and allergy info to be stored but got %v\n", productData)
+	}
+}
+
+func TestUpdateRecordNewSourcingValue(t *testing.T) {
+	/*
+		Testing Scenario: Creating a product, then updating its sourcing values with one it didn't have before
+		Expectation: Update doesn't panic and both the kept and the new sourcing value are stored
+		** product created in this scenario is permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{{
+		ProductId:      "testupdatesourcing123",
+		Name:           "Name of Ice Cream",
+		SourcingValues: []string{"Fairtrade"},
+	}})
+	if !success || len(idList) == 0 {
+		t.Fatalf("Couldn't create product to update\n")
+	}
+	defer func() {
+		// Cleaning up the product created for this scenario
+		model.DropRecord(ctx, idList[0])
+		mysqlc.DBClosing()
+	}()
+
+	success = model.UpdateRecord(ctx, idList[0], &structs.IceCreamDataStruct{
+		SourcingValues: []string{"Fairtrade", "Caring Dairy"},
+	}, map[string]bool{"sourcing_values": true})
+	// Sourcing values are not ordered, comparing them sorted
+	sourcingValues := model.SelectSourcingValueNameByProductIdPK(ctx, idList[0])
+	sort.Strings(sourcingValues)
+	if !success || !utils.ListOfStringCompare(sourcingValues, []string{"Caring Dairy", "Fairtrade"}) {
+		t.Fatalf("Expected sourcing values %v but got %v\n", []string{"Caring Dairy", "Fairtrade"}, sourcingValues)
+	}
+}
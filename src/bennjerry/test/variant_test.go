@@ -0,0 +1,165 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"authenticator"
+	"bennjerry"
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"logger"
+	"mysqlc"
+)
+
+func TestValidateVariant(t *testing.T) {
+	/*
+		Testing Scenario: Validating variants without sku or name, with skus not usable in urls, sizes <= 0,
+		unknown units, unknown nutrients and nutrition amounts not possible per 100 g, and a valid variant in other cases
+		Expectation: Invalid variants are refused, the valid one is stored trimmed, its unit and nutrients in lower case
+	*/
+	variant := func(sku string, name string, size float64, unit string,
+		nutrition map[string]float64) *structs.VariantStruct {
+		return &structs.VariantStruct{Sku: sku, Name: name, Size: size, SizeUnit: unit, Nutrition: nutrition}
+	}
+	invalidVariants := map[string]*structs.VariantStruct{
+		"no sku":            variant(" ", "Pint", 473, "ml", nil),
+		"sku with slash":    variant("BJ/PINT", "Pint", 473, "ml", nil),
+		"sku of dots":       variant("..", "Pint", 473, "ml", nil),
+		"no name":           variant("BJ-PINT", "", 473, "ml", nil),
+		"no size":           variant("BJ-PINT", "Pint", 0, "ml", nil),
+		"unknown unit":      variant("BJ-PINT", "Pint", 16, "oz", nil),
+		"empty nutrient":    variant("BJ-PINT", "Pint", 473, "ml", map[string]float64{" ": 1}),
+		"unknown nutrient":  variant("BJ-PINT", "Pint", 473, "ml", map[string]float64{"sugar_g": 1}),
+		"negative nutrient": variant("BJ-PINT", "Pint", 473, "ml", map[string]float64{"sugar": -1}),
+		"over 100 g":        variant("BJ-PINT", "Pint", 473, "ml", map[string]float64{"fat": 101}),
+		"repeated nutrient": variant("BJ-PINT", "Pint", 473, "ml", map[string]float64{"fat": 1, "FAT": 2}),
+	}
+	for name, invalidVariant := range invalidVariants {
+		if model.ValidateVariant(invalidVariant) {
+			t.Fatalf("Expected variant with %s to be invalid\n", name)
+		}
+	}
+	valid := variant(" BJ-2190.Pint_1 ", " Pint ", 473, " ML ", map[string]float64{" Sugar ": 21.5, "fat": 0})
+	if !model.ValidateVariant(valid) {
+		t.Fatalf("Expected variant to be valid\n")
+	}
+	if valid.Sku != "BJ-2190.Pint_1" || valid.Name != "Pint" || valid.SizeUnit != "ml" || len(valid.Nutrition) != 2 ||
+		valid.Nutrition["sugar"] != 21.5 {
+		t.Fatalf("Expected variant trimmed with unit and nutrients in lower case but got %v\n", *valid)
+	}
+}
+
+func TestProductVariant(t *testing.T) {
+	/*
+		Testing Scenario: Adding two variants to a product, adding one with a sku used already, changing a variant,
+		changing a variant of another product through this one, reading the product and deleting a variant
+		Expectation: Variants are read nested in the product in the order they were added, with their nutrition
+		values, skus are unique, variants are changed and deleted only through their own product
+		** products created in this scenario are permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+	route := gin.Default()
+	route.GET("/bennjerry/:product_id/", authenticator.IsAuthorized, bennjerry.ReadData)
+	route.GET("/bennjerry/:product_id/variants/", authenticator.IsAuthorized, bennjerry.ReadVariants)
+	route.POST("/bennjerry/:product_id/variants/", authenticator.IsAuthorized, bennjerry.CreateVariant)
+	route.PUT("/bennjerry/:product_id/variants/:sku/", authenticator.IsAuthorized, bennjerry.UpdateVariant)
+	route.DELETE("/bennjerry/:product_id/variants/:sku/", authenticator.IsAuthorized, bennjerry.DeleteVariant)
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{
+		{ProductId: "testvariant1", Name: "Name of Ice Cream"},
+		{ProductId: "testvariant2", Name: "Name of another Ice Cream"},
+	})
+	if !success || len(idList) != 2 {
+		t.Fatalf("Couldn't create products\n")
+	}
+	defer func() {
+		// Cleaning up the products created for this scenario, their variants are deleted along
+		for _, id := range idList {
+			model.DropRecord(ctx, id)
+		}
+		mysqlc.DBClosing()
+	}()
+	jwtToken, tokenErr := authenticator.GenerateJWT()
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	serve := func(method string, path string, form url.Values, resp interface{}) {
+		req, reqErr := http.NewRequest(method, path, bytes.NewBufferString(form.Encode()))
+		if reqErr != nil {
+			t.Fatalf("Couldn't create request: %v\n", reqErr)
+		}
+		req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Add("Content-Length", strconv.Itoa(len(form.Encode())))
+		recorder := httptest.NewRecorder()
+		route.ServeHTTP(recorder, req)
+		if unMarshallErr := json.Unmarshal(recorder.Body.Bytes(), resp); unMarshallErr != nil {
+			t.Fatalf("Expected json response but got %s\n", recorder.Body.String())
+		}
+	}
+	change := func(method string, path string, variant *structs.VariantStruct) *structs.CreateUpdateDeleteResponse {
+		data, _ := json.Marshal(variant)
+		resp := &structs.CreateUpdateDeleteResponse{}
+		serve(method, path, url.Values{"data": {string(data)}}, resp)
+		return resp
+	}
+
+	pint := &structs.VariantStruct{Sku: "TESTVARIANT1-PINT", Name: "Pint", Size: 473, SizeUnit: "ml",
+		Nutrition: map[string]float64{"sugar": 21.5, "fat": 14}}
+	bar := &structs.VariantStruct{Sku: "TESTVARIANT1-BAR", Name: "Bar", Size: 80, SizeUnit: "g"}
+	for _, variant := range []*structs.VariantStruct{pint, bar} {
+		if resp := change(http.MethodPost, "/bennjerry/testvariant1/variants/", variant); !resp.Success {
+			t.Fatalf("Expected variant %s to be added but got %v\n", variant.Sku, *resp)
+		}
+	}
+	if resp := change(http.MethodPost, "/bennjerry/testvariant2/variants/", pint); resp.Success ||
+		resp.Message != constants.VariantSkuExistsMessage {
+		t.Fatalf("Expected sku used already to be refused but got %v\n", *resp)
+	}
+	pint.Size = 465
+	pint.Nutrition = map[string]float64{"sugar": 20}
+	if resp := change(http.MethodPut, "/bennjerry/testvariant1/variants/TESTVARIANT1-PINT/", pint); !resp.Success {
+		t.Fatalf("Expected variant to be changed but got %v\n", *resp)
+	}
+	if resp := change(http.MethodPut, "/bennjerry/testvariant2/variants/TESTVARIANT1-PINT/", pint); resp.Success ||
+		resp.Message != constants.NoRecordsFoundMessage {
+		t.Fatalf("Expected variant of another product not to be found but got %v\n", *resp)
+	}
+
+	read := &structs.ReadResponse{}
+	serve(http.MethodGet, "/bennjerry/testvariant1/", url.Values{}, read)
+	if !read.Success || len(read.Data.Variants) != 2 || read.Data.Variants[0].Sku != pint.Sku ||
+		read.Data.Variants[0].Size != 465 || len(read.Data.Variants[0].Nutrition) != 1 ||
+		read.Data.Variants[0].Nutrition["sugar"] != 20 || read.Data.Variants[1].Sku != bar.Sku ||
+		read.Data.Variants[1].SizeUnit != "g" || len(read.Data.Variants[1].Nutrition) != 0 {
+		t.Fatalf("Expected the changed pint and the bar but got %v\n", read.Data)
+	}
+
+	deleted := &structs.CreateUpdateDeleteResponse{}
+	serve(http.MethodDelete, "/bennjerry/testvariant2/variants/TESTVARIANT1-BAR/", url.Values{}, deleted)
+	if deleted.Success || deleted.Message != constants.NoRecordsFoundMessage {
+		t.Fatalf("Expected variant of another product not to be deleted but got %v\n", *deleted)
+	}
+	deleted = &structs.CreateUpdateDeleteResponse{}
+	serve(http.MethodDelete, "/bennjerry/testvariant1/variants/TESTVARIANT1-BAR/", url.Values{}, deleted)
+	if !deleted.Success {
+		t.Fatalf("Expected variant to be deleted but got %v\n", *deleted)
+	}
+	variants := &structs.VariantListResponse{}
+	serve(http.MethodGet, "/bennjerry/testvariant1/variants/", url.Values{}, variants)
+	if !variants.Success || len(variants.Data) != 1 || variants.Data[0].Sku != pint.Sku {
+		t.Fatalf("Expected only the pint to be left but got %v\n", variants.Data)
+	}
+}
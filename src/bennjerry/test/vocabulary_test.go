@@ -0,0 +1,166 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"authenticator"
+	"bennjerry"
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"logger"
+	"mysqlc"
+	"utils"
+)
+
+func TestVocabularyEntryChanges(t *testing.T) {
+	/*
+		Testing Scenario: Listing, deleting, renaming and merging ingredients used by products
+		Expectation: Usage is counted, entries in use aren't deleted, renaming to an existing name is refused,
+		products carry renamed and merged entries in the same position
+		** products and ingredients created in this scenario are permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+	route := gin.Default()
+	route.GET("/catalog/vocabularies/:vocabulary/", authenticator.IsAuthorized, bennjerry.ReadVocabulary)
+	vocabulary := model.Vocabularies["ingredients"]
+
+	idList, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{
+		{ProductId: "testvocabulary1", Name: "Name of Ice Cream",
+			Ingredients: structs.IngredientList{{Name: "testvocabulary Cacao"}, {Name: "testvocabulary Milk"}}},
+		{ProductId: "testvocabulary2", Name: "Name of Ice Cream",
+			Ingredients: structs.IngredientList{{Name: "testvocabulary Cocoa"}}},
+	})
+	if !success || len(idList) != 2 {
+		t.Fatalf("Couldn't create products with ingredients\n")
+	}
+	defer func() {
+		// Cleaning up the products and ingredients created for this scenario
+		for _, id := range idList {
+			model.DropRecord(ctx, id)
+		}
+		model.CleanUpUnUsed(ctx, false)
+		mysqlc.DBClosing()
+	}()
+
+	// Listing ingredients through the api to find ids and usage of the ones created above
+	req, reqErr := http.NewRequest(http.MethodGet, "/catalog/vocabularies/ingredients/", nil)
+	if reqErr != nil {
+		t.Fatalf("Couldn't create request: %v\n", reqErr)
+	}
+	jwtToken, tokenErr := authenticator.GenerateJWT()
+	if tokenErr != nil {
+		t.Fatalf("Couldn't generate token %s\n", tokenErr.Error())
+	}
+	req.Header.Add(constants.JWTTokenKeyNameInHeader, jwtToken)
+	recorder := httptest.NewRecorder()
+	route.ServeHTTP(recorder, req)
+	resp := &structs.VocabularyListResponse{}
+	if unMarshallErr := json.Unmarshal(recorder.Body.Bytes(), resp); unMarshallErr != nil || !resp.Success {
+		t.Fatalf("Expected success response but got %s\n", recorder.Body.String())
+	}
+	entryMap := make(map[string]*structs.VocabularyEntryStruct)
+	for _, entry := range resp.Data {
+		entryMap[entry.Name] = entry
+	}
+	cacao, cocoa := entryMap["testvocabulary Cacao"], entryMap["testvocabulary Cocoa"]
+	if cacao == nil || cocoa == nil || cacao.ProductCount != 1 || cocoa.ProductCount != 1 {
+		t.Fatalf("Expected ingredients Cacao and Cocoa used by 1 product each but got %v and %v\n", cacao, cocoa)
+	}
+
+	if result := model.DeleteVocabularyEntry(ctx, vocabulary, cacao.Id); result != model.VocabularyInUse {
+		t.Fatalf("Expected ingredient in use not to be deleted but got result %d\n", result)
+	}
+	// Names are matched by their normalized key, e.g. ignoring case
+	result := model.RenameVocabularyEntry(ctx, vocabulary, cacao.Id, "testvocabulary COCOA")
+	if result != model.VocabularyNameExists {
+		t.Fatalf("Expected rename to an existing name to be refused but got result %d\n", result)
+	}
+	result = model.RenameVocabularyEntry(ctx, vocabulary, cacao.Id, "testvocabulary Cacao powder")
+	ingredients := model.SelectIngredientFromProductIngredientByProductIdPK(ctx, idList[0]).Names()
+	expected := []string{"testvocabulary Cacao powder", "testvocabulary Milk"}
+	if result != model.VocabularyChanged || !utils.ListOfStringCompare(ingredients, expected) {
+		t.Fatalf("Expected ingredients %v after rename but got %v (result %d)\n", expected, ingredients, result)
+	}
+
+	result = model.MergeVocabularyEntry(ctx, vocabulary, cacao.Id, cocoa.Id)
+	ingredients = model.SelectIngredientFromProductIngredientByProductIdPK(ctx, idList[0]).Names()
+	expected = []string{"testvocabulary Cocoa", "testvocabulary Milk"}
+	if result != model.VocabularyChanged || !utils.ListOfStringCompare(ingredients, expected) {
+		t.Fatalf("Expected ingredients %v after merge but got %v (result %d)\n", expected, ingredients, result)
+	}
+	if result = model.DeleteVocabularyEntry(ctx, vocabulary, cacao.Id); result != model.VocabularyNotFound {
+		t.Fatalf("Expected merged ingredient to be deleted but got result %d\n", result)
+	}
+
+	id, result := model.CreateVocabularyEntry(ctx, vocabulary, "testvocabulary Vanilla")
+	if result != model.VocabularyChanged || id == 0 {
+		t.Fatalf("Expected ingredient to be created but got result %d\n", result)
+	}
+	if result = model.DeleteVocabularyEntry(ctx, vocabulary, id); result != model.VocabularyChanged {
+		t.Fatalf("Expected unused ingredient to be deleted but got result %d\n", result)
+	}
+}
+
+func TestNormalizedVocabularyMatching(t *testing.T) {
+	/*
+		Testing Scenario: Creating products with variants of the same ingredient and sourcing value names,
+		differing in case and whitespace, then updating one of them with another variant
+		Expectation: Every variant refers to the entry of the name as first stored, trimmed,
+		and the update keeps the product's entry instead of replacing it
+		** products and ingredients created in this scenario are permanently deleted at the end
+	*/
+	mysqlc.Init()
+	logger.Init()
+	ctx := context.Background()
+
+	// Creating the products one at a time, so that the names of the first one are stored
+	idList := make([]int, 0)
+	defer func() {
+		// Cleaning up the products and ingredients created for this scenario
+		for _, id := range idList {
+			model.DropRecord(ctx, id)
+		}
+		model.CleanUpUnUsed(ctx, false)
+		mysqlc.DBClosing()
+	}()
+	for _, iceCream := range []*structs.IceCreamDataStruct{
+		{ProductId: "testnormalized1", Name: "Name of Ice Cream", SourcingValues: []string{"  testnormalized Fairtrade"},
+			Ingredients: structs.IngredientList{{Name: " testnormalized  Milk "}, {Name: "testnormalized Cream"}}},
+		{ProductId: "testnormalized2", Name: "Name of Ice Cream", SourcingValues: []string{"TESTNORMALIZED FAIRTRADE"},
+			Ingredients: structs.IngredientList{{Name: "TESTNORMALIZED MILK"}, {Name: "testnormalized milk"}}},
+	} {
+		ids, success := model.InsertRecord(ctx, []*structs.IceCreamDataStruct{iceCream})
+		if !success || len(ids) != 1 {
+			t.Fatalf("Couldn't create product %s\n", iceCream.ProductId)
+		}
+		idList = append(idList, ids...)
+	}
+
+	ingredients := model.SelectIngredientFromProductIngredientByProductIdPK(ctx, idList[1]).Names()
+	expected := []string{"testnormalized Milk"}
+	if !utils.ListOfStringCompare(ingredients, expected) {
+		t.Fatalf("Expected ingredients %v but got %v\n", expected, ingredients)
+	}
+	sourcingValues := model.SelectSourcingValueNameByProductIdPK(ctx, idList[1])
+	expected = []string{"testnormalized Fairtrade"}
+	if !utils.ListOfStringCompare(sourcingValues, expected) {
+		t.Fatalf("Expected sourcing values %v but got %v\n", expected, sourcingValues)
+	}
+
+	success := model.UpdateRecord(ctx, idList[0], &structs.IceCreamDataStruct{
+		SourcingValues: []string{"testnormalized fairtrade "},
+	}, map[string]bool{"sourcing_values": true})
+	sourcingValues = model.SelectSourcingValueNameByProductIdPK(ctx, idList[0])
+	if !success || !utils.ListOfStringCompare(sourcingValues, expected) {
+		t.Fatalf("Expected sourcing values %v after update but got %v\n", expected, sourcingValues)
+	}
+}
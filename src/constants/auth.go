@@ -6,4 +6,14 @@ const (
 	JWTTokenParseErrorMessage = "Error while parsing token"
 	IsAuthorizedKeyName       = "is_authorized"
 	UnAuthorizedErrorMessage  = "You are unauthorized to call this api"
+	AuthFailureMissingToken   = "missing_token"
+	AuthFailureInvalidToken   = "invalid_token"
+	AuthFailureForbiddenRole  = "forbidden_role"
+	// Claim of the token with the role of the user, dropped in ginContext object under the same key
+	RoleKeyName                 = "role"
+	RoleEditor                  = "editor"
+	RoleReviewer                = "reviewer"
+	RoleAdmin                   = "admin"
+	ReviewerRoleRequiredMessage = "Only reviewers are allowed to call this api"
+	AdminRoleRequiredMessage    = "Only admins and reviewers are allowed to call this api"
 )
@@ -8,4 +8,43 @@ const (
 	SoftDeleteSuccessMessage      = "Successfully soft deleted"
 	PermanentDeleteSuccessMessage = "Successfully permanently deleted"
 	NoRecordsFoundMessage         = "No records found"
+	RollbackSuccessMessage        = "Successfully rolled back"
+	InvalidAllergenErrorMessage   = "Unknown allergen code or level"
+	InvalidIngredientErrorMessage = "Ingredient name missing or percentages not between 0 and 100"
+	MergeSuccessMessage           = "Successfully merged"
+	UnknownVocabularyErrorMessage = "Unknown vocabulary, expected ingredients, sourcing_values, dietary_certifications" +
+		" or tags"
+	VocabularyNameExistsMessage = "An entry with this name already exists, merge the entries instead"
+	VocabularyInUseMessage      = "Entry is used by products, merge it into another entry instead"
+	InvalidLocaleErrorMessage   = "Invalid locale, expected a language tag other than the default locale, e.g. ms-MY"
+	InvalidMarketErrorMessage   = "Invalid market, expected a country code, e.g. SG"
+	InvalidPriceErrorMessage    = "Invalid prices, expected currency codes (e.g. SGD), amounts in minor units >= 0 and" +
+		" dates as 2006-01-02 15:04:05 (UTC), effective_from before effective_to, not overlapping per currency"
+	VariantSkuExistsMessage    = "A variant with this sku already exists"
+	InvalidVariantErrorMessage = "Invalid variant, expected a sku (letters, digits, - _ .), a name, a size > 0 in ml," +
+		" l, g or kg and nutrition amounts >= 0 per 100 g by nutrient (e.g. sugar)"
+	InvalidNutritionErrorMessage = "Invalid nutrition, expected a serving size > 0 (g) and known nutrients (e.g. sugar)" +
+		" once each, in a unit of the nutrient, with amounts >= 0 per_100g or per_serving"
+	InvalidCategoryErrorMessage = "Unknown category, expected paths of existing categories, e.g." +
+		" \"Non-Dairy > Frozen Dessert\""
+	InvalidTagErrorMessage          = "Tag name missing"
+	CategoryNameExistsMessage       = "A category with this name already exists in the parent category"
+	CategoryInUseMessage            = "Category has subcategories or products, move or delete them first"
+	InvalidCategoryNameErrorMessage = "Invalid category, expected a name without > and an existing parent category," +
+		" not the category itself or one of its subcategories, at most 8 levels deep"
+	InvalidProductFilterErrorMessage = "Expected a category id and/or a tag name to find products by"
+	InvalidFacetFilterErrorMessage   = "Invalid filter, expected a category id and known allergen codes"
+	InvalidSimilarLimitErrorMessage  = "Invalid limit, expected a number of similar products from 1 to 50"
+	InvalidScheduleErrorMessage      = "Invalid schedule, expected publish_at and unpublish_at as 2006-01-02 15:04:05" +
+		" (UTC) or empty, unpublish_at after publish_at"
+	DraftSaveSuccessMessage = "Successfully saved as draft"
+	SubmitSuccessMessage    = "Successfully submitted for review"
+	ApproveSuccessMessage   = "Successfully approved"
+	RejectSuccessMessage    = "Successfully sent back to draft"
+	PublishSuccessMessage   = "Successfully published"
+	DraftStatusErrorMessage = "Draft isn't in the expected status, expected draft to submit, in_review to approve" +
+		" or reject and approved to publish"
+	// Levels of an allergen in a product, as stored in product_allergen.level
+	AllergenLevelContains   = "contains"
+	AllergenLevelMayContain = "may_contain"
 )
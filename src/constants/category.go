@@ -0,0 +1,13 @@
+package constants
+
+const (
+	// Separator of the names of a category and its parents in its path, e.g. "Non-Dairy > Frozen Dessert"
+	CategoryPathSeparator = " > "
+	// Character refused in names of categories, as it separates them in paths
+	CategoryPathSeparatorChar = ">"
+	// Deepest nesting of categories, a root category is at depth 1
+	CategoryMaxDepth = 8
+	// Query params of the api finding products by category (id) and tag (name)
+	CategoryQueryParamName = "category"
+	TagQueryParamName      = "tag"
+)
@@ -1,11 +1,31 @@
 package constants
 
+import "time"
+
 const (
-	GenericErrorMessage           = "Something went wrong"
-	JsonSerializerType            = "json"
-	JsonSerializationErrorMessage = "JSON serialization error"
-	JsonContentType               = "application/json; charset=utf-8"
-	ServerHost                    = "0.0.0.0"
-	ServerPort                    = "8080"
-	UnMarshalErrorString          = "Error while un-marshalling data"
+	GenericErrorMessage                 = "Something went wrong"
+	JsonSerializerType                  = "json"
+	JsonSerializationErrorMessage       = "JSON serialization error"
+	JsonContentType                     = "application/json; charset=utf-8"
+	ServerHost                          = "0.0.0.0"
+	ServerPort                          = "8080"
+	ServerReadHeaderTimeout             = 5 * time.Second
+	ServerReadTimeout                   = 15 * time.Second
+	ServerWriteTimeout                  = 30 * time.Second
+	ServerIdleTimeout                   = 60 * time.Second
+	ServerShutdownTimeout               = 30 * time.Second
+	ServerReadHeaderTimeoutEnvVarName   = "SERVER_READ_HEADER_TIMEOUT_SECONDS"
+	ServerReadTimeoutEnvVarName         = "SERVER_READ_TIMEOUT_SECONDS"
+	ServerWriteTimeoutEnvVarName        = "SERVER_WRITE_TIMEOUT_SECONDS"
+	ServerIdleTimeoutEnvVarName         = "SERVER_IDLE_TIMEOUT_SECONDS"
+	ServerShutdownTimeoutEnvVarName     = "SERVER_SHUTDOWN_TIMEOUT_SECONDS"
+	ServerLogBucketName                 = "server"
+	ServerStartErrorMessage             = "Error while starting server"
+	ServerShutDownMessage               = "Shutting down server"
+	ServerDrainErrorMessage             = "Error while draining in-flight requests"
+	ServerTransactionsDrainErrorMessage = "Timed out waiting for open mysql transactions"
+	ServerStoppedMessage                = "Server stopped"
+	UnMarshalErrorString                = "Error while un-marshalling data"
+	MetricsContentType                  = "text/plain; version=0.0.4; charset=utf-8"
+	MetricsUnmatchedRoute               = "unmatched"
 )
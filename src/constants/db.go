@@ -1,14 +1,40 @@
 package constants
 
+import "time"
+
 const (
-	MySQLDBName                 = "bennjerry"
-	MySQLMaxOpenConnection      = 5
-	MySQLMaxIdleConnection      = 5
-	MySQLPassword               = "password"
-	MySQLUserName               = "root"
-	MySQLQueryRunErrorMessage   = "Error while running mysql query"
-	MySQLSelectScanErrorMessage = "Error while scanning select query data"
-	DockerMySQLHostString       = "tcp(db:3306)"
-	DockerMySQLModeEnvVarName   = "Mode"
-	DockerMySQLModeEnvVarValue  = "release"
+	MySQLDBName                      = "bennjerry"
+	MySQLMaxOpenConnection           = 5
+	MySQLMaxIdleConnection           = 5
+	MySQLPassword                    = "password"
+	MySQLUserName                    = "root"
+	MySQLQueryRunErrorMessage        = "Error while running mysql query"
+	MySQLSelectScanErrorMessage      = "Error while scanning select query data"
+	MySQLCommitErrorMessage          = "Error while committing mysql transaction"
+	MySQLRollbackErrorMessage        = "Error while rolling back mysql transaction"
+	MySQLBeginErrorMessage           = "Error while beginning mysql transaction"
+	MySQLTransactionCancelledMessage = "Mysql transaction rolled back as its context is done"
+	MySQLTransactionClosingMessage   = "Mysql transaction refused as the server is shutting down"
+	MySQLTransactionInsert           = "insert"
+	MySQLTransactionUpdate           = "update"
+	MySQLTransactionDrop             = "drop"
+	MySQLTransactionCleanUp          = "cleanup"
+	MySQLTransactionVocabulary       = "vocabulary"
+	MySQLTransactionMarket           = "market"
+	MySQLTransactionVariant          = "variant"
+	MySQLTransactionCategory         = "category"
+	MySQLTransactionSchedule         = "schedule"
+	MySQLTransactionDraft            = "draft"
+	MySQLTransactionCommitted        = "commit"
+	MySQLTransactionRolledBack       = "rollback"
+	MySQLTransactionCommitFailed     = "commit_error"
+	MySQLTransactionCancelled        = "cancelled"
+	// Default deadlines for a single query and for a whole transaction
+	MySQLQueryTimeout                 = 5 * time.Second
+	MySQLTransactionTimeout           = 15 * time.Second
+	MySQLQueryTimeoutEnvVarName       = "MYSQL_QUERY_TIMEOUT_SECONDS"
+	MySQLTransactionTimeoutEnvVarName = "MYSQL_TRANSACTION_TIMEOUT_SECONDS"
+	DockerMySQLHostString             = "tcp(db:3306)"
+	DockerMySQLModeEnvVarName         = "Mode"
+	DockerMySQLModeEnvVarValue        = "release"
 )
@@ -0,0 +1,10 @@
+package constants
+
+const (
+	// Statuses of the draft of a product, as stored in product_draft.status
+	DraftStatusDraft    = "draft"
+	DraftStatusInReview = "in_review"
+	DraftStatusApproved = "approved"
+	// Query param of the read api to preview the draft of a product instead of its published version
+	PreviewQueryParamName = "preview"
+)
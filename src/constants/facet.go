@@ -0,0 +1,9 @@
+package constants
+
+const (
+	// Query params of the facets api, besides category and tag, each can be given several times
+	IngredientQueryParamName           = "ingredient"
+	SourcingValueQueryParamName        = "sourcing_value"
+	DietaryCertificationQueryParamName = "dietary_certification"
+	FreeFromQueryParamName             = "free_from"
+)
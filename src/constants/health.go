@@ -0,0 +1,14 @@
+package constants
+
+import "time"
+
+const (
+	HealthCheckTimeout             = 2 * time.Second
+	HealthCheckDatabase            = "database"
+	HealthCheckLogFile             = "log_file"
+	HealthCheckSchema              = "schema"
+	HealthStatusOk                 = "ok"
+	HealthStatusUnavailable        = "unavailable"
+	HealthNotConnectedErrorMessage = "Not connected to mysql"
+	HealthLogBucketName            = "health"
+)
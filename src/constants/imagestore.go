@@ -0,0 +1,33 @@
+package constants
+
+import "time"
+
+const (
+	// Folder images are stored in by the local storage, relative to the current working directory
+	ImageStorageDirectory     = "/images"
+	ImageStorageDirEnvVarName = "IMAGE_STORAGE_DIR"
+	ImageBaseURLEnvVarName    = "IMAGE_BASE_URL"
+	// Path images of the local storage are served at, also their base url unless IMAGE_BASE_URL is set
+	ImageURLPath            = "/images/"
+	ImageFormKeyName        = "image"
+	ImageMaxSize            = 10 << 20
+	ImageMinDimension       = 100
+	ImageMaxDimension       = 4096
+	ImageThumbnailDimension = 256
+	ImageJPEGQuality        = 90
+	// Images uploaded but not referenced by any product are kept this long, till the product referring them is saved
+	ImageUnReferencedGracePeriod = 24 * time.Hour
+	ImageStoragePut              = "put"
+	ImageStorageDelete           = "delete"
+	ImageStoreLogBucketName      = "imagestore"
+	ImageStoreInitErrorMessage   = "Error while initializing image storage"
+	ImageStoreErrorMessage       = "Error while storing image"
+	ImageDeleteErrorMessage      = "Error while deleting image from storage"
+	ImageTooLargeErrorMessage    = "Image too large, expected at most 10 MB"
+	ImageTypeErrorMessage        = "Unsupported image type, expected jpeg, png or gif"
+	ImageDimensionErrorMessage   = "Invalid image dimensions, expected 100 to 4096 pixels in width and height"
+	ImageDecodeErrorMessage      = "Invalid image, couldn't be decoded"
+	ImageMissingErrorMessage     = "Image file missing, expected a file in post form key image"
+	InvalidImageErrorMessage     = "Image url not found, upload the image first or use an external url"
+	ImageUploadSuccessMessage    = "Image uploaded"
+)
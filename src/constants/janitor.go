@@ -0,0 +1,24 @@
+package constants
+
+import "time"
+
+const (
+	// Default interval between two runs of the janitor, 0 disables the janitor
+	JanitorInterval              = time.Hour
+	JanitorIntervalEnvVarName    = "JANITOR_INTERVAL_SECONDS"
+	JanitorDryRunEnvVarName      = "JANITOR_DRY_RUN"
+	JanitorLogBucketName         = "janitor"
+	JanitorRunErrorMessage       = "Error while cleaning up unused sourcing values, ingredients, certifications and tags"
+	JanitorStartedMessage        = "Janitor started"
+	JanitorDisabledMessage       = "Janitor disabled"
+	JanitorRunSuccess            = "success"
+	JanitorRunDryRun             = "dry_run"
+	JanitorRunError              = "error"
+	JanitorSourcingValues        = "sourcing_value"
+	JanitorIngredients           = "ingredient"
+	JanitorDietaryCertifications = "dietary_certification"
+	JanitorTags                  = "tag"
+	JanitorImages                = "image"
+	CleanUpSuccessMessage        = "Unused vocabulary entries and images deleted"
+	CleanUpDryRunMessage         = "Unused vocabulary entries and images found, none deleted"
+)
@@ -0,0 +1,10 @@
+package constants
+
+const (
+	// Locale of product names, descriptions, stories and vocabulary names as stored in their own tables,
+	// translations are stored for other locales only
+	DefaultLocale            = "en"
+	AcceptLanguageHeaderName = "Accept-Language"
+	// Longest language tag stored, as per the size of locale columns
+	LocaleMaxLength = 35
+)
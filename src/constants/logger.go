@@ -1,8 +1,27 @@
 package constants
 
 const (
-	LoggerFilePath         = "/logs/zalora.log"
-	BenNJerryLogBucketName = "bennjerry"
-	MySQLLogBucketName     = "mysql"
-	AuthLogBucketName      = "auth"
+	LoggerFilePath                   = "/logs/zalora.log"
+	LoggerLevelFilePath              = "/logs/level"
+	LoggerDefaultLevel               = "info"
+	LoggerMaxSizeMB                  = 100
+	LoggerMaxBackups                 = 7
+	LoggerRotationIntervalHours      = 24
+	LoggerLevelEnvVarName            = "LOG_LEVEL"
+	LoggerStdoutEnvVarName           = "LOG_STDOUT"
+	LoggerCompressEnvVarName         = "LOG_COMPRESS"
+	LoggerMaxSizeEnvVarName          = "LOG_MAX_SIZE_MB"
+	LoggerMaxBackupsEnvVarName       = "LOG_MAX_BACKUPS"
+	LoggerRotationIntervalEnvVarName = "LOG_ROTATION_INTERVAL_HOURS"
+	LoggerLogBucketName              = "logger"
+	LoggerNotInitialisedErrorMessage = "Logger is not initialised"
+	LoggerFallbackErrorMessage       = "Log file couldn't be opened, logging to stderr"
+	BenNJerryLogBucketName           = "bennjerry"
+	MySQLLogBucketName               = "mysql"
+	AuthLogBucketName                = "auth"
+	AdminLogBucketName               = "admin"
+	RequestLogBucketName             = "request"
+	RequestIdHeaderName              = "X-Request-ID"
+	RequestIdKeyName                 = "request_id"
+	RequestIdMaxLength               = 128
 )
@@ -0,0 +1,17 @@
+package constants
+
+const (
+	MigrationsDirectoryPath          = "/migrations"
+	MigrationVersionTableName        = "schema_version"
+	MigrationUpSuffix                = ".up.sql"
+	MigrationDownSuffix              = ".down.sql"
+	MigrationLogBucketName           = "migration"
+	MigrationLoadErrorMessage        = "Error while loading migrations"
+	MigrationVersionErrorMessage     = "Error while reading schema version"
+	MigrationPendingErrorMessage     = "Database schema is behind, run migrate up. Pending migrations: "
+	MigrationAheadMessage            = "Database schema is ahead of the migrations known to the server, at version "
+	MigrationMissingScriptMessage    = "Missing up or down script for migration version "
+	MigrationDuplicateVersionMessage = "More than one migration with version "
+	MigrationUnknownVersionMessage   = "No migration with version "
+	MigrationStatementErrorMessage   = "Error while running statement of migration "
+)
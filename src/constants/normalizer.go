@@ -0,0 +1,9 @@
+package constants
+
+const (
+	// Json file of {"variant": "canonical name"} pairs, relative to the current working directory
+	NormalizerSynonymsFilePath     = "/synonyms.json"
+	NormalizerSynonymsEnvVarName   = "VOCABULARY_SYNONYMS_FILE"
+	NormalizerLogBucketName        = "normalizer"
+	NormalizerSynonymsErrorMessage = "Error while loading vocabulary synonyms"
+)
@@ -0,0 +1,15 @@
+package constants
+
+const (
+	// Units of nutrients, amounts of a nutrient are stored in the first unit of it in the nutrition package
+	NutritionUnitGram       = "g"
+	NutritionUnitMilligram  = "mg"
+	NutritionUnitKilocalory = "kcal"
+	NutritionUnitKilojoule  = "kj"
+	// Kilojoules in a kilocalorie
+	NutritionKilojoulePerKilocalory = 4.184
+	// Largest serving size, in grams
+	NutritionServingSizeMax = 1000
+	// Amounts of nutrients are rounded to this many decimals, as stored in product_nutrient.amount
+	NutritionDecimals = 3
+)
@@ -0,0 +1,8 @@
+package constants
+
+const (
+	// Format of effective dates of prices, in UTC
+	PriceTimeLayout = "2006-01-02 15:04:05"
+	// Query param of read api asking for availability and current prices in a market, e.g. ?market=SG
+	MarketQueryParamName = "market"
+)
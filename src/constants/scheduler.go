@@ -0,0 +1,23 @@
+package constants
+
+import "time"
+
+const (
+	// Format of publish_at and unpublish_at of products, in UTC
+	ScheduleTimeLayout = "2006-01-02 15:04:05"
+	// Default interval between two runs of the scheduler, 0 disables the scheduler
+	SchedulerInterval               = time.Minute
+	SchedulerIntervalEnvVarName     = "SCHEDULER_INTERVAL_SECONDS"
+	SchedulerWebhookURLEnvVarName   = "SCHEDULER_WEBHOOK_URL"
+	SchedulerWebhookTimeout         = 5 * time.Second
+	SchedulerLogBucketName          = "scheduler"
+	SchedulerStartedMessage         = "Scheduler started"
+	SchedulerDisabledMessage        = "Scheduler disabled"
+	SchedulerRunErrorMessage        = "Error while emitting events of scheduled products, retrying on next run"
+	SchedulerPublishErrorMessage    = "Error while publishing event of a scheduled product"
+	SchedulerWebhookStatusMessage   = "Webhook responded with status "
+	SchedulerRunSuccess             = "success"
+	SchedulerRunError               = "error"
+	ScheduleEventProductPublished   = "product.published"
+	ScheduleEventProductUnpublished = "product.unpublished"
+)
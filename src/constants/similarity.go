@@ -0,0 +1,21 @@
+package constants
+
+import "time"
+
+const (
+	// Weights of the overlap of ingredients and of dietary certifications in the score of similar products
+	SimilarityIngredientWeight    = 1.0
+	SimilarityCertificationWeight = 0.5
+	// Number of similar products listed by default and at most, and the query param asking for another number
+	SimilarDefaultLimit        = 10
+	SimilarMaxLimit            = 50
+	SimilarLimitQueryParamName = "limit"
+	// Default time the products compared are cached for, changes committed by this server refresh them sooner
+	// 0 refreshes them only on such changes
+	SimilarityCacheTTL            = 5 * time.Minute
+	SimilarityCacheTTLEnvVarName  = "SIMILARITY_CACHE_TTL_SECONDS"
+	SimilarityLogBucketName       = "similarity"
+	SimilarityRefreshErrorMessage = "Error while loading products to compare"
+	SimilarityRefreshSuccess      = "success"
+	SimilarityRefreshError        = "error"
+)
@@ -0,0 +1,11 @@
+package constants
+
+const (
+	// Maximum length of the SKU of a variant, as of product_variant.sku
+	VariantSkuMaxLength = 64
+	// Units of the size of a variant, as stored in product_variant.size_unit
+	VariantSizeUnitMilliliter = "ml"
+	VariantSizeUnitLiter      = "l"
+	VariantSizeUnitGram       = "g"
+	VariantSizeUnitKilogram   = "kg"
+)
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"bennjerry/model"
+	"mysqlc"
+	"normalizer"
+)
+
+const usage = `Usage: dedupe [-dry-run]
+Merges sourcing values, ingredients and dietary certifications whose names are the same once normalized
+(trimmed, NFC, case folded, synonyms replaced), moving their products to the entry kept,
+then stores names and keys of the remaining entries normalized
+`
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only print the changes, without making them")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// loading synonyms, so that names are matched as they are by the server
+	if err := normalizer.Init(); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	// connecting to mysql
+	mysqlc.Init()
+	err := dedupe(context.Background(), *dryRun)
+
+	// closing connection with mysql
+	mysqlc.DBClosing()
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func dedupe(ctx context.Context, dryRun bool) error {
+	/*
+		To dedupe every vocabulary, in order of its name
+	*/
+	names := make([]string, 0, len(model.Vocabularies))
+	for name := range model.Vocabularies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := dedupeVocabulary(ctx, name, model.Vocabularies[name], dryRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dedupeVocabulary(ctx context.Context, name string, vocabulary *model.Vocabulary, dryRun bool) error {
+	/*
+		To merge entries of a vocabulary sharing a normalized key into the one used by most products
+		(the oldest one, if tied), then to normalize name and key of the entries left
+		Every merge and rename is a transaction of its own, so running it again continues where it stopped
+	*/
+	entries, success := model.SelectVocabularyWithUsage(ctx, vocabulary)
+	if !success {
+		return fmt.Errorf("%s: couldn't read entries", name)
+	}
+	keys := make([]string, 0)
+	groups := make(map[string][]*model.PropertyUsage)
+	for _, entry := range entries {
+		key := normalizer.Key(entry.Name)
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], entry)
+	}
+	merged, renamed := 0, 0
+	pending := make([]*model.PropertyUsage, 0)
+	for _, key := range keys {
+		kept := groups[key][0]
+		for _, entry := range groups[key][1:] {
+			if entry.ProductCount > kept.ProductCount || (entry.ProductCount == kept.ProductCount && entry.Id < kept.Id) {
+				kept = entry
+			}
+		}
+		for _, entry := range groups[key] {
+			if entry == kept {
+				continue
+			}
+			fmt.Printf("%s: merging %d %q (%d products) into %d %q\n", name, entry.Id, entry.Name,
+				entry.ProductCount, kept.Id, kept.Name)
+			merged++
+			if dryRun {
+				continue
+			}
+			result := model.MergeVocabularyEntry(ctx, vocabulary, entry.Id, kept.Id)
+			if result != model.VocabularyChanged {
+				return fmt.Errorf("%s: couldn't merge %d into %d, result %d", name, entry.Id, kept.Id, result)
+			}
+		}
+		if kept.Name != normalizer.Name(kept.Name) || kept.NameKey != key {
+			pending = append(pending, kept)
+		}
+	}
+	// An entry may still carry the old key of another entry till that one is renamed too,
+	// so entries refused as existing are retried once the others are done
+	for attempt := 0; attempt < 2 && len(pending) > 0; attempt++ {
+		refused := make([]*model.PropertyUsage, 0)
+		for _, entry := range pending {
+			fmt.Printf("%s: normalizing %d %q to %q\n", name, entry.Id, entry.Name, normalizer.Name(entry.Name))
+			if dryRun {
+				renamed++
+				continue
+			}
+			switch result := model.RenameVocabularyEntry(ctx, vocabulary, entry.Id, entry.Name); result {
+			case model.VocabularyChanged:
+				renamed++
+			case model.VocabularyNameExists:
+				refused = append(refused, entry)
+			default:
+				return fmt.Errorf("%s: couldn't normalize %d, result %d", name, entry.Id, result)
+			}
+		}
+		pending = refused
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%s: couldn't normalize %d entries sharing a key with another entry", name, len(pending))
+	}
+	summary := "%s: %d of %d entries merged, %d normalized\n"
+	if dryRun {
+		summary = "%s: %d of %d entries to be merged, %d to be normalized\n"
+	}
+	fmt.Printf(summary, name, merged, len(entries), renamed)
+	return nil
+}
@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"constants"
+	"health/structs"
+	"logger"
+	"migration"
+	"mysqlc"
+)
+
+// Every dependency checked for readiness, by name
+var checks = map[string]func(ctx context.Context) error{
+	constants.HealthCheckDatabase: checkDatabase,
+	constants.HealthCheckLogFile:  checkLogFile,
+	constants.HealthCheckSchema:   checkSchema,
+}
+
+func runChecks(ctx context.Context) (map[string]*structs.CheckResult, bool) {
+	/*
+		To run all dependency checks concurrently, each with its own timeout
+		Returns result of each check and whether all of them passed
+	*/
+	var (
+		mutex   sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]*structs.CheckResult)
+		healthy = true
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(ctx context.Context) error) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, constants.HealthCheckTimeout)
+			defer cancel()
+			startTime := time.Now()
+			err := check(checkCtx)
+			result := &structs.CheckResult{
+				Status:    constants.HealthStatusOk,
+				LatencyMs: float64(time.Since(startTime).Nanoseconds()) / float64(time.Millisecond),
+			}
+			if err != nil {
+				result.Status = constants.HealthStatusUnavailable
+				result.Error = err.Error()
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			results[name] = result
+			if err != nil {
+				healthy = false
+			}
+		}(name, check)
+	}
+	wg.Wait()
+	return results, healthy
+}
+
+func checkDatabase(ctx context.Context) error {
+	/*
+		To check that mysql is reachable
+	*/
+	if mysqlc.MySqlDB == nil {
+		return errors.New(constants.HealthNotConnectedErrorMessage)
+	}
+	return mysqlc.MySqlDB.PingContext(ctx)
+}
+
+func checkLogFile(ctx context.Context) error {
+	/*
+		To check that log file can be written to
+	*/
+	return logger.ZaloraStatsLogger.Writable()
+}
+
+func checkSchema(ctx context.Context) error {
+	/*
+		To check that the database schema is at the version expected by the server, i.e. no migration is pending
+	*/
+	if mysqlc.MySqlDB == nil {
+		return errors.New(constants.HealthNotConnectedErrorMessage)
+	}
+	_, err := migration.Check(ctx, mysqlc.MySqlDB)
+	return err
+}
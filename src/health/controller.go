@@ -0,0 +1,83 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"constants"
+	"health/structs"
+	"logger"
+	"utils"
+)
+
+var startTime = time.Now()
+
+func Liveness(ginContext *gin.Context) {
+	/*
+		To report that the server process is up and able to serve requests
+		Dependencies are deliberately not checked, a failing database shouldn't get the server restarted
+		Sample Url: "http://host/healthz"
+		Request Method: GET
+		Response Data:
+		{
+			"status": "ok",
+			"uptime": "1h2m3s"
+		}
+	*/
+	response := &structs.HealthResponse{
+		Status: constants.HealthStatusOk,
+		Uptime: time.Since(startTime).Round(time.Second).String(),
+	}
+	writeResponse(ginContext, "health.Liveness", http.StatusOK, response)
+}
+
+func Readiness(ginContext *gin.Context) {
+	/*
+		To report whether the server can serve traffic, by checking each of its dependencies with a timeout
+		Responds with status code 503, if any of the dependencies is unavailable
+		Sample Url: "http://host/readyz"
+		Request Method: GET
+		Response Data:
+		{
+			"status": "ok/unavailable",
+			"checks": {
+				"database": {"status": "ok", "latency_ms": 1.2},
+				"log_file": {"status": "ok", "latency_ms": 0.1},
+				"schema": {
+					"status": "unavailable",
+					"latency_ms": 2.3,
+					"error": "Database schema is behind, run migrate up. Pending migrations: 14"
+				}
+			}
+		}
+	*/
+	results, healthy := runChecks(ginContext.Request.Context())
+	response := &structs.HealthResponse{
+		Status: constants.HealthStatusOk,
+		Checks: results,
+	}
+	statusCode := http.StatusOK
+	if !healthy {
+		response.Status = constants.HealthStatusUnavailable
+		statusCode = http.StatusServiceUnavailable
+	}
+	writeResponse(ginContext, "health.Readiness", statusCode, response)
+}
+
+func writeResponse(ginContext *gin.Context, logIdentifier string, statusCode int,
+	response *structs.HealthResponse) {
+	// Converting response structure to []byte
+	responseBytes, responseErr := json.Marshal(response)
+	// Calling common util function to send json response (utils/common.go)
+	serializer := utils.GetSerializer(constants.JsonSerializerType)
+	if responseErr != nil {
+		logger.FromContext(ginContext.Request.Context()).Error(constants.HealthLogBucketName, logIdentifier,
+			constants.JsonSerializationErrorMessage, responseErr.Error())
+		serializer.ReturnError(ginContext, http.StatusInternalServerError,
+			constants.JsonSerializationErrorMessage+" %v\"", responseErr)
+	}
+	serializer.ReturnStatus(ginContext, statusCode, responseBytes)
+}
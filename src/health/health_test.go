@@ -0,0 +1,76 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"constants"
+	"health/structs"
+)
+
+func TestLiveness(t *testing.T) {
+	/*
+		Testing Scenario: Calling /healthz while mysql isn't connected
+		Expectation: Status code 200 with status ok and the uptime, as dependencies aren't checked
+	*/
+	route := gin.New()
+	route.GET("/healthz", Liveness)
+	recorder := httptest.NewRecorder()
+	route.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	response := &structs.HealthResponse{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil || recorder.Code != http.StatusOK ||
+		response.Status != constants.HealthStatusOk || response.Uptime == "" {
+		t.Fatalf("Expected status ok with the uptime but got %d %s\n", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	/*
+		Testing Scenario: Calling /readyz with a dependency unavailable, then with all of them available
+		Expectation: Status code 503 with the error of the unavailable check, then 200 with every check ok
+	*/
+	defaultChecks := checks
+	defer func() {
+		checks = defaultChecks
+	}()
+	available := func(ctx context.Context) error {
+		return nil
+	}
+	route := gin.New()
+	route.GET("/readyz", Readiness)
+	testCases := []struct {
+		name       string
+		schema     func(ctx context.Context) error
+		statusCode int
+		status     string
+	}{
+		{"unavailable", checkSchema, http.StatusServiceUnavailable, constants.HealthStatusUnavailable},
+		{"available", available, http.StatusOK, constants.HealthStatusOk},
+	}
+	for _, testCase := range testCases {
+		// mysql isn't connected in tests, so the schema check fails unless it's replaced
+		checks = map[string]func(ctx context.Context) error{
+			constants.HealthCheckDatabase: available,
+			constants.HealthCheckLogFile:  available,
+			constants.HealthCheckSchema:   testCase.schema,
+		}
+		recorder := httptest.NewRecorder()
+		route.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		response := &structs.HealthResponse{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil ||
+			recorder.Code != testCase.statusCode || response.Status != testCase.status || len(response.Checks) != 3 {
+			t.Fatalf("%s: expected status code %d with status %s but got %d %s\n", testCase.name,
+				testCase.statusCode, testCase.status, recorder.Code, recorder.Body.String())
+		}
+		schema := response.Checks[constants.HealthCheckSchema]
+		if schema == nil || schema.Status != testCase.status ||
+			(testCase.status == constants.HealthStatusUnavailable) != (schema.Error != "") {
+			t.Fatalf("%s: expected schema check %s but got %v\n", testCase.name, testCase.status, schema)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package structs
+
+// Result of checking a single dependency
+type CheckResult struct {
+	Error     string  `json:"error,omitempty"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+}
+
+// Response structure of liveness and readiness
+type HealthResponse struct {
+	Status string                  `json:"status"`
+	Uptime string                  `json:"uptime,omitempty"`
+	Checks map[string]*CheckResult `json:"checks,omitempty"`
+}
@@ -0,0 +1,160 @@
+package imagestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"constants"
+)
+
+// Errors of Process, their messages are sent in the response as they are
+var (
+	ErrTooLarge          = errors.New(constants.ImageTooLargeErrorMessage)
+	ErrUnsupportedType   = errors.New(constants.ImageTypeErrorMessage)
+	ErrInvalidDimensions = errors.New(constants.ImageDimensionErrorMessage)
+	ErrInvalidImage      = errors.New(constants.ImageDecodeErrorMessage)
+)
+
+// Content type and file extension of every format accepted, by name of the format in the image package
+var formats = map[string]struct{ contentType, extension string }{
+	"jpeg": {"image/jpeg", ".jpg"},
+	"png":  {"image/png", ".png"},
+	"gif":  {"image/gif", ".gif"},
+}
+
+// Image is an uploaded image along with its thumbnail, ready to be stored
+type Image struct {
+	ContentType          string
+	Key                  string
+	ThumbnailContentType string
+	ThumbnailKey         string
+	Data                 []byte
+	Thumbnail            []byte
+	Height               int
+	Size                 int
+	Width                int
+}
+
+func Process(data []byte) (*Image, error) {
+	/*
+		To validate size, type and dimensions of an uploaded image and generate its thumbnail
+		Key is the sha256 of the file, so the same file always has the same (stable) url
+		Thumbnails of jpeg are jpeg, of png and gif are png (keeping transparency)
+	*/
+	if len(data) > constants.ImageMaxSize {
+		return nil, ErrTooLarge
+	}
+	// checking dimensions from the header, before decoding pixels of a possibly huge image
+	config, formatName, err := image.DecodeConfig(bytes.NewReader(data))
+	if err == image.ErrFormat {
+		return nil, ErrUnsupportedType
+	} else if err != nil {
+		return nil, ErrInvalidImage
+	}
+	format, supported := formats[formatName]
+	if !supported {
+		return nil, ErrUnsupportedType
+	}
+	if config.Width < constants.ImageMinDimension || config.Height < constants.ImageMinDimension ||
+		config.Width > constants.ImageMaxDimension || config.Height > constants.ImageMaxDimension {
+		return nil, ErrInvalidDimensions
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	thumbnail := Thumbnail(decoded, constants.ImageThumbnailDimension)
+	thumbnailBuffer := &bytes.Buffer{}
+	thumbnailFormat := formats["png"]
+	if formatName == "jpeg" {
+		thumbnailFormat = format
+		err = jpeg.Encode(thumbnailBuffer, thumbnail, &jpeg.Options{Quality: constants.ImageJPEGQuality})
+	} else {
+		err = png.Encode(thumbnailBuffer, thumbnail)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	return &Image{
+		ContentType:          format.contentType,
+		Key:                  hash + format.extension,
+		ThumbnailContentType: thumbnailFormat.contentType,
+		ThumbnailKey:         hash + "_thumb" + thumbnailFormat.extension,
+		Data:                 data,
+		Thumbnail:            thumbnailBuffer.Bytes(),
+		Height:               config.Height,
+		Size:                 len(data),
+		Width:                config.Width,
+	}, nil
+}
+
+func Thumbnail(source image.Image, maxDimension int) *image.RGBA {
+	/*
+		To scale an image down to fit in maxDimension x maxDimension, keeping its aspect ratio
+		Every pixel of the thumbnail is the average of the pixels of the source it covers
+		Images fitting already are copied as they are
+	*/
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	thumbWidth, thumbHeight := width, height
+	if width > maxDimension || height > maxDimension {
+		if width >= height {
+			thumbWidth, thumbHeight = maxDimension, height*maxDimension/width
+		} else {
+			thumbWidth, thumbHeight = width*maxDimension/height, maxDimension
+		}
+	}
+	if thumbWidth < 1 {
+		thumbWidth = 1
+	}
+	if thumbHeight < 1 {
+		thumbHeight = 1
+	}
+	// converting once to RGBA (premultiplied alpha), so that pixels can be read from its buffer
+	rgba := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(rgba, rgba.Bounds(), source, bounds.Min, draw.Src)
+	thumbnail := image.NewRGBA(image.Rect(0, 0, thumbWidth, thumbHeight))
+	for y := 0; y < thumbHeight; y++ {
+		top, bottom := y*height/thumbHeight, (y+1)*height/thumbHeight
+		for x := 0; x < thumbWidth; x++ {
+			left, right := x*width/thumbWidth, (x+1)*width/thumbWidth
+			var sum [4]int
+			for sourceY := top; sourceY < bottom; sourceY++ {
+				offset := rgba.PixOffset(left, sourceY)
+				for sourceX := left; sourceX < right; sourceX++ {
+					for channel := 0; channel < 4; channel++ {
+						sum[channel] += int(rgba.Pix[offset+channel])
+					}
+					offset += 4
+				}
+			}
+			count := (bottom - top) * (right - left)
+			offset := thumbnail.PixOffset(x, y)
+			for channel := 0; channel < 4; channel++ {
+				thumbnail.Pix[offset+channel] = uint8(sum[channel] / count)
+			}
+		}
+	}
+	return thumbnail
+}
+
+func Save(ctx context.Context, img *Image) error {
+	/*
+		To put an image and its thumbnail in the store
+	*/
+	if err := Store.Put(ctx, img.Key, img.Data, img.ContentType); err != nil {
+		return err
+	}
+	return Store.Put(ctx, img.ThumbnailKey, img.Thumbnail, img.ThumbnailContentType)
+}
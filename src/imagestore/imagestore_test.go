@@ -0,0 +1,157 @@
+package imagestore
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"constants"
+)
+
+func encodedImage(t *testing.T, format string, width int, height int) []byte {
+	/*
+		To encode an image of two halves, red on the left and blue on the right
+	*/
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+			if x >= width/2 {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	buffer := &bytes.Buffer{}
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(buffer, img, nil)
+	} else {
+		err = png.Encode(buffer, img)
+	}
+	if err != nil {
+		t.Fatalf("Couldn't encode image %s\n", err.Error())
+	}
+	return buffer.Bytes()
+}
+
+func TestProcess(t *testing.T) {
+	/*
+		Testing Scenario: Processing a png and a jpeg, and images too small, too large, of another type and corrupt
+		Expectation: Valid images have keys by their content and thumbnails in their aspect ratio,
+		the others are refused with the error of what is wrong
+	*/
+	data := encodedImage(t, "png", 1024, 512)
+	img, err := Process(data)
+	if err != nil {
+		t.Fatalf("Expected png to be valid but got %s\n", err.Error())
+	}
+	if img.ContentType != "image/png" || img.Width != 1024 || img.Height != 512 || img.Size != len(data) ||
+		filepath.Ext(img.Key) != ".png" || img.ThumbnailKey != img.Key[:64]+"_thumb.png" {
+		t.Fatalf("Unexpected png %s %s %s %dx%d\n", img.Key, img.ThumbnailKey, img.ContentType, img.Width, img.Height)
+	}
+	thumbnail, format, err := image.DecodeConfig(bytes.NewReader(img.Thumbnail))
+	if err != nil || format != "png" || thumbnail.Width != constants.ImageThumbnailDimension ||
+		thumbnail.Height != constants.ImageThumbnailDimension/2 {
+		t.Fatalf("Expected png thumbnail of 256x128 but got %s %dx%d (%v)\n", format, thumbnail.Width,
+			thumbnail.Height, err)
+	}
+	if again, _ := Process(data); again.Key != img.Key {
+		t.Fatalf("Expected the same key for the same file but got %s and %s\n", img.Key, again.Key)
+	}
+
+	img, err = Process(encodedImage(t, "jpeg", 300, 600))
+	if err != nil || img.ContentType != "image/jpeg" || filepath.Ext(img.Key) != ".jpg" ||
+		filepath.Ext(img.ThumbnailKey) != ".jpg" {
+		t.Fatalf("Expected jpeg with jpeg thumbnail but got %v (%v)\n", img, err)
+	}
+
+	invalid := map[string]struct {
+		data     []byte
+		expected error
+	}{
+		"too small": {encodedImage(t, "png", 99, 300), ErrInvalidDimensions},
+		"too wide":  {encodedImage(t, "png", constants.ImageMaxDimension+1, 100), ErrInvalidDimensions},
+		"text":      {[]byte("not an image at all"), ErrUnsupportedType},
+		"corrupt":   {data[:len(data)/2], ErrInvalidImage},
+		"too large": {make([]byte, constants.ImageMaxSize+1), ErrTooLarge},
+	}
+	for name, invalidImage := range invalid {
+		if _, err := Process(invalidImage.data); err != invalidImage.expected {
+			t.Fatalf("Expected %q for %s image but got %v\n", invalidImage.expected, name, err)
+		}
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	/*
+		Testing Scenario: Scaling down an image of a red and a blue half, and an image fitting already
+		Expectation: Pixels are averages of the pixels they cover, fitting images keep their dimensions
+	*/
+	source, _, err := image.Decode(bytes.NewReader(encodedImage(t, "png", 400, 200)))
+	if err != nil {
+		t.Fatalf("Couldn't decode image %s\n", err.Error())
+	}
+	thumbnail := Thumbnail(source, 4)
+	if thumbnail.Bounds().Dx() != 4 || thumbnail.Bounds().Dy() != 2 {
+		t.Fatalf("Expected thumbnail of 4x2 but got %v\n", thumbnail.Bounds())
+	}
+	if left, right := thumbnail.RGBAAt(0, 0), thumbnail.RGBAAt(3, 1); left != (color.RGBA{R: 255, A: 255}) ||
+		right != (color.RGBA{B: 255, A: 255}) {
+		t.Fatalf("Expected red on the left and blue on the right but got %v and %v\n", left, right)
+	}
+	if thumbnail = Thumbnail(source, 1000); thumbnail.Bounds().Dx() != 400 || thumbnail.Bounds().Dy() != 200 {
+		t.Fatalf("Expected image fitting already to keep 400x200 but got %v\n", thumbnail.Bounds())
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	/*
+		Testing Scenario: Putting, resolving the url of and deleting a file of the local storage,
+		and resolving urls that aren't of the storage
+		Expectation: The file is written under its key and its url resolves to it, deleting it twice isn't an error,
+		other urls are refused
+	*/
+	directory, err := ioutil.TempDir("", "images")
+	if err != nil {
+		t.Fatalf("Couldn't create directory %s\n", err.Error())
+	}
+	defer os.RemoveAll(directory)
+	storage, err := NewLocalStorage(filepath.Join(directory, "nested"), "https://cdn.example.com/images")
+	if err != nil {
+		t.Fatalf("Couldn't create storage %s\n", err.Error())
+	}
+	ctx := context.Background()
+	if err := storage.Put(ctx, "abc.png", []byte("content"), "image/png"); err != nil {
+		t.Fatalf("Couldn't put file %s\n", err.Error())
+	}
+	if content, err := ioutil.ReadFile(filepath.Join(directory, "nested", "abc.png")); err != nil ||
+		string(content) != "content" {
+		t.Fatalf("Expected file with content but got %q (%v)\n", content, err)
+	}
+	url := storage.URL("abc.png")
+	if key, isStored := storage.KeyFromURL(url); url != "https://cdn.example.com/images/abc.png" || !isStored ||
+		key != "abc.png" {
+		t.Fatalf("Expected url of abc.png to resolve to it but got %s: %q (%v)\n", url, key, isStored)
+	}
+	for _, url := range []string{"https://example.com/abc.png", "https://cdn.example.com/images/",
+		"https://cdn.example.com/images/../secret", "https://cdn.example.com/images/.upload-1"} {
+		if key, isStored := storage.KeyFromURL(url); isStored {
+			t.Fatalf("Expected %s not to be of the storage but got key %q\n", url, key)
+		}
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		if err := storage.Delete(ctx, "abc.png"); err != nil {
+			t.Fatalf("Expected file to be deleted but got %s\n", err.Error())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(directory, "nested", "abc.png")); !os.IsNotExist(err) {
+		t.Fatalf("Expected file to be deleted\n")
+	}
+}
@@ -0,0 +1,126 @@
+package imagestore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"constants"
+)
+
+// Storage keeps image files by key and tells the url they are served at
+// The local filesystem is the default, an object store (e.g. S3, GCS) implements the same interface
+type Storage interface {
+	// Put stores data under key, replacing a file stored under it already
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Delete removes the file stored under key, a missing file isn't an error
+	Delete(ctx context.Context, key string) error
+	// URL tells the stable url of key
+	URL(key string) string
+	// KeyFromURL tells the key of an url of this storage, false if the url is not of this storage
+	KeyFromURL(url string) (string, bool)
+}
+
+// Store is the storage used by the server, set by Init
+var Store Storage
+
+func Init() error {
+	/*
+		To use the local storage, in the folder set in IMAGE_STORAGE_DIR, else images of current working directory,
+		with urls starting with IMAGE_BASE_URL, else /images/ (served by the server itself)
+	*/
+	directory := os.Getenv(constants.ImageStorageDirEnvVarName)
+	if directory == "" {
+		pwd, _ := os.Getwd()
+		directory = pwd + constants.ImageStorageDirectory
+	}
+	baseURL := os.Getenv(constants.ImageBaseURLEnvVarName)
+	if baseURL == "" {
+		baseURL = constants.ImageURLPath
+	}
+	storage, err := NewLocalStorage(directory, baseURL)
+	if err != nil {
+		return err
+	}
+	Store = storage
+	return nil
+}
+
+func KeyFromURL(url string) (string, bool) {
+	/*
+		To tell the key of an url of the store, false if it's an external url or no store is set (e.g. uploader)
+	*/
+	if Store == nil || url == "" {
+		return "", false
+	}
+	return Store.KeyFromURL(url)
+}
+
+// LocalStorage keeps images as files of a folder
+type LocalStorage struct {
+	BaseURL   string
+	Directory string
+}
+
+func NewLocalStorage(directory string, baseURL string) (*LocalStorage, error) {
+	/*
+		To create the folder if it's missing and return a local storage of it
+	*/
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return &LocalStorage{BaseURL: baseURL, Directory: directory}, nil
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	/*
+		To write data to a temporary file and rename it to key, so that a file is never served half written
+	*/
+	file, err := ioutil.TempFile(s.Directory, ".upload-")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filepath.Join(s.Directory, filepath.Base(key)))
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	err := os.Remove(filepath.Join(s.Directory, filepath.Base(key)))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return s.BaseURL + key
+}
+
+func (s *LocalStorage) KeyFromURL(url string) (string, bool) {
+	if !strings.HasPrefix(url, s.BaseURL) {
+		return "", false
+	}
+	key := strings.TrimPrefix(url, s.BaseURL)
+	// keys are file names, anything else can't have been stored
+	if key == "" || key != filepath.Base(key) || strings.HasPrefix(key, ".") {
+		return "", false
+	}
+	return key, true
+}
@@ -0,0 +1,114 @@
+package janitor
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"bennjerry/model"
+	"constants"
+	"logger"
+	"metrics"
+)
+
+var (
+	// To stop the running janitor and to wait for its current run to finish
+	cancelJanitor context.CancelFunc
+	janitorWg     sync.WaitGroup
+)
+
+func Start(interval time.Duration, dryRun bool) {
+	/*
+		To run the clean up of unused sourcing values, ingredients, dietary certifications, tags and unreferenced
+		images every interval, in background, till Stop is called
+		interval <= 0 disables the janitor
+	*/
+	logIdentifier := "janitor.Start"
+	if interval <= 0 {
+		logger.ZaloraStatsLogger.Info(constants.JanitorLogBucketName, logIdentifier, constants.JanitorDisabledMessage)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelJanitor = cancel
+	janitorWg.Add(1)
+	go func() {
+		defer janitorWg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				RunOnce(ctx, dryRun)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	logger.ZaloraStatsLogger.Info(constants.JanitorLogBucketName, logIdentifier,
+		constants.JanitorStartedMessage+", every "+interval.String()+", dry run: "+strconv.FormatBool(dryRun))
+}
+
+func Stop() {
+	/*
+		To stop the janitor, a run in progress is cancelled (and its transaction rolled back)
+	*/
+	if cancelJanitor != nil {
+		cancelJanitor()
+		janitorWg.Wait()
+	}
+}
+
+func RunOnce(ctx context.Context, dryRun bool) (*model.UnUsedProperties, bool) {
+	/*
+		To clean up unused sourcing values, ingredients, dietary certifications, tags and unreferenced images once
+		With dryRun, unused entries are only reported and none are deleted
+	*/
+	logIdentifier := "janitor.RunOnce"
+	unUsed, success := model.CleanUpUnUsed(ctx, dryRun)
+	if success {
+		// Images are files of the image storage as well, so they are deleted one by one, outside the transaction
+		unUsed.Images, success = model.CleanUpStaleImages(ctx, dryRun)
+	}
+	if !success {
+		metrics.JanitorRuns.WithLabels(constants.JanitorRunError).Inc()
+		logger.FromContext(ctx).Error(constants.JanitorLogBucketName, logIdentifier,
+			constants.JanitorRunErrorMessage, "dry run: "+strconv.FormatBool(dryRun))
+		return nil, false
+	}
+	found := []struct {
+		vocabulary  string
+		names       []string
+		rowsDeleted int64
+	}{
+		{constants.JanitorSourcingValues, unUsed.SourcingValues, unUsed.RowsDeleted["sourcingvalue"]},
+		{constants.JanitorIngredients, unUsed.Ingredients, unUsed.RowsDeleted["ingredient"]},
+		{constants.JanitorDietaryCertifications, unUsed.DietaryCertifications,
+			unUsed.RowsDeleted["dietarycertification"]},
+		{constants.JanitorTags, unUsed.Tags, unUsed.RowsDeleted["tag"]},
+		// Images are listed only once deleted
+		{constants.JanitorImages, unUsed.Images, int64(len(unUsed.Images))},
+	}
+	if dryRun {
+		metrics.JanitorRuns.WithLabels(constants.JanitorRunDryRun).Inc()
+	} else {
+		metrics.JanitorRuns.WithLabels(constants.JanitorRunSuccess).Inc()
+	}
+	for _, entries := range found {
+		if len(entries.names) == 0 && entries.rowsDeleted == 0 {
+			continue
+		}
+		// Entries found may be attached to a product before they are deleted, so rows deleted are reported
+		message := "Deleted " + strconv.FormatInt(entries.rowsDeleted, 10) + " of " + strconv.Itoa(len(entries.names)) +
+			" unused " + entries.vocabulary + ": " + strings.Join(entries.names, ", ")
+		if dryRun {
+			message = "Would delete " + strconv.Itoa(len(entries.names)) + " unused " + entries.vocabulary + ": " +
+				strings.Join(entries.names, ", ")
+		} else {
+			metrics.JanitorRowsDeleted.WithLabels(entries.vocabulary).Add(float64(entries.rowsDeleted))
+		}
+		logger.FromContext(ctx).Info(constants.JanitorLogBucketName, logIdentifier, message)
+	}
+	return unUsed, true
+}
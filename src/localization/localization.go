@@ -0,0 +1,166 @@
+package localization
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"constants"
+)
+
+// Fallbacks lists, for a locale, the locales to try before its parent (e.g. ms-MY falls back to ms, then to id)
+// Locales not listed fall back to their parent only, and all of them to the default locale at last
+var Fallbacks = map[string][]string{
+	"id":    {"ms"},
+	"ms":    {"id"},
+	"zh-HK": {"zh-Hant"},
+	"zh-MY": {"zh-Hans"},
+	"zh-SG": {"zh-Hans"},
+	"zh-TW": {"zh-Hant"},
+}
+
+func Canonical(tag string) (string, bool) {
+	/*
+		To validate a language tag (e.g. "ms_my") and return it in its canonical case (e.g. "ms-MY")
+		Language is lower case, script title case and region upper case, as per BCP 47
+		Return: canonical tag, false if it isn't a language tag
+	*/
+	tag = strings.TrimSpace(tag)
+	if tag == "" || len(tag) > constants.LocaleMaxLength {
+		return "", false
+	}
+	subtags := strings.Split(strings.Replace(tag, "_", "-", -1), "-")
+	for index, subtag := range subtags {
+		if index == 0 {
+			if len(subtag) < 2 || len(subtag) > 3 || !isAlpha(subtag) {
+				return "", false
+			}
+			subtags[index] = strings.ToLower(subtag)
+			continue
+		}
+		if subtag == "" || len(subtag) > 8 || !isAlphaNumeric(subtag) {
+			return "", false
+		}
+		switch {
+		case len(subtag) == 4 && isAlpha(subtag):
+			subtags[index] = strings.ToUpper(subtag[:1]) + strings.ToLower(subtag[1:])
+		case len(subtag) == 2 && isAlpha(subtag), len(subtag) == 3 && !isAlpha(subtag):
+			subtags[index] = strings.ToUpper(subtag)
+		default:
+			subtags[index] = strings.ToLower(subtag)
+		}
+	}
+	return strings.Join(subtags, "-"), true
+}
+
+func ParseAcceptLanguage(header string) []string {
+	/*
+		To read the locales of an Accept-Language header (e.g. "ms-MY,ms;q=0.9,en;q=0.5")
+		Return: canonical locales in order of preference, skipping the wildcard, invalid tags and tags with q=0
+	*/
+	type weightedLocale struct {
+		locale  string
+		quality float64
+	}
+	weightedLocales := make([]*weightedLocale, 0)
+	for _, part := range strings.Split(header, ",") {
+		parameters := strings.Split(part, ";")
+		locale, valid := Canonical(parameters[0])
+		if !valid {
+			continue
+		}
+		quality := 1.0
+		for _, parameter := range parameters[1:] {
+			parameter = strings.TrimSpace(parameter)
+			if strings.HasPrefix(parameter, "q=") {
+				value, err := strconv.ParseFloat(strings.TrimPrefix(parameter, "q="), 64)
+				if err != nil || value < 0 || value > 1 {
+					value = 0
+				}
+				quality = value
+			}
+		}
+		if quality > 0 {
+			weightedLocales = append(weightedLocales, &weightedLocale{locale: locale, quality: quality})
+		}
+	}
+	// Keeping the order of the header among locales of the same quality
+	sort.SliceStable(weightedLocales, func(i, j int) bool {
+		return weightedLocales[i].quality > weightedLocales[j].quality
+	})
+	locales := make([]string, 0, len(weightedLocales))
+	for _, weighted := range weightedLocales {
+		locales = append(locales, weighted.locale)
+	}
+	return locales
+}
+
+func FallbackChain(locales []string) []string {
+	/*
+		To take locales in order of preference and return every locale to look for a translation in, in order
+		Each locale is followed by its fallbacks and then by its parent, e.g. "zh-SG" by "zh-Hans", "zh"
+		The chain ends at the default locale, whose content isn't translated but stored in the product itself
+	*/
+	chain := make([]string, 0)
+	visited := make(map[string]bool)
+	var expand func(locale string)
+	expand = func(locale string) {
+		if locale == "" || visited[locale] {
+			return
+		}
+		visited[locale] = true
+		chain = append(chain, locale)
+		for _, fallback := range Fallbacks[locale] {
+			expand(fallback)
+		}
+		if index := strings.LastIndex(locale, "-"); index > 0 {
+			expand(locale[:index])
+		}
+	}
+	for _, locale := range locales {
+		expand(locale)
+	}
+	for index, locale := range chain {
+		if locale == constants.DefaultLocale {
+			return chain[:index]
+		}
+	}
+	return chain
+}
+
+func Pick(chain []string, translations map[string]string) (string, string) {
+	/*
+		To take a fallback chain and translations of a text by locale, and pick the first translation in the chain
+		Return: translation and its locale, both empty if there's none and the text isn't to be translated
+	*/
+	for _, locale := range chain {
+		if translation := translations[locale]; translation != "" {
+			return translation, locale
+		}
+	}
+	return "", ""
+}
+
+func isAlpha(value string) bool {
+	/*
+		To check that a subtag has ascii letters only
+	*/
+	for _, r := range value {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphaNumeric(value string) bool {
+	/*
+		To check that a subtag has ascii letters and digits only
+	*/
+	for _, r := range value {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
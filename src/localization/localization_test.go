@@ -0,0 +1,92 @@
+package localization
+
+import (
+	"testing"
+
+	"utils"
+)
+
+func TestCanonical(t *testing.T) {
+	/*
+		Testing Scenario: Validating language tags in different cases and separators, and invalid ones
+		Expectation: Valid tags in canonical case (language lower, script title, region upper), invalid ones refused
+	*/
+	tags := map[string]string{
+		"ms":             "ms",
+		"MS_my":          "ms-MY",
+		"zh-hant-tw":     "zh-Hant-TW",
+		"es-419":         "es-419",
+		" th-TH ":        "th-TH",
+		"fil-PH":         "fil-PH",
+		"en-GB-oxendict": "en-GB-oxendict",
+	}
+	for tag, expected := range tags {
+		if canonical, valid := Canonical(tag); !valid || canonical != expected {
+			t.Fatalf("Expected %q to be canonical %q but got %q (valid %v)\n", tag, expected, canonical, valid)
+		}
+	}
+	for _, tag := range []string{"", "*", "m", "english", "ms-", "ms-MY!", "1d", "ms-toolongsubtag"} {
+		if canonical, valid := Canonical(tag); valid {
+			t.Fatalf("Expected %q to be invalid but got %q\n", tag, canonical)
+		}
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	/*
+		Testing Scenario: Parsing Accept-Language headers with qualities out of order, the wildcard, q=0,
+		invalid tags and no header
+		Expectation: Valid locales by quality, in order of the header among equal qualities
+	*/
+	headers := map[string][]string{
+		"ms-MY,ms;q=0.9,en;q=0.5":          {"ms-MY", "ms", "en"},
+		"en;q=0.2, th, id-ID;q=0.8, vi":    {"th", "vi", "id-ID", "en"},
+		"*;q=0.1, zh-SG, fr;q=0, !!;q=0.5": {"zh-SG"},
+		"":                                 {},
+	}
+	for header, expected := range headers {
+		if locales := ParseAcceptLanguage(header); !utils.ListOfStringCompare(locales, expected) {
+			t.Fatalf("Expected locales %v for %q but got %v\n", expected, header, locales)
+		}
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	/*
+		Testing Scenario: Building fallback chains of locales with parents, configured fallbacks and
+		the default locale
+		Expectation: Each locale followed by its fallbacks and parents, without repetitions, ending before the
+		default locale
+	*/
+	chains := []struct {
+		locales  []string
+		expected []string
+	}{
+		{[]string{"ms-MY"}, []string{"ms-MY", "ms", "id"}},
+		{[]string{"zh-SG", "th"}, []string{"zh-SG", "zh-Hans", "zh", "th"}},
+		{[]string{"id", "ms"}, []string{"id", "ms"}},
+		{[]string{"th", "en", "vi"}, []string{"th"}},
+		{[]string{"en-SG", "ms"}, []string{"en-SG"}},
+		{[]string{}, []string{}},
+	}
+	for _, chain := range chains {
+		if result := FallbackChain(chain.locales); !utils.ListOfStringCompare(result, chain.expected) {
+			t.Fatalf("Expected chain %v for %v but got %v\n", chain.expected, chain.locales, result)
+		}
+	}
+}
+
+func TestPick(t *testing.T) {
+	/*
+		Testing Scenario: Picking a translation along a chain, with an empty translation and with none in the chain
+		Expectation: First non empty translation in order of the chain, nothing if there's none
+	*/
+	translations := map[string]string{"ms": "", "id": "Susu", "th": "นม"}
+	if translation, locale := Pick([]string{"ms-MY", "ms", "id", "th"}, translations); translation != "Susu" ||
+		locale != "id" {
+		t.Fatalf("Expected translation Susu in id but got %q in %q\n", translation, locale)
+	}
+	if translation, locale := Pick([]string{"vi"}, translations); translation != "" || locale != "" {
+		t.Fatalf("Expected no translation but got %q in %q\n", translation, locale)
+	}
+}
@@ -1,15 +1,31 @@
 package logger
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 
 	"constants"
+	"utils"
 )
 
 type Logger struct {
 	*logrus.Logger
+	file  *RotatingFile
+	level string
+	mutex sync.RWMutex
 }
 
 var (
@@ -17,24 +33,193 @@ var (
 )
 
 func Init() {
+	/*
+		To create the application logger writing to logs/zalora.log (relative to working directory)
+		Level, rotation and stdout output are configured through environment variables (constants/logger.go)
+	*/
 	pwd, _ := os.Getwd()
 	ZaloraStatsLogger = NewLogger(pwd + constants.LoggerFilePath)
+	if levelName := os.Getenv(constants.LoggerLevelEnvVarName); levelName != "" {
+		if err := ZaloraStatsLogger.SetLevelByName(levelName); err != nil {
+			ZaloraStatsLogger.Warn(constants.LoggerLogBucketName, "logger.Init",
+				"Invalid log level "+levelName+", using "+ZaloraStatsLogger.LevelName())
+		}
+	}
+	ZaloraStatsLogger.loadLevelFile(pwd + constants.LoggerLevelFilePath)
 }
 
 func NewLogger(filePath string) *Logger {
-	logger := Logger{Logger: logrus.New()}
+	/*
+		To create a logger writing json lines to a rotating file at filePath and, if enabled, to stdout
+		If the file can't be opened, logger falls back to stderr instead of stopping the application
+	*/
+	logger := &Logger{Logger: logrus.New()}
 	logger.SetFormatter(&logrus.JSONFormatter{})
-	f, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	logger.SetLevelByName(constants.LoggerDefaultLevel)
+
+	writers := make([]io.Writer, 0)
+	file, err := NewRotatingFile(filePath, int64(utils.GetEnvInt(constants.LoggerMaxSizeEnvVarName,
+		constants.LoggerMaxSizeMB))*1024*1024, time.Duration(utils.GetEnvInt(constants.LoggerRotationIntervalEnvVarName,
+		constants.LoggerRotationIntervalHours))*time.Hour, utils.GetEnvInt(constants.LoggerMaxBackupsEnvVarName,
+		constants.LoggerMaxBackups), os.Getenv(constants.LoggerCompressEnvVarName) != "0")
 	if err != nil {
-		logrus.Fatalln("cant open zalora log, ", err)
+		fmt.Fprintln(os.Stderr, "cant open zalora log, logging to stderr instead,", err)
+		writers = append(writers, os.Stderr)
+	} else {
+		logger.file = file
+		writers = append(writers, file)
+	}
+	// Containers collect logs from stdout, therefore it's enabled by default in docker (release) mode
+	if os.Getenv(constants.LoggerStdoutEnvVarName) == "1" ||
+		(os.Getenv(constants.LoggerStdoutEnvVarName) == "" &&
+			os.Getenv(constants.DockerMySQLModeEnvVarName) == constants.DockerMySQLModeEnvVarValue) {
+		if file != nil {
+			writers = append(writers, os.Stdout)
+		}
+	}
+	logger.Logger.Out = io.MultiWriter(writers...)
+	return logger
+}
+
+func (l *Logger) SetLevelByName(levelName string) error {
+	/*
+		To change level of the logger at runtime, e.g. "debug", "info", "warning", "error"
+	*/
+	level, err := logrus.ParseLevel(strings.ToLower(strings.TrimSpace(levelName)))
+	if err != nil {
+		return err
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.Logger.SetLevel(level)
+	l.level = level.String()
+	return nil
+}
+
+func (l *Logger) LevelName() string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.level
+}
+
+func (l *Logger) Reopen() error {
+	/*
+		To reopen the log file, e.g. after it has been moved by an external logrotate
+	*/
+	if l == nil || l.file == nil {
+		return nil
+	}
+	return l.file.Reopen()
+}
+
+func (l *Logger) Writable() error {
+	/*
+		To check that logs are being written to the log file and the file is writable
+	*/
+	if l == nil || l.Logger == nil {
+		return errors.New(constants.LoggerNotInitialisedErrorMessage)
+	}
+	if l.file == nil {
+		return errors.New(constants.LoggerFallbackErrorMessage)
 	}
-	logger.Logger.Out = f
-	return &logger
+	return l.file.Writable()
+}
+
+func (l *Logger) Close() error {
+	/*
+		To flush and close the log file
+	*/
+	if l == nil || l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+func WatchSignals() {
+	/*
+		To reopen the log file and reload log level from logs/level file when the process receives SIGHUP
+	*/
+	pwd, _ := os.Getwd()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			if err := ZaloraStatsLogger.Reopen(); err != nil {
+				fmt.Fprintln(os.Stderr, "cant reopen zalora log,", err)
+			}
+			ZaloraStatsLogger.loadLevelFile(pwd + constants.LoggerLevelFilePath)
+		}
+	}()
+}
+
+func (l *Logger) loadLevelFile(levelFilePath string) {
+	/*
+		To set level of the logger from the contents of levelFilePath, if the file exists
+	*/
+	levelBytes, err := ioutil.ReadFile(levelFilePath)
+	if err != nil {
+		return
+	}
+	if err := l.SetLevelByName(string(levelBytes)); err != nil {
+		l.Error(constants.LoggerLogBucketName, "logger.loadLevelFile", "Invalid log level in "+levelFilePath,
+			err.Error())
+		return
+	}
+	l.Info(constants.LoggerLogBucketName, "logger.loadLevelFile", "Log level set to "+l.LevelName())
 }
 
 func (l *Logger) Error(bucket string, identifier string, message string, errorMessage string) {
-	l.Logger.WithFields(logrus.Fields{
+	if entry := l.entry(bucket, identifier); entry != nil {
+		entry.Error(message + ": " + errorMessage)
+	}
+}
+
+func (l *Logger) Warn(bucket string, identifier string, message string) {
+	if entry := l.entry(bucket, identifier); entry != nil {
+		entry.Warn(message)
+	}
+}
+
+func (l *Logger) Info(bucket string, identifier string, message string) {
+	if entry := l.entry(bucket, identifier); entry != nil {
+		entry.Info(message)
+	}
+}
+
+func (l *Logger) Debug(bucket string, identifier string, message string) {
+	if entry := l.entry(bucket, identifier); entry != nil {
+		entry.Debug(message)
+	}
+}
+
+func (l *Logger) entry(bucket string, identifier string) *logrus.Entry {
+	/*
+		To create a log entry with the fields common to every log line
+		Returns nil, if logger is not initialised (e.g. scripts which don't call Init)
+	*/
+	if l == nil || l.Logger == nil {
+		return nil
+	}
+	return l.Logger.WithFields(logrus.Fields{
 		"bucket":     bucket,
 		"identifier": identifier,
-	}).Error(message + ": " + errorMessage)
+		"caller":     caller(),
+	})
+}
+
+func caller() string {
+	/*
+		To find file name and line number of the code that called the logging function
+		Skipping caller() itself, the logging method and, for request loggers, the method it delegates from
+	*/
+	for skip := 2; skip < 5; skip++ {
+		_, file, line, ok := runtime.Caller(skip)
+		if !ok {
+			break
+		}
+		if filepath.Base(filepath.Dir(file)) != "logger" {
+			return filepath.Base(filepath.Dir(file)) + "/" + filepath.Base(file) + ":" + strconv.Itoa(line)
+		}
+	}
+	return ""
 }
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"constants"
+)
+
+func RequestTracer(ginContext *gin.Context) {
+	/*
+		Middleware to attach a request logger to every request
+		Request id is taken from X-Request-ID header if the client sent a valid one, else a new one is generated
+		Request id is sent back in the X-Request-ID response header
+		The request logger is available to handlers and model functions through the request's context
+	*/
+	requestId := ginContext.Request.Header.Get(constants.RequestIdHeaderName)
+	if !isValidRequestId(requestId) {
+		requestId = newRequestId()
+	}
+	ginContext.Header(constants.RequestIdHeaderName, requestId)
+
+	requestLogger := ZaloraStatsLogger.ForRequest(requestId, ginContext.Request.Method, RouteOf(ginContext))
+	ginContext.Set(constants.RequestIdKeyName, requestId)
+	ginContext.Request = ginContext.Request.WithContext(NewContext(ginContext.Request.Context(), requestLogger))
+
+	ginContext.Next()
+
+	requestLogger.Info(constants.RequestLogBucketName, "logger.RequestTracer",
+		"Request completed with status "+strconv.Itoa(ginContext.Writer.Status()))
+}
+
+func RouteOf(ginContext *gin.Context) string {
+	/*
+		To find the registered route of a request, e.g. /bennjerry/:product_id/ instead of /bennjerry/2190/
+		Requests not matching any route are logged with their path
+	*/
+	if route := ginContext.FullPath(); route != "" {
+		return route
+	}
+	return ginContext.Request.URL.Path
+}
+
+func isValidRequestId(requestId string) bool {
+	/*
+		To check that a client provided request id is safe to be logged and echoed back
+	*/
+	if requestId == "" || len(requestId) > constants.RequestIdMaxLength {
+		return false
+	}
+	for _, char := range requestId {
+		if char < '!' || char > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestId() string {
+	/*
+		To generate a random 16 byte, hex encoded request id
+	*/
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(randomBytes)
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+type requestLoggerKey struct{}
+
+// Logger carrying information of the http request being served, so that every log line can be traced back to it
+type RequestLogger struct {
+	*Logger
+	Method    string
+	RequestId string
+	Route     string
+	startTime time.Time
+}
+
+func (l *Logger) ForRequest(requestId string, method string, route string) *RequestLogger {
+	/*
+		To create a logger for a single http request, latency is measured from the time of creation
+	*/
+	return &RequestLogger{
+		Logger:    l,
+		Method:    method,
+		RequestId: requestId,
+		Route:     route,
+		startTime: time.Now(),
+	}
+}
+
+func NewContext(ctx context.Context, requestLogger *RequestLogger) context.Context {
+	/*
+		To return a copy of ctx which carries the request logger
+	*/
+	return context.WithValue(ctx, requestLoggerKey{}, requestLogger)
+}
+
+func FromContext(ctx context.Context) *RequestLogger {
+	/*
+		To fetch the request logger carried by ctx
+		Falls back to the application logger without request information, e.g. for scripts and background jobs
+	*/
+	if ctx != nil {
+		if requestLogger, ok := ctx.Value(requestLoggerKey{}).(*RequestLogger); ok && requestLogger != nil {
+			return requestLogger
+		}
+	}
+	return &RequestLogger{Logger: ZaloraStatsLogger}
+}
+
+func (r *RequestLogger) Latency() time.Duration {
+	/*
+		To return time elapsed since the request logger was created
+	*/
+	if r.startTime.IsZero() {
+		return 0
+	}
+	return time.Since(r.startTime)
+}
+
+func (r *RequestLogger) Error(bucket string, identifier string, message string, errorMessage string) {
+	if entry := r.entry(bucket, identifier); entry != nil {
+		entry.Error(message + ": " + errorMessage)
+	}
+}
+
+func (r *RequestLogger) Warn(bucket string, identifier string, message string) {
+	if entry := r.entry(bucket, identifier); entry != nil {
+		entry.Warn(message)
+	}
+}
+
+func (r *RequestLogger) Info(bucket string, identifier string, message string) {
+	if entry := r.entry(bucket, identifier); entry != nil {
+		entry.Info(message)
+	}
+}
+
+func (r *RequestLogger) Debug(bucket string, identifier string, message string) {
+	if entry := r.entry(bucket, identifier); entry != nil {
+		entry.Debug(message)
+	}
+}
+
+func (r *RequestLogger) entry(bucket string, identifier string) *logrus.Entry {
+	/*
+		To create a log entry with the common fields and information of the request
+	*/
+	entry := r.Logger.entry(bucket, identifier)
+	if entry == nil || r.RequestId == "" {
+		return entry
+	}
+	return entry.WithFields(logrus.Fields{
+		"request_id": r.RequestId,
+		"method":     r.Method,
+		"route":      r.Route,
+		"latency_ms": float64(r.Latency().Nanoseconds()) / float64(time.Millisecond),
+	})
+}
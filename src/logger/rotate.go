@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log file which is rotated once it grows beyond maxSize bytes or is older than interval
+// Rotated files are renamed with a timestamp suffix, optionally gzipped and only the latest maxBackups are kept
+type RotatingFile struct {
+	compress   bool
+	file       *os.File
+	interval   time.Duration
+	maxBackups int
+	maxSize    int64
+	mutex      sync.Mutex
+	openedAt   time.Time
+	path       string
+	size       int64
+}
+
+func NewRotatingFile(path string, maxSize int64, interval time.Duration, maxBackups int,
+	compress bool) (*RotatingFile, error) {
+	/*
+		To open (create, if missing along with its directory) a log file which rotates itself
+		maxSize <= 0 disables size based rotation, interval <= 0 disables time based rotation
+		maxBackups <= 0 keeps all rotated files
+	*/
+	rotatingFile := &RotatingFile{
+		compress:   compress,
+		interval:   interval,
+		maxBackups: maxBackups,
+		maxSize:    maxSize,
+		path:       path,
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := rotatingFile.open(); err != nil {
+		return nil, err
+	}
+	return rotatingFile, nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) Reopen() error {
+	/*
+		To close and open the log file again, e.g. after it has been moved by an external logrotate
+	*/
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.closeFile()
+	return r.open()
+}
+
+func (r *RotatingFile) Writable() error {
+	/*
+		To check that the log file can still be opened for writing, e.g. disk is not read-only
+	*/
+	file, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func (r *RotatingFile) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.closeFile()
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file = file
+	r.size = info.Size()
+	// An existing file is treated as opened at its last modification, so that time based rotation
+	// doesn't restart on every restart of the server
+	r.openedAt = time.Now()
+	if info.Size() > 0 {
+		r.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+func (r *RotatingFile) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+	r.file.Sync()
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *RotatingFile) shouldRotate(writeSize int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.maxSize > 0 && r.size+writeSize > r.maxSize {
+		return true
+	}
+	return r.interval > 0 && time.Since(r.openedAt) >= r.interval
+}
+
+func (r *RotatingFile) rotate() error {
+	/*
+		To move current log file aside with a timestamp suffix and start a new one
+		Compression and removal of old files happens in background to not block the writer
+	*/
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+	rotatedPath := r.path + "." + time.Now().Format("20060102T150405.000000000")
+	if err := os.Rename(r.path, rotatedPath); err != nil {
+		return err
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+	go r.cleanUp(rotatedPath)
+	return nil
+}
+
+func (r *RotatingFile) cleanUp(rotatedPath string) {
+	/*
+		To gzip the rotated file (if enabled) and delete rotated files beyond the retention count
+	*/
+	if r.compress {
+		if err := gzipFile(rotatedPath); err == nil {
+			os.Remove(rotatedPath)
+		}
+	}
+	if r.maxBackups <= 0 {
+		return
+	}
+	backups, err := filepath.Glob(r.path + ".*")
+	if err != nil {
+		return
+	}
+	// Timestamp suffix sorts in chronological order, a file and its gzip are counted once
+	seen := make(map[string]bool)
+	unique := make([]string, 0)
+	for _, backup := range backups {
+		name := strings.TrimSuffix(backup, ".gz")
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	sort.Strings(unique)
+	for index := 0; index < len(unique)-r.maxBackups; index++ {
+		os.Remove(unique[index])
+		os.Remove(unique[index] + ".gz")
+	}
+}
+
+func gzipFile(path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	destination, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	gzipWriter := gzip.NewWriter(destination)
+	if _, err = io.Copy(gzipWriter, source); err != nil {
+		gzipWriter.Close()
+		destination.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err = gzipWriter.Close(); err != nil {
+		destination.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	return destination.Close()
+}
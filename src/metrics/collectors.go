@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"mysqlc"
+)
+
+var (
+	// Buckets (in seconds) for latency of http requests
+	latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+	HTTPRequests = NewCounterVec("zalora_http_requests_total",
+		"Number of http requests served, by method, route and status code", "method", "route", "status")
+	HTTPRequestDuration = NewHistogramVec("zalora_http_request_duration_seconds",
+		"Latency of http requests, by method, route and status code", latencyBuckets, "method", "route", "status")
+	MySQLTransactions = NewCounterVec("zalora_mysql_transactions_total",
+		"Number of mysql transactions, by operation (insert/update/drop) and result (commit/rollback)",
+		"operation", "result")
+	MySQLQueryErrors = NewCounterVec("zalora_mysql_query_errors_total",
+		"Number of mysql queries that returned an error, by model function", "function")
+	JanitorRuns = NewCounterVec("zalora_janitor_runs_total",
+		"Number of runs of the janitor cleaning up unused entries, by result (success/dry_run/error)", "result")
+	JanitorRowsDeleted = NewCounterVec("zalora_janitor_rows_deleted_total",
+		"Number of unused entries deleted by the janitor, by vocabulary", "vocabulary")
+	SchedulerRuns = NewCounterVec("zalora_scheduler_runs_total",
+		"Number of runs of the scheduler emitting events of scheduled products, by result (success/error)", "result")
+	SchedulerEvents = NewCounterVec("zalora_scheduler_events_total",
+		"Number of events emitted by the scheduler, by event (product.published/product.unpublished)", "event")
+	ImageStorageErrors = NewCounterVec("zalora_image_storage_errors_total",
+		"Number of failed operations of the image storage, by operation (put/delete)", "operation")
+	SimilarityCacheRefreshes = NewCounterVec("zalora_similarity_cache_refreshes_total",
+		"Number of refreshes of the products compared by the similar products api, by result (success/error)",
+		"result")
+	AuthFailures = NewCounterVec("zalora_auth_failures_total",
+		"Number of requests that failed authentication, by reason", "reason")
+)
+
+func init() {
+	// State of mysql connection pool, read from sql.DB.Stats() at the time of scraping
+	NewGaugeFunc("zalora_mysql_max_open_connections", "Maximum number of open connections to mysql",
+		func() float64 { return float64(dbStats().MaxOpenConnections) })
+	NewGaugeFunc("zalora_mysql_open_connections", "Number of established connections to mysql, in use and idle",
+		func() float64 { return float64(dbStats().OpenConnections) })
+	NewGaugeFunc("zalora_mysql_in_use_connections", "Number of connections to mysql currently in use",
+		func() float64 { return float64(dbStats().InUse) })
+	NewGaugeFunc("zalora_mysql_idle_connections", "Number of idle connections to mysql",
+		func() float64 { return float64(dbStats().Idle) })
+	NewCounterFunc("zalora_mysql_wait_count_total", "Number of times a connection to mysql was waited for",
+		func() float64 { return float64(dbStats().WaitCount) })
+	NewCounterFunc("zalora_mysql_wait_duration_seconds_total", "Total time spent waiting for a connection to mysql",
+		func() float64 { return dbStats().WaitDuration.Seconds() })
+}
+
+func dbStats() sql.DBStats {
+	if mysqlc.MySqlDB == nil {
+		return sql.DBStats{}
+	}
+	return mysqlc.MySqlDB.Stats()
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"constants"
+)
+
+func Handler(ginContext *gin.Context) {
+	/*
+		To expose all metrics in prometheus text format
+		Sample Url: "http://host/metrics"
+		Request Method: GET
+	*/
+	ginContext.Status(http.StatusOK)
+	ginContext.Header("Content-Type", constants.MetricsContentType)
+	WriteText(ginContext.Writer)
+}
+
+func Instrument(ginContext *gin.Context) {
+	/*
+		Middleware to count http requests and measure their latency by method, route and status code
+		Route is the registered route (e.g. /bennjerry/:product_id/), to keep number of series bounded
+	*/
+	startTime := time.Now()
+	ginContext.Next()
+
+	route := ginContext.FullPath()
+	if route == "" {
+		// request didn't match any registered route, e.g. a 404 or a 405 of gin
+		route = constants.MetricsUnmatchedRoute
+	}
+	status := strconv.Itoa(ginContext.Writer.Status())
+	HTTPRequests.WithLabels(ginContext.Request.Method, route, status).Inc()
+	HTTPRequestDuration.WithLabels(ginContext.Request.Method, route, status).Observe(time.Since(startTime).Seconds())
+}
@@ -0,0 +1,281 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Anything that can write its samples in prometheus text exposition format
+type collector interface {
+	write(w io.Writer)
+}
+
+var (
+	registryMutex sync.Mutex
+	registry      = make([]collector, 0)
+)
+
+func register(c collector) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry = append(registry, c)
+}
+
+func WriteText(w io.Writer) {
+	/*
+		To write samples of all registered metrics in prometheus text exposition format (version 0.0.4)
+	*/
+	registryMutex.Lock()
+	collectors := append([]collector{}, registry...)
+	registryMutex.Unlock()
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// Monotonically increasing value, partitioned by label values
+type CounterVec struct {
+	help       string
+	labelNames []string
+	mutex      sync.Mutex
+	name       string
+	values     map[string]*counterValue
+}
+
+type counterValue struct {
+	labelValues []string
+	value       float64
+}
+
+// A single series of a CounterVec
+type Counter struct {
+	vec *CounterVec
+	key string
+}
+
+func NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	counterVec := &CounterVec{
+		help:       help,
+		labelNames: labelNames,
+		name:       name,
+		values:     make(map[string]*counterValue),
+	}
+	register(counterVec)
+	return counterVec
+}
+
+func (c *CounterVec) WithLabels(labelValues ...string) *Counter {
+	/*
+		To fetch the series for the given label values (in the order of label names), creating it if missing
+	*/
+	key := strings.Join(labelValues, "\xff")
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, exists := c.values[key]; !exists {
+		c.values[key] = &counterValue{labelValues: labelValues}
+	}
+	return &Counter{vec: c, key: key}
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+func (c *Counter) Add(value float64) {
+	if value < 0 {
+		return
+	}
+	c.vec.mutex.Lock()
+	defer c.vec.mutex.Unlock()
+	c.vec.values[c.key].value += value
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	writeHeader(w, c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.values) {
+		value := c.values[key]
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labelNames, value.labelValues, "", ""),
+			formatFloat(value.value))
+	}
+}
+
+// Distribution of observed values in cumulative buckets, partitioned by label values
+type HistogramVec struct {
+	buckets    []float64
+	help       string
+	labelNames []string
+	mutex      sync.Mutex
+	name       string
+	values     map[string]*histogramValue
+}
+
+type histogramValue struct {
+	bucketCounts []uint64
+	count        uint64
+	labelValues  []string
+	sum          float64
+}
+
+// A single series of a HistogramVec
+type Histogram struct {
+	vec *HistogramVec
+	key string
+}
+
+func NewHistogramVec(name string, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	sortedBuckets := append([]float64{}, buckets...)
+	sort.Float64s(sortedBuckets)
+	histogramVec := &HistogramVec{
+		buckets:    sortedBuckets,
+		help:       help,
+		labelNames: labelNames,
+		name:       name,
+		values:     make(map[string]*histogramValue),
+	}
+	register(histogramVec)
+	return histogramVec
+}
+
+func (h *HistogramVec) WithLabels(labelValues ...string) *Histogram {
+	/*
+		To fetch the series for the given label values (in the order of label names), creating it if missing
+	*/
+	key := strings.Join(labelValues, "\xff")
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if _, exists := h.values[key]; !exists {
+		h.values[key] = &histogramValue{
+			bucketCounts: make([]uint64, len(h.buckets)),
+			labelValues:  labelValues,
+		}
+	}
+	return &Histogram{vec: h, key: key}
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.vec.mutex.Lock()
+	defer h.vec.mutex.Unlock()
+	histogram := h.vec.values[h.key]
+	for index, upperBound := range h.vec.buckets {
+		if value <= upperBound {
+			histogram.bucketCounts[index]++
+		}
+	}
+	histogram.count++
+	histogram.sum += value
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	writeHeader(w, h.name, h.help, "histogram")
+	for _, key := range sortedKeys(h.values) {
+		value := h.values[key]
+		for index, upperBound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name,
+				formatLabels(h.labelNames, value.labelValues, "le", formatFloat(upperBound)),
+				value.bucketCounts[index])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labelNames, value.labelValues, "le", "+Inf"),
+			value.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labelNames, value.labelValues, "", ""),
+			formatFloat(value.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labelNames, value.labelValues, "", ""),
+			value.count)
+	}
+}
+
+// Value read from a function at the time of scraping, e.g. state of connection pool
+type GaugeFunc struct {
+	help      string
+	name      string
+	valueFunc func() float64
+	valueType string
+}
+
+func NewGaugeFunc(name string, help string, valueFunc func() float64) *GaugeFunc {
+	gaugeFunc := &GaugeFunc{help: help, name: name, valueFunc: valueFunc, valueType: "gauge"}
+	register(gaugeFunc)
+	return gaugeFunc
+}
+
+func NewCounterFunc(name string, help string, valueFunc func() float64) *GaugeFunc {
+	/*
+		Same as GaugeFunc but for values that only increase, e.g. total wait count of connection pool
+	*/
+	counterFunc := &GaugeFunc{help: help, name: name, valueFunc: valueFunc, valueType: "counter"}
+	register(counterFunc)
+	return counterFunc
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	writeHeader(w, g.name, g.help, g.valueType)
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.valueFunc()))
+}
+
+func writeHeader(w io.Writer, name string, help string, valueType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, strings.Replace(strings.Replace(help, "\\", "\\\\", -1), "\n", "\\n", -1))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, valueType)
+}
+
+func formatLabels(labelNames []string, labelValues []string, extraName string, extraValue string) string {
+	/*
+		To format label pairs as {name="value",...}, an extra label (e.g. le of histogram buckets) is appended
+	*/
+	pairs := make([]string, 0, len(labelNames)+1)
+	for index, labelName := range labelNames {
+		labelValue := ""
+		if index < len(labelValues) {
+			labelValue = labelValues[index]
+		}
+		pairs = append(pairs, labelName+"=\""+escapeLabelValue(labelValue)+"\"")
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+"=\""+extraValue+"\"")
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabelValue(value string) string {
+	value = strings.Replace(value, "\\", "\\\\", -1)
+	value = strings.Replace(value, "\"", "\\\"", -1)
+	return strings.Replace(value, "\n", "\\n", -1)
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys(values interface{}) []string {
+	keys := make([]string, 0)
+	switch typedValues := values.(type) {
+	case map[string]*counterValue:
+		for key := range typedValues {
+			keys = append(keys, key)
+		}
+	case map[string]*histogramValue:
+		for key := range typedValues {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
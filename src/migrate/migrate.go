@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+
+	"migration"
+	"mysqlc"
+)
+
+const usage = `Usage: migrate [-dir directory] command
+Commands:
+  up               apply all pending migrations
+  down [n]         revert last n applied migrations (default 1)
+  status           list migrations and whether they have been applied
+  to <version>     apply or revert migrations till schema is at version (0 reverts all)
+`
+
+func main() {
+	directory := flag.String("dir", "", "directory of migration scripts (default ./migrations)")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// reading migrations
+	var err error
+	if *directory == "" {
+		err = migration.Init()
+	} else {
+		migration.Migrations, err = migration.Load(*directory)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	// connecting to mysql
+	mysqlc.Init()
+	err = execute(context.Background(), flag.Arg(0), flag.Args()[1:])
+
+	// closing connection with mysql
+	mysqlc.DBClosing()
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func execute(ctx context.Context, command string, arguments []string) error {
+	/*
+		To run a migrate command against the database and print its outcome
+	*/
+	migrations := migration.Migrations
+	switch command {
+	case "up":
+		done, err := migration.Up(ctx, mysqlc.MySqlDB, migrations, math.MaxInt32)
+		printMigrations("Applied", done)
+		return err
+	case "down":
+		steps := 1
+		if len(arguments) > 0 {
+			value, err := strconv.Atoi(arguments[0])
+			if err != nil || value < 1 {
+				return fmt.Errorf("invalid number of migrations to revert: %s", arguments[0])
+			}
+			steps = value
+		}
+		targetVersion, err := versionBefore(ctx, migrations, steps)
+		if err != nil {
+			return err
+		}
+		done, err := migration.Down(ctx, mysqlc.MySqlDB, migrations, targetVersion)
+		printMigrations("Reverted", done)
+		return err
+	case "status":
+		statusList, err := migration.StatusList(ctx, mysqlc.MySqlDB, migrations)
+		if err != nil {
+			return err
+		}
+		for _, status := range statusList {
+			state := "pending"
+			if status.Applied {
+				state = "applied at " + status.AppliedAt
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Migration.Version, status.Migration.Name, state)
+		}
+		return nil
+	case "to":
+		if len(arguments) == 0 {
+			return fmt.Errorf("missing version")
+		}
+		version, err := strconv.Atoi(arguments[0])
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version: %s", arguments[0])
+		}
+		done, err := migration.To(ctx, mysqlc.MySqlDB, migrations, version)
+		printMigrations("Migrated", done)
+		return err
+	}
+	flag.Usage()
+	return fmt.Errorf("unknown command: %s", command)
+}
+
+func versionBefore(ctx context.Context, migrations []*migration.Migration, steps int) (int, error) {
+	/*
+		To find the version the schema will be at after reverting last 'steps' applied migrations
+	*/
+	statusList, err := migration.StatusList(ctx, mysqlc.MySqlDB, migrations)
+	if err != nil {
+		return 0, err
+	}
+	for index := len(statusList) - 1; index >= 0; index-- {
+		if !statusList[index].Applied {
+			continue
+		}
+		if steps == 0 {
+			return statusList[index].Migration.Version, nil
+		}
+		steps--
+	}
+	return 0, nil
+}
+
+func printMigrations(action string, migrations []*migration.Migration) {
+	if len(migrations) == 0 {
+		fmt.Println("Nothing to do")
+		return
+	}
+	for _, doneMigration := range migrations {
+		fmt.Printf("%s %04d_%s\n", action, doneMigration.Version, doneMigration.Name)
+	}
+}
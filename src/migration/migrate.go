@@ -0,0 +1,235 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"constants"
+)
+
+// State of a migration in the database, as reported by status
+type Status struct {
+	AppliedAt string
+	Applied   bool
+	Migration *Migration
+}
+
+func EnsureVersionTable(ctx context.Context, db *sql.DB) error {
+	/*
+		To create schema_version table, if it doesn't exist already
+		Every applied migration has a row in it, the highest version is the schema version of the database
+	*/
+	_, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+constants.MigrationVersionTableName+" ("+
+		" version int(11) NOT NULL, name varchar(255) NOT NULL,"+
+		" applied_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY (version)"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci")
+	return err
+}
+
+func CurrentVersion(ctx context.Context, db *sql.DB) (int, error) {
+	/*
+		To read schema version of the database, 0 if no migration has been applied or schema_version is missing
+	*/
+	var tableCount int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.tables"+
+		" WHERE table_schema = DATABASE() AND table_name = '"+constants.MigrationVersionTableName+"'").
+		Scan(&tableCount)
+	if err != nil || tableCount == 0 {
+		return 0, err
+	}
+	var version int
+	err = db.QueryRowContext(ctx, "SELECT IFNULL(MAX(version), 0) FROM "+
+		constants.MigrationVersionTableName).Scan(&version)
+	return version, err
+}
+
+func appliedVersions(ctx context.Context, db *sql.DB) (map[int]string, error) {
+	/*
+		To read all applied migrations
+		Return: map {version: applied at}
+	*/
+	applied := make(map[int]string)
+	rows, err := db.QueryContext(ctx, "SELECT version, applied_at FROM "+constants.MigrationVersionTableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			version   int
+			appliedAt string
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+func StatusList(ctx context.Context, db *sql.DB, migrations []*Migration) ([]*Status, error) {
+	/*
+		To report, for every migration, whether and when it has been applied
+	*/
+	if err := EnsureVersionTable(ctx, db); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	statusList := make([]*Status, 0, len(migrations))
+	for _, migration := range migrations {
+		appliedAt, isApplied := applied[migration.Version]
+		statusList = append(statusList, &Status{AppliedAt: appliedAt, Applied: isApplied, Migration: migration})
+	}
+	return statusList, nil
+}
+
+func Up(ctx context.Context, db *sql.DB, migrations []*Migration, targetVersion int) ([]*Migration, error) {
+	/*
+		To apply, in order of version, every migration not applied yet with version <= targetVersion
+		Return: Migrations applied, till the first one that failed
+	*/
+	if err := EnsureVersionTable(ctx, db); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	done := make([]*Migration, 0)
+	for _, migration := range migrations {
+		if _, isApplied := applied[migration.Version]; isApplied || migration.Version > targetVersion {
+			continue
+		}
+		if err := run(ctx, db, migration, true); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+func Down(ctx context.Context, db *sql.DB, migrations []*Migration, targetVersion int) ([]*Migration, error) {
+	/*
+		To revert, in reverse order of version, every applied migration with version > targetVersion
+		Return: Migrations reverted, till the first one that failed
+	*/
+	if err := EnsureVersionTable(ctx, db); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	done := make([]*Migration, 0)
+	for index := len(migrations) - 1; index >= 0; index-- {
+		migration := migrations[index]
+		if _, isApplied := applied[migration.Version]; !isApplied || migration.Version <= targetVersion {
+			continue
+		}
+		if err := run(ctx, db, migration, false); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+func To(ctx context.Context, db *sql.DB, migrations []*Migration, version int) ([]*Migration, error) {
+	/*
+		To bring the database to given version, applying or reverting migrations as needed
+		version 0 reverts all migrations
+	*/
+	if version != 0 && Find(migrations, version) == nil {
+		return nil, errors.New(constants.MigrationUnknownVersionMessage + strconv.Itoa(version))
+	}
+	currentVersion, err := CurrentVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if version >= currentVersion {
+		return Up(ctx, db, migrations, version)
+	}
+	return Down(ctx, db, migrations, version)
+}
+
+func Check(ctx context.Context, db *sql.DB) (int, error) {
+	/*
+		To check that every migration known to the server (Migrations) has been applied to the database
+		Returns schema version of the database, and an error if migrations are pending, including any migration
+		older than the schema version that was skipped, e.g. one merged after newer ones had been applied
+		A database ahead of the server isn't an error, e.g. while an older server is still being rolled out
+	*/
+	currentVersion, err := CurrentVersion(ctx, db)
+	if err != nil {
+		return currentVersion, err
+	}
+	applied := make(map[int]string)
+	if currentVersion > 0 {
+		if applied, err = appliedVersions(ctx, db); err != nil {
+			return currentVersion, err
+		}
+	}
+	if pending := Pending(Migrations, applied); len(pending) > 0 {
+		versions := make([]string, 0, len(pending))
+		for _, migration := range pending {
+			versions = append(versions, strconv.Itoa(migration.Version))
+		}
+		return currentVersion, errors.New(constants.MigrationPendingErrorMessage + strings.Join(versions, ", "))
+	}
+	return currentVersion, nil
+}
+
+func Pending(migrations []*Migration, applied map[int]string) []*Migration {
+	/*
+		To take migrations and the versions applied to the database, map {version: applied at}
+		Return: Migrations not applied yet, in order of version
+	*/
+	pending := make([]*Migration, 0)
+	for _, migration := range migrations {
+		if _, isApplied := applied[migration.Version]; !isApplied {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+func run(ctx context.Context, db *sql.DB, migration *Migration, up bool) error {
+	/*
+		To run up or down script of a migration and record it in schema_version, in a single transaction
+		** mysql commits DDL statements (CREATE, ALTER, DROP) implicitly, so a failing script may be left
+		partially applied and has to be fixed manually
+	*/
+	script := migration.Up
+	if !up {
+		script = migration.Down
+	}
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, statement := range statements(script) {
+		if _, err := txn.ExecContext(ctx, statement); err != nil {
+			txn.Rollback()
+			return errors.New(constants.MigrationStatementErrorMessage + strconv.Itoa(migration.Version) + "_" +
+				migration.Name + ": " + err.Error())
+		}
+	}
+	if up {
+		_, err = txn.ExecContext(ctx, "INSERT INTO "+constants.MigrationVersionTableName+" (version, name)"+
+			" VALUES ("+strconv.Itoa(migration.Version)+", '"+migration.Name+"')")
+	} else {
+		_, err = txn.ExecContext(ctx, "DELETE FROM "+constants.MigrationVersionTableName+
+			" WHERE version = "+strconv.Itoa(migration.Version))
+	}
+	if err != nil {
+		txn.Rollback()
+		return err
+	}
+	return txn.Commit()
+}
@@ -0,0 +1,132 @@
+package migration
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"constants"
+)
+
+// Versioned change to the schema, read from <version>_<name>.up.sql and <version>_<name>.down.sql
+type Migration struct {
+	Down    string
+	Name    string
+	Up      string
+	Version int
+}
+
+var (
+	// Migrations found in migrations directory by Init, sorted by version
+	Migrations []*Migration
+	// Version of the last migration, i.e. the schema version the server expects
+	LatestVersion int
+	// <version>_<name>.(up|down).sql
+	fileNameRegexp = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_]+)\.(up|down)\.sql$`)
+)
+
+func Init() error {
+	/*
+		To load migrations from the migrations directory of current working directory
+	*/
+	pwd, _ := os.Getwd()
+	migrations, err := Load(pwd + constants.MigrationsDirectoryPath)
+	if err != nil {
+		return err
+	}
+	Migrations = migrations
+	LatestVersion = 0
+	if len(migrations) > 0 {
+		LatestVersion = migrations[len(migrations)-1].Version
+	}
+	return nil
+}
+
+func Load(directory string) ([]*Migration, error) {
+	/*
+		To read all migration scripts in a directory
+		Every version needs both an up and a down script, files not matching the naming scheme are ignored
+		Return: Migrations sorted by version
+	*/
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+	migrationsMap := make(map[int]*Migration)
+	for _, file := range files {
+		matches := fileNameRegexp.FindStringSubmatch(file.Name())
+		if file.IsDir() || matches == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(matches[1])
+		script, err := ioutil.ReadFile(filepath.Join(directory, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migration, exists := migrationsMap[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: matches[2]}
+			migrationsMap[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, errors.New(constants.MigrationDuplicateVersionMessage + strconv.Itoa(version))
+		}
+		if matches[3] == "up" {
+			migration.Up = string(script)
+		} else {
+			migration.Down = string(script)
+		}
+	}
+	migrations := make([]*Migration, 0, len(migrationsMap))
+	for _, migration := range migrationsMap {
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			return nil, errors.New(constants.MigrationMissingScriptMessage + strconv.Itoa(migration.Version))
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func Find(migrations []*Migration, version int) *Migration {
+	/*
+		To find the migration with given version, nil if there is none
+	*/
+	for _, migration := range migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+	return nil
+}
+
+func statements(script string) []string {
+	/*
+		To split a script into statements, a statement ends with ';' at the end of a line
+		Lines starting with '--' are comments and are skipped
+	*/
+	statementList := make([]string, 0)
+	current := make([]string, 0)
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current = append(current, line)
+		if strings.HasSuffix(trimmed, ";") {
+			statement := strings.TrimSuffix(strings.TrimSpace(strings.Join(current, "\n")), ";")
+			statementList = append(statementList, statement)
+			current = current[:0]
+		}
+	}
+	if len(current) > 0 {
+		statementList = append(statementList, strings.TrimSpace(strings.Join(current, "\n")))
+	}
+	return statementList
+}
@@ -0,0 +1,83 @@
+package migration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	/*
+		Testing Scenario: Loading a directory with two migrations (out of order) and an unrelated file
+		Expectation: Both migrations sorted by version, with their statements split on ';' at end of line
+	*/
+	directory, err := ioutil.TempDir("", "migrations")
+	if err != nil {
+		t.Fatalf("Couldn't create directory %s\n", err.Error())
+	}
+	defer os.RemoveAll(directory)
+	files := map[string]string{
+		"0002_second.up.sql":           "-- comment\nALTER TABLE a\n  ADD COLUMN b int;\nCREATE TABLE c (id int);\n",
+		"0002_second.down.sql":         "DROP TABLE c;\nALTER TABLE a DROP COLUMN b;\n",
+		"0001_initial_schema.up.sql":   "CREATE TABLE a (id int);\n",
+		"0001_initial_schema.down.sql": "DROP TABLE a;\n",
+		"README.md":                    "not a migration",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(directory, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Couldn't write file %s\n", err.Error())
+		}
+	}
+
+	migrations, err := Load(directory)
+	if err != nil {
+		t.Fatalf("Expected migrations to load but got %s\n", err.Error())
+	}
+	if len(migrations) != 2 || migrations[0].Version != 1 || migrations[1].Version != 2 ||
+		migrations[1].Name != "second" {
+		t.Fatalf("Expected migrations 0001_initial_schema and 0002_second but got %v\n", migrations)
+	}
+	upStatements := statements(migrations[1].Up)
+	if len(upStatements) != 2 || upStatements[0] != "ALTER TABLE a\n  ADD COLUMN b int" ||
+		upStatements[1] != "CREATE TABLE c (id int)" {
+		t.Fatalf("Expected 2 statements but got %q\n", upStatements)
+	}
+}
+
+func TestLoadMissingDownScript(t *testing.T) {
+	/*
+		Testing Scenario: Loading a directory with a migration without down script
+		Expectation: Error
+	*/
+	directory, err := ioutil.TempDir("", "migrations")
+	if err != nil {
+		t.Fatalf("Couldn't create directory %s\n", err.Error())
+	}
+	defer os.RemoveAll(directory)
+	err = ioutil.WriteFile(filepath.Join(directory, "0001_initial_schema.up.sql"), []byte("CREATE TABLE a (id int);"),
+		0644)
+	if err != nil {
+		t.Fatalf("Couldn't write file %s\n", err.Error())
+	}
+	if _, err := Load(directory); err == nil {
+		t.Fatalf("Expected error for missing down script but got none\n")
+	}
+}
+
+func TestPending(t *testing.T) {
+	/*
+		Testing Scenario: Comparing migrations 1 to 4 with a database where 1, 2 and 4 are applied,
+		e.g. 3 was merged after 4 had been applied, then with all of them applied
+		Expectation: Migration 3 is pending although the schema version is the latest, then none is pending
+	*/
+	migrations := []*Migration{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 4}}
+	applied := map[int]string{1: "2019-10-01 00:00:00", 2: "2019-10-01 00:00:00", 4: "2019-10-02 00:00:00"}
+	if pending := Pending(migrations, applied); len(pending) != 1 || pending[0].Version != 3 {
+		t.Fatalf("Expected migration 3 to be pending but got %v\n", pending)
+	}
+	applied[3] = "2019-10-03 00:00:00"
+	if pending := Pending(migrations, applied); len(pending) != 0 {
+		t.Fatalf("Expected no migration to be pending but got %v\n", pending)
+	}
+}
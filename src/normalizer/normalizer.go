@@ -0,0 +1,105 @@
+package normalizer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"constants"
+)
+
+// Synonyms maps the key of a variant to the key of its canonical name, e.g. {"cacao": "cocoa"}
+var Synonyms = make(map[string]string)
+
+func Init() error {
+	/*
+		To load synonyms from the file set in VOCABULARY_SYNONYMS_FILE,
+		else from synonyms.json of current working directory
+		Names are matched without synonyms, if the file doesn't exist
+	*/
+	filePath := os.Getenv(constants.NormalizerSynonymsEnvVarName)
+	if filePath == "" {
+		pwd, _ := os.Getwd()
+		filePath = pwd + constants.NormalizerSynonymsFilePath
+	}
+	synonyms, err := LoadSynonyms(filePath)
+	if err != nil {
+		return err
+	}
+	Synonyms = synonyms
+	return nil
+}
+
+func LoadSynonyms(filePath string) (map[string]string, error) {
+	/*
+		To read a json file of {"variant": "canonical name"} pairs
+		Return: map of key of variant to key of canonical name, empty if the file doesn't exist
+	*/
+	synonyms := make(map[string]string)
+	fileBytes, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return synonyms, nil
+	} else if err != nil {
+		return nil, err
+	}
+	pairs := make(map[string]string)
+	if err := json.Unmarshal(fileBytes, &pairs); err != nil {
+		return nil, err
+	}
+	for variant, canonical := range pairs {
+		synonyms[fold(Name(variant))] = fold(Name(canonical))
+	}
+	return synonyms, nil
+}
+
+func Name(name string) string {
+	/*
+		To bring a name to the form it's stored in
+		Trims it, collapses runs of whitespace inside it into a single space and composes it as per Unicode NFC,
+		so that e.g. "Crème  brûlée " with decomposed accents is stored as "Crème brûlée"
+	*/
+	return norm.NFC.String(strings.Join(strings.Fields(name), " "))
+}
+
+func Key(name string) string {
+	/*
+		To take a name and return the key it's matched by
+		Names with the same key are the same entry, e.g. "Milk", "milk " and "MILK" are all "milk",
+		and a variant listed in the synonyms is its canonical name, e.g. "Cacao" is "cocoa"
+	*/
+	key := fold(Name(name))
+	if canonical, exists := Synonyms[key]; exists {
+		return canonical
+	}
+	return key
+}
+
+func Keys(nameList []string) []string {
+	/*
+		To take a list of names and return the list of their unique keys, in order of first occurrence
+	*/
+	keyMap := make(map[string]bool)
+	keys := make([]string, 0, len(nameList))
+	for _, name := range nameList {
+		key := Key(name)
+		if !keyMap[key] {
+			keyMap[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func fold(name string) string {
+	/*
+		To fold case of every letter, mapping through upper case first so that letters with more than one
+		lower case form (e.g. final sigma, long s) end up the same
+	*/
+	return strings.Map(func(r rune) rune {
+		return unicode.ToLower(unicode.ToUpper(r))
+	}, name)
+}
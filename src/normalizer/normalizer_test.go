@@ -0,0 +1,85 @@
+package normalizer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestName(t *testing.T) {
+	/*
+		Testing Scenario: Normalizing names with surrounding and repeated whitespace and with decomposed accents
+		Expectation: Names are trimmed, whitespace inside is a single space and accents are composed,
+		while case is kept
+	*/
+	names := map[string]string{
+		" Milk ":                         "Milk",
+		"Fair\tTrade  Cocoa\n":           "Fair Trade Cocoa",
+		"Cre\u0300me bru\u0302le\u0301e": "Cr\u00e8me br\u00fbl\u00e9e",
+	}
+	for name, expected := range names {
+		if normalized := Name(name); normalized != expected {
+			t.Fatalf("Expected %q to be normalized to %q but got %q\n", name, expected, normalized)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	/*
+		Testing Scenario: Matching variants of a name differing in case, whitespace, composition of accents,
+		and a synonym of it
+		Expectation: All of them have the same key, while a different name doesn't
+	*/
+	Synonyms = map[string]string{"cacao": "cocoa"}
+	defer func() {
+		Synonyms = make(map[string]string)
+	}()
+	for _, name := range []string{"Cocoa", "cocoa ", "  COCOA", "Cacao", "CACAO\t"} {
+		if key := Key(name); key != "cocoa" {
+			t.Fatalf("Expected key of %q to be \"cocoa\" but got %q\n", name, key)
+		}
+	}
+	if Key("Cre\u0300me") != Key("CR\u00c8ME") {
+		t.Fatalf("Expected composed and decomposed accents to have the same key\n")
+	}
+	if Key("Cocoa butter") == Key("Cocoa") {
+		t.Fatalf("Expected \"Cocoa butter\" not to have the key of \"Cocoa\"\n")
+	}
+	keys := Keys([]string{"Milk", "Cacao", "milk ", "Cocoa"})
+	if len(keys) != 2 || keys[0] != "milk" || keys[1] != "cocoa" {
+		t.Fatalf("Expected keys [milk cocoa] but got %v\n", keys)
+	}
+}
+
+func TestLoadSynonyms(t *testing.T) {
+	/*
+		Testing Scenario: Loading synonyms from a json file, from a missing file and from an invalid file
+		Expectation: Variants and canonical names are stored as keys, a missing file has no synonyms
+		and an invalid file is an error
+	*/
+	directory, err := ioutil.TempDir("", "synonyms")
+	if err != nil {
+		t.Fatalf("Couldn't create directory %s\n", err.Error())
+	}
+	defer os.RemoveAll(directory)
+	validFile, invalidFile := filepath.Join(directory, "valid.json"), filepath.Join(directory, "invalid.json")
+	if err := ioutil.WriteFile(validFile, []byte(`{" Soya ": "Soy", "CACAO": "Cocoa"}`), 0644); err != nil {
+		t.Fatalf("Couldn't write file %s\n", err.Error())
+	}
+	if err := ioutil.WriteFile(invalidFile, []byte(`["soya", "soy"]`), 0644); err != nil {
+		t.Fatalf("Couldn't write file %s\n", err.Error())
+	}
+
+	synonyms, err := LoadSynonyms(validFile)
+	if err != nil || len(synonyms) != 2 || synonyms["soya"] != "soy" || synonyms["cacao"] != "cocoa" {
+		t.Fatalf("Expected synonyms soya: soy, cacao: cocoa but got %v (%v)\n", synonyms, err)
+	}
+	synonyms, err = LoadSynonyms(filepath.Join(directory, "missing.json"))
+	if err != nil || len(synonyms) != 0 {
+		t.Fatalf("Expected no synonyms for a missing file but got %v (%v)\n", synonyms, err)
+	}
+	if _, err = LoadSynonyms(invalidFile); err == nil {
+		t.Fatalf("Expected an error for an invalid file\n")
+	}
+}
@@ -0,0 +1,101 @@
+package nutrition
+
+import (
+	"math"
+	"strings"
+
+	"constants"
+)
+
+// Nutrient of nutrition facts, with the unit its amounts are stored in and the units it can be given in
+type Nutrient struct {
+	Code string
+	// unit amounts are stored in, per 100 g
+	Unit string
+	// factors converting an amount in a unit to Unit, e.g. {"mg": 0.001} for a nutrient stored in g
+	Units map[string]float64
+	// largest possible amount per 100 g, in Unit
+	Max float64
+}
+
+var (
+	gramUnits = map[string]float64{
+		constants.NutritionUnitGram:      1,
+		constants.NutritionUnitMilligram: 0.001,
+	}
+	milligramUnits = map[string]float64{
+		constants.NutritionUnitMilligram: 1,
+		constants.NutritionUnitGram:      1000,
+	}
+	// Nutrients known to nutrition facts, in the order they are listed on the label
+	Nutrients = []*Nutrient{
+		{Code: "energy", Unit: constants.NutritionUnitKilocalory, Max: 900, Units: map[string]float64{
+			constants.NutritionUnitKilocalory: 1,
+			constants.NutritionUnitKilojoule:  1 / constants.NutritionKilojoulePerKilocalory,
+		}},
+		{Code: "fat", Unit: constants.NutritionUnitGram, Max: 100, Units: gramUnits},
+		{Code: "saturated_fat", Unit: constants.NutritionUnitGram, Max: 100, Units: gramUnits},
+		{Code: "trans_fat", Unit: constants.NutritionUnitGram, Max: 100, Units: gramUnits},
+		{Code: "cholesterol", Unit: constants.NutritionUnitMilligram, Max: 100000, Units: milligramUnits},
+		{Code: "sodium", Unit: constants.NutritionUnitMilligram, Max: 100000, Units: milligramUnits},
+		{Code: "carbohydrate", Unit: constants.NutritionUnitGram, Max: 100, Units: gramUnits},
+		{Code: "fiber", Unit: constants.NutritionUnitGram, Max: 100, Units: gramUnits},
+		{Code: "sugar", Unit: constants.NutritionUnitGram, Max: 100, Units: gramUnits},
+		{Code: "added_sugar", Unit: constants.NutritionUnitGram, Max: 100, Units: gramUnits},
+		{Code: "protein", Unit: constants.NutritionUnitGram, Max: 100, Units: gramUnits},
+		{Code: "salt", Unit: constants.NutritionUnitGram, Max: 100, Units: gramUnits},
+		{Code: "calcium", Unit: constants.NutritionUnitMilligram, Max: 100000, Units: milligramUnits},
+	}
+)
+
+func Lookup(code string) (*Nutrient, int, bool) {
+	/*
+		To find a nutrient by its code, in any case
+		Return: the nutrient and its position on the label
+	*/
+	code = strings.ToLower(strings.TrimSpace(code))
+	for position, nutrient := range Nutrients {
+		if nutrient.Code == code {
+			return nutrient, position, true
+		}
+	}
+	return nil, 0, false
+}
+
+func (n *Nutrient) Convert(amount float64, unit string) (float64, bool) {
+	/*
+		To convert an amount of the nutrient in a unit (in any case, Unit if empty) to Unit
+		Return: false, if the nutrient can't be given in the unit
+	*/
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if unit == "" {
+		unit = n.Unit
+	}
+	factor, exists := n.Units[unit]
+	if !exists {
+		return 0, false
+	}
+	return amount * factor, true
+}
+
+func Per100g(perServing float64, servingSize float64) float64 {
+	/*
+		To compute the amount per 100 g from the amount per serving of servingSize grams
+	*/
+	return Round(perServing * 100 / servingSize)
+}
+
+func PerServing(per100g float64, servingSize float64) float64 {
+	/*
+		To compute the amount per serving of servingSize grams from the amount per 100 g
+	*/
+	return Round(per100g * servingSize / 100)
+}
+
+func Round(amount float64) float64 {
+	/*
+		To round an amount to the decimals amounts are stored with
+	*/
+	scale := math.Pow(10, constants.NutritionDecimals)
+	return math.Round(amount*scale) / scale
+}
@@ -0,0 +1,73 @@
+package nutrition
+
+import (
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	/*
+		Testing Scenario: Looking up nutrients by code in other cases, and unknown ones
+		Expectation: Known nutrients are found with their position on the label, unknown ones aren't
+	*/
+	nutrient, position, exists := Lookup(" Sugar ")
+	if !exists || nutrient.Code != "sugar" || Nutrients[position] != nutrient {
+		t.Fatalf("Expected sugar to be found but got %v at %d\n", nutrient, position)
+	}
+	if _, energy, _ := Lookup("energy"); energy >= position {
+		t.Fatalf("Expected energy to be listed before sugar\n")
+	}
+	for _, code := range []string{"", "sugar_g", "vitamin c"} {
+		if _, _, exists := Lookup(code); exists {
+			t.Fatalf("Expected nutrient %q to be unknown\n", code)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	/*
+		Testing Scenario: Converting amounts of nutrients in their own unit, no unit, other units and invalid units
+		Expectation: Amounts in the unit of the nutrient, units it can't be given in are refused
+	*/
+	conversions := []struct {
+		code     string
+		amount   float64
+		unit     string
+		expected float64
+	}{
+		{"sugar", 21.5, "g", 21.5},
+		{"sugar", 21.5, "", 21.5},
+		{"sugar", 500, "MG", 0.5},
+		{"sodium", 0.15, "g", 150},
+		{"energy", 1046, "kJ", 250},
+	}
+	for _, conversion := range conversions {
+		nutrient, _, _ := Lookup(conversion.code)
+		amount, valid := nutrient.Convert(conversion.amount, conversion.unit)
+		if !valid || Round(amount) != conversion.expected {
+			t.Fatalf("Expected %v %s of %s to be %v but got %v (valid %v)\n", conversion.amount, conversion.unit,
+				conversion.code, conversion.expected, amount, valid)
+		}
+	}
+	sugar, _, _ := Lookup("sugar")
+	for _, unit := range []string{"kcal", "ml", "oz"} {
+		if _, valid := sugar.Convert(1, unit); valid {
+			t.Fatalf("Expected sugar not to be given in %s\n", unit)
+		}
+	}
+}
+
+func TestPerServing(t *testing.T) {
+	/*
+		Testing Scenario: Computing amounts per serving from amounts per 100 g and back
+		Expectation: Amounts in proportion to the serving size, rounded to 3 decimals
+	*/
+	if perServing := PerServing(20, 143); perServing != 28.6 {
+		t.Fatalf("Expected 28.6 per serving of 143 g but got %v\n", perServing)
+	}
+	if per100g := Per100g(28, 143); per100g != 19.580 {
+		t.Fatalf("Expected 19.58 per 100 g but got %v\n", per100g)
+	}
+	if per100g := Per100g(1, 3); per100g != 33.333 {
+		t.Fatalf("Expected 33.333 per 100 g but got %v\n", per100g)
+	}
+}
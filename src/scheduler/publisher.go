@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+
+	"bennjerry/structs"
+	"constants"
+	"logger"
+)
+
+// Publisher emits events of scheduled products to whoever has to know, e.g. marketing tools or caches
+// Logging them is the default, a webhook is used if SCHEDULER_WEBHOOK_URL is set, a queue implements the same interface
+type Publisher interface {
+	// Publish emits an event, an error leaves it to be emitted again on the next run
+	Publish(ctx context.Context, event *structs.ScheduleEventStruct) error
+}
+
+// Events is the publisher used by the scheduler, set by Init
+var Events Publisher = &LogPublisher{}
+
+func Init() {
+	/*
+		To publish events to the webhook set in SCHEDULER_WEBHOOK_URL, else only to the log
+	*/
+	if url := os.Getenv(constants.SchedulerWebhookURLEnvVarName); url != "" {
+		Events = &WebhookPublisher{URL: url, Client: &http.Client{Timeout: constants.SchedulerWebhookTimeout}}
+		return
+	}
+	Events = &LogPublisher{}
+}
+
+// LogPublisher writes events to the log, at info level
+type LogPublisher struct{}
+
+func (p *LogPublisher) Publish(ctx context.Context, event *structs.ScheduleEventStruct) error {
+	logger.FromContext(ctx).Info(constants.SchedulerLogBucketName, "scheduler.LogPublisher.Publish",
+		event.Event+" "+event.ProductId+" ("+event.Name+"), scheduled at "+event.ScheduledAt)
+	return nil
+}
+
+// WebhookPublisher posts events as json to an url, any status other than 2xx is an error
+type WebhookPublisher struct {
+	URL    string
+	Client *http.Client
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, event *structs.ScheduleEventStruct) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/json")
+	response, err := p.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return errors.New(constants.SchedulerWebhookStatusMessage + strconv.Itoa(response.StatusCode))
+	}
+	return nil
+}
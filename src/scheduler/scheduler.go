@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"bennjerry/model"
+	"bennjerry/structs"
+	"constants"
+	"logger"
+	"metrics"
+)
+
+var (
+	// To stop the running scheduler and to wait for its current run to finish
+	cancelScheduler context.CancelFunc
+	schedulerWg     sync.WaitGroup
+)
+
+func Start(interval time.Duration) {
+	/*
+		To emit events of products going live or expiring every interval, in background, till Stop is called
+		interval <= 0 disables the scheduler, products are still shown and hidden as per their schedule
+	*/
+	logIdentifier := "scheduler.Start"
+	if interval <= 0 {
+		logger.ZaloraStatsLogger.Info(constants.SchedulerLogBucketName, logIdentifier,
+			constants.SchedulerDisabledMessage)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelScheduler = cancel
+	schedulerWg.Add(1)
+	go func() {
+		defer schedulerWg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				RunOnce(ctx, time.Now())
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	logger.ZaloraStatsLogger.Info(constants.SchedulerLogBucketName, logIdentifier,
+		constants.SchedulerStartedMessage+", every "+interval.String())
+}
+
+func Stop() {
+	/*
+		To stop the scheduler, a run in progress is cancelled, events not emitted yet are emitted after restart
+	*/
+	if cancelScheduler != nil {
+		cancelScheduler()
+		schedulerWg.Wait()
+	}
+}
+
+func RunOnce(ctx context.Context, at time.Time) ([]*structs.ScheduleEventStruct, bool) {
+	/*
+		To emit events of products that went live or expired at or before a time once, through the publisher
+		Return: events emitted by this run
+	*/
+	logIdentifier := "scheduler.RunOnce"
+	emitted, success := model.EmitScheduleEvents(ctx, at, func(event *structs.ScheduleEventStruct) error {
+		return Events.Publish(ctx, event)
+	})
+	for _, event := range emitted {
+		metrics.SchedulerEvents.WithLabels(event.Event).Inc()
+	}
+	if !success {
+		metrics.SchedulerRuns.WithLabels(constants.SchedulerRunError).Inc()
+		logger.FromContext(ctx).Error(constants.SchedulerLogBucketName, logIdentifier,
+			constants.SchedulerRunErrorMessage, "emitted: "+strconv.Itoa(len(emitted)))
+		return emitted, false
+	}
+	metrics.SchedulerRuns.WithLabels(constants.SchedulerRunSuccess).Inc()
+	return emitted, true
+}
@@ -0,0 +1,106 @@
+package similarity
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"bennjerry/model"
+	"constants"
+	"logger"
+	"metrics"
+	"utils"
+)
+
+var (
+	// Index of live products last loaded, with the version of the catalog and the time it was loaded at
+	// Loading holds the mutex, so that concurrent requests wait for a single refresh
+	cacheMutex    sync.Mutex
+	cachedIndex   *Index
+	cachedAt      time.Time
+	cachedVersion uint64
+	cacheTTL      = utils.GetEnvSeconds(constants.SimilarityCacheTTLEnvVarName, constants.SimilarityCacheTTL)
+)
+
+func Similar(ctx context.Context, productId string, limit int) ([]*Match, bool, bool) {
+	/*
+		To rank the live products most similar to a live product, at most limit of them
+		Return: found: false, if the product isn't live; success: false, if products couldn't be loaded
+	*/
+	index, success := current(ctx)
+	if !success {
+		return nil, true, false
+	}
+	matches, found := index.Similar(productId, limit)
+	return matches, found, true
+}
+
+func current(ctx context.Context) (*Index, bool) {
+	/*
+		To return the cached index, refreshed if this server changed the catalog since it was loaded or it expired
+		Products going live or changed by other servers are compared once the index expires
+	*/
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+	// Reading the version before loading, so that changes committed while loading refresh it again
+	version := model.CatalogVersion()
+	if cachedIndex != nil && cachedVersion == version && (cacheTTL <= 0 || time.Since(cachedAt) < cacheTTL) {
+		return cachedIndex, true
+	}
+	index, success := load(ctx)
+	if !success {
+		metrics.SimilarityCacheRefreshes.WithLabels(constants.SimilarityRefreshError).Inc()
+		logger.FromContext(ctx).Error(constants.SimilarityLogBucketName, "similarity.current",
+			constants.SimilarityRefreshErrorMessage, "version: "+strconv.FormatUint(version, 10))
+		return nil, false
+	}
+	metrics.SimilarityCacheRefreshes.WithLabels(constants.SimilarityRefreshSuccess).Inc()
+	cachedIndex, cachedAt, cachedVersion = index, time.Now(), version
+	return index, true
+}
+
+func load(ctx context.Context) (*Index, bool) {
+	/*
+		To load live products with ids of their ingredients and dietary certifications and index them
+	*/
+	products, success := model.SelectFromProductByCategoryAndTag(ctx, nil, "")
+	if !success {
+		return nil, false
+	}
+	ingredients, success := model.SelectLiveProductVocabularyIds(ctx, model.Vocabularies["ingredients"])
+	if !success {
+		return nil, false
+	}
+	certifications, success := model.SelectLiveProductVocabularyIds(ctx,
+		model.Vocabularies["dietary_certifications"])
+	if !success {
+		return nil, false
+	}
+	itemMap := make(map[int]*Item)
+	items := make([]*Item, 0, len(products))
+	for _, product := range products {
+		item := &Item{
+			Id:             product.Id,
+			ProductId:      product.ProductId,
+			Name:           product.Name,
+			Ingredients:    make(map[int]bool),
+			Certifications: make(map[int]bool),
+		}
+		itemMap[product.Id] = item
+		items = append(items, item)
+	}
+	// Products going live or expiring between the queries are compared without some of their entries
+	// till the next refresh
+	for _, ingredient := range ingredients {
+		if item := itemMap[ingredient.ProductId]; item != nil {
+			item.Ingredients[ingredient.PropertyId] = true
+		}
+	}
+	for _, certification := range certifications {
+		if item := itemMap[certification.ProductId]; item != nil {
+			item.Certifications[certification.PropertyId] = true
+		}
+	}
+	return NewIndex(items), true
+}
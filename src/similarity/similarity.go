@@ -0,0 +1,97 @@
+package similarity
+
+import (
+	"sort"
+
+	"constants"
+)
+
+// Live product compared to find similar products, with ids of the entries it uses
+type Item struct {
+	Id             int
+	ProductId      string
+	Name           string
+	Ingredients    map[int]bool
+	Certifications map[int]bool
+}
+
+// Product similar to another, with its score, higher is more similar
+type Match struct {
+	Item  *Item
+	Score float64
+}
+
+// Items compared, by product_id
+type Index struct {
+	items       []*Item
+	byProductId map[string]*Item
+}
+
+func NewIndex(items []*Item) *Index {
+	/*
+		To index items by product_id, ordered by product_id so that ties are always ranked the same way
+	*/
+	index := &Index{items: append([]*Item{}, items...), byProductId: make(map[string]*Item)}
+	sort.Slice(index.items, func(i, j int) bool {
+		return index.items[i].ProductId < index.items[j].ProductId
+	})
+	for _, item := range index.items {
+		index.byProductId[item.ProductId] = item
+	}
+	return index
+}
+
+func (index *Index) Similar(productId string, limit int) ([]*Match, bool) {
+	/*
+		To rank the items most similar to the item of a product_id, at most limit of them
+		Items sharing nothing with it aren't listed, ties are ranked by product_id
+		Return: found: false, if no item has the product_id
+	*/
+	item := index.byProductId[productId]
+	if item == nil {
+		return nil, false
+	}
+	matches := make([]*Match, 0)
+	for _, other := range index.items {
+		if other == item {
+			continue
+		}
+		if score := Score(item, other); score > 0 {
+			matches = append(matches, &Match{Item: other, Score: score})
+		}
+	}
+	// Stable sort keeps items of the same score in order of product_id
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, true
+}
+
+func Score(item *Item, other *Item) float64 {
+	/*
+		To score how similar two items are, by the overlap of their ingredients and of their dietary certifications
+	*/
+	return constants.SimilarityIngredientWeight*Jaccard(item.Ingredients, other.Ingredients) +
+		constants.SimilarityCertificationWeight*Jaccard(item.Certifications, other.Certifications)
+}
+
+func Jaccard(ids map[int]bool, otherIds map[int]bool) float64 {
+	/*
+		To take two sets of ids and return the number of ids in both over the number of ids in any of them
+		e.g. 0.5 for {1, 2, 3} and {2, 3, 4}, 0 if both are empty
+	*/
+	shared := 0
+	for id := range ids {
+		if otherIds[id] {
+			shared++
+		}
+	}
+	all := len(ids) + len(otherIds) - shared
+	if all == 0 {
+		return 0
+	}
+	return float64(shared) / float64(all)
+}
@@ -0,0 +1,78 @@
+package similarity
+
+import (
+	"testing"
+)
+
+func TestJaccard(t *testing.T) {
+	/*
+		Testing Scenario: Comparing sets of ids that overlap, are equal, are disjoint and are empty
+		Expectation: Number of shared ids over the number of all ids, 0 if both are empty
+	*/
+	testCases := []struct {
+		name     string
+		ids      map[int]bool
+		otherIds map[int]bool
+		expected float64
+	}{
+		{"overlapping", map[int]bool{1: true, 2: true, 3: true}, map[int]bool{2: true, 3: true, 4: true}, 0.5},
+		{"equal", map[int]bool{1: true, 2: true}, map[int]bool{1: true, 2: true}, 1},
+		{"disjoint", map[int]bool{1: true}, map[int]bool{2: true}, 0},
+		{"one empty", map[int]bool{1: true}, map[int]bool{}, 0},
+		{"both empty", map[int]bool{}, nil, 0},
+	}
+	for _, testCase := range testCases {
+		if jaccard := Jaccard(testCase.ids, testCase.otherIds); jaccard != testCase.expected {
+			t.Errorf("%s: expected %v but got %v\n", testCase.name, testCase.expected, jaccard)
+		}
+	}
+}
+
+func TestIndexSimilar(t *testing.T) {
+	/*
+		Testing Scenario: Ranking products similar to a product among products sharing all, some or none of its
+		ingredients and certifications, with a limit and for an unknown product
+		Expectation: Products are ranked by score then product_id, the product itself and products sharing nothing
+		aren't listed, at most limit of them are listed and an unknown product isn't found
+	*/
+	ids := func(values ...int) map[int]bool {
+		result := make(map[int]bool)
+		for _, value := range values {
+			result[value] = true
+		}
+		return result
+	}
+	index := NewIndex([]*Item{
+		{ProductId: "e", Ingredients: ids(1, 2), Certifications: ids(9)},
+		{ProductId: "a", Ingredients: ids(1, 2), Certifications: ids(9)},
+		{ProductId: "d", Ingredients: ids(1, 3)},
+		{ProductId: "c", Ingredients: ids(1, 2)},
+		{ProductId: "b", Ingredients: ids(1, 2)},
+		{ProductId: "f", Ingredients: ids(4), Certifications: ids(8)},
+	})
+
+	matches, found := index.Similar("a", 10)
+	expected := []string{"e", "b", "c", "d"}
+	if !found || len(matches) != len(expected) {
+		t.Fatalf("Expected %v to be similar but got %d products\n", expected, len(matches))
+	}
+	for i, match := range matches {
+		if match.Item.ProductId != expected[i] {
+			t.Fatalf("Expected %s at %d but got %s\n", expected[i], i, match.Item.ProductId)
+		}
+	}
+	if matches[0].Score != 1.5 || matches[1].Score != 1 || matches[3].Score != 1.0/3 {
+		t.Fatalf("Expected scores 1.5, 1, 1, 0.33 but got %v, %v, %v, %v\n", matches[0].Score, matches[1].Score,
+			matches[2].Score, matches[3].Score)
+	}
+
+	if matches, _ := index.Similar("a", 2); len(matches) != 2 || matches[1].Item.ProductId != "b" {
+		t.Fatalf("Expected e, b within the limit but got %d products\n", len(matches))
+	}
+	if matches, found := index.Similar("f", 10); !found || len(matches) != 0 {
+		t.Fatalf("Expected no product to be similar to f but got %d products\n", len(matches))
+	}
+	if _, found := index.Similar("unknown", 10); found {
+		t.Fatalf("Expected an unknown product not to be found\n")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -8,12 +9,19 @@ import (
 
 	"bennjerry/model"
 	"bennjerry/structs"
+	"constants"
 	"mysqlc"
+	"normalizer"
 )
 
 func main() {
 	// connecting to mysql
 	mysqlc.DBConnecting()
+	// loading synonyms, so that names are matched as they are by the server
+	if err := normalizer.Init(); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
 
 	// reading json file with list of IceCreamDataStruct
 	jsonFile, err := os.Open("icecream.json")
@@ -28,11 +36,63 @@ func main() {
 	umMarshalErr := json.Unmarshal(byteValue, &iceCreamData)
 	if umMarshalErr != nil {
 		fmt.Println(umMarshalErr.Error())
+	} else if productId, valid := validNutrition(iceCreamData); !valid {
+		fmt.Println(productId + ": " + constants.InvalidNutritionErrorMessage)
+	} else if productId, valid := validSchedule(iceCreamData); !valid {
+		fmt.Println(productId + ": " + constants.InvalidScheduleErrorMessage)
+	} else if productId, valid := validCategoriesAndTags(iceCreamData); !valid {
+		fmt.Println(productId + ": " + constants.InvalidCategoryErrorMessage + " / " + constants.InvalidTagErrorMessage)
 	} else {
+		// Categories missing along the paths are created, so a fresh catalog can be uploaded as it is
 		// Calling function to execute queries in an atomic transaction
-		model.InsertRecord(iceCreamData)
+		model.InsertRecord(context.Background(), iceCreamData)
 	}
 
 	// closing connection with mysql
 	mysqlc.DBClosing()
 }
+
+func validNutrition(iceCreamData []*structs.IceCreamDataStruct) (string, bool) {
+	/*
+		To validate nutrition facts of every product, converting amounts per serving and in other units
+		to amounts per 100 g in the unit of the nutrient, as they are stored
+		Return: productId of the first product with invalid nutrition facts
+	*/
+	for _, iceCream := range iceCreamData {
+		if !model.ValidateNutrition(iceCream.Nutrition) {
+			return iceCream.ProductId, false
+		}
+	}
+	return "", true
+}
+
+func validCategoriesAndTags(iceCreamData []*structs.IceCreamDataStruct) (string, bool) {
+	/*
+		To validate paths of categories and names of tags of every product
+		Return: productId of the first product with an invalid category path or tag
+	*/
+	for _, iceCream := range iceCreamData {
+		if !model.ValidateTags(iceCream.Tags) {
+			return iceCream.ProductId, false
+		}
+		for _, path := range iceCream.Categories {
+			if _, valid := model.SplitCategoryPath(path); !valid {
+				return iceCream.ProductId, false
+			}
+		}
+	}
+	return "", true
+}
+
+func validSchedule(iceCreamData []*structs.IceCreamDataStruct) (string, bool) {
+	/*
+		To validate publish_at and unpublish_at of every product
+		Return: productId of the first product with an invalid schedule
+	*/
+	for _, iceCream := range iceCreamData {
+		if !model.ValidateSchedule(iceCream) {
+			return iceCream.ProductId, false
+		}
+	}
+	return "", true
+}
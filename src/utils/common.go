@@ -3,6 +3,9 @@ package utils
 import (
 	"fmt"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -38,6 +41,17 @@ func (serializer *Serializer) ReturnOk(ginContext *gin.Context, result []byte) {
 	ginContext.Data(http.StatusOK, serializer.contentType, result)
 }
 
+func (serializer *Serializer) ReturnStatus(ginContext *gin.Context, code int, result []byte) {
+	/*
+		To send http response with response as []byte and a status code other than 200, e.g. 503
+	*/
+	if ginContext.IsAborted() {
+		return
+	}
+	ginContext.Abort()
+	ginContext.Data(code, serializer.contentType, result)
+}
+
 func (serializer *Serializer) ReturnError(ginContext *gin.Context, code int, sFmt string, v ...interface{}) {
 	/*
 		To send http error response with relevant error code and error response as []byte
@@ -86,3 +100,29 @@ func ListOfStringCompare(sl1 []string, sl2 []string) bool {
 	}
 	return true
 }
+
+func GetEnvInt(name string, defaultValue int) int {
+	/*
+		To read an integer from environment variable, defaultValue is returned if it's missing or invalid
+		Arguments: string, int
+		Returns: int
+	*/
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func GetEnvSeconds(name string, defaultValue time.Duration) time.Duration {
+	/*
+		To read a duration, in seconds, from environment variable, defaultValue is returned if it's missing or invalid
+		Arguments: string, time.Duration
+		Returns: time.Duration
+	*/
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return time.Duration(value) * time.Second
+}